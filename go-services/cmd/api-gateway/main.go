@@ -18,6 +18,8 @@ import (
 	"vnstock-hybrid/internal/handlers"
 	"vnstock-hybrid/internal/middleware"
 	"vnstock-hybrid/internal/services"
+	"vnstock-hybrid/pkg/calendar"
+	_ "vnstock-hybrid/pkg/synthetic" // registers the synthetic provider
 	"vnstock-hybrid/pkg/vnstock"
 )
 
@@ -44,9 +46,27 @@ func main() {
 		}
 	}
 
+	if cfg.MarketData.HolidaysFile != "" {
+		if err := calendar.LoadHolidays(cfg.MarketData.HolidaysFile); err != nil {
+			log.Fatalf("Failed to load holidays: %v", err)
+		}
+	}
+
 	// Initialize services
-	marketClient := vnstock.NewClient()
-	technicalSvc := services.NewTechnicalService(db, rdb, marketClient)
+	providerCfg, err := cfg.MarketData.ProviderConfig()
+	if err != nil {
+		log.Fatalf("Invalid market data configuration: %v", err)
+	}
+	if db != nil {
+		providerCfg.Composite.OnMismatch = services.NewMismatchRecorder(db)
+	}
+	marketData, err := vnstock.NewProvider(providerCfg)
+	if err != nil {
+		log.Fatalf("Failed to create market data provider: %v", err)
+	}
+	technicalSvc := services.NewTechnicalService(db, rdb, marketData)
+	fundamentalSvc := services.NewFundamentalService(db, marketData)
+	formulaSvc := services.NewFormulaService(db, technicalSvc)
 	sentimentClient := services.NewSentimentClient(cfg.Services.SentimentURL)
 
 	// Setup Gin
@@ -64,7 +84,7 @@ func main() {
 
 	// Health endpoints
 	r.GET("/health", handlers.HealthCheck(db, rdb))
-	r.GET("/ready", handlers.ReadinessCheck(db, rdb, sentimentClient))
+	r.GET("/ready", handlers.ReadinessCheck(db, rdb, sentimentClient, marketData))
 
 	// API v1
 	v1 := r.Group("/api/v1")
@@ -72,10 +92,29 @@ func main() {
 		// Technical analysis
 		v1.GET("/technical/:symbol", handlers.TechnicalAnalysis(technicalSvc))
 		v1.POST("/technical/batch", handlers.TechnicalBatch(technicalSvc))
+		v1.GET("/indicators", handlers.IndicatorDefinitions())
+
+		// Custom formulas
+		v1.GET("/formulas", handlers.ListFormulas(formulaSvc))
+		v1.POST("/formulas", handlers.SaveFormula(formulaSvc))
+		v1.GET("/formulas/functions", handlers.FormulaFunctions())
+		v1.POST("/formulas/evaluate", handlers.EvaluateExpression(formulaSvc, technicalSvc.Instruments()))
+		v1.GET("/formulas/:name", handlers.GetFormula(formulaSvc))
+		v1.DELETE("/formulas/:name", handlers.DeleteFormula(formulaSvc))
+		v1.GET("/formulas/:name/evaluate/:symbol", handlers.EvaluateFormula(formulaSvc, technicalSvc.Instruments()))
+
+		// Instrument master
+		v1.GET("/instruments", handlers.Instruments(technicalSvc.Instruments()))
+
+		// Financial statements and valuation
+		v1.GET("/fundamentals/:symbol", handlers.Fundamentals(fundamentalSvc, technicalSvc.Instruments()))
 
 		// Sentiment (proxy to Python)
 		v1.POST("/sentiment", handlers.SentimentProxy(sentimentClient))
 
+		// Trading calendar
+		v1.GET("/calendar", handlers.MarketCalendar())
+
 		// Combined analysis
 		v1.POST("/analyze", handlers.FullAnalysis(technicalSvc, sentimentClient))
 	}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"flag"
+	"log"
+	"net/http"
+
+	"vnstock-hybrid/pkg/realtime"
+)
+
+// sample is a synthetic half hour of HOSE trading, served when no file is given
+//
+//go:embed sample.jsonl
+var sample []byte
+
+func main() {
+	addr := flag.String("addr", ":8090", "listen address")
+	file := flag.String("file", "", "recorded tick file, one JSON message per line (default: built-in sample)")
+	speed := flag.Float64("speed", 1, "replay speed, 1 for real time, 0 for no delay")
+	loop := flag.Bool("loop", true, "restart the recording when it ends")
+	retime := flag.Bool("retime", true, "shift timestamps so the recording starts now")
+	flag.Parse()
+
+	var messages []realtime.Message
+	var err error
+	if *file != "" {
+		messages, err = realtime.LoadRecording(*file)
+	} else {
+		messages, err = realtime.ReadRecording(bytes.NewReader(sample))
+	}
+	if err != nil {
+		log.Fatalf("Failed to load recording: %v", err)
+	}
+
+	server := realtime.NewReplayServer(messages, realtime.ReplayOptions{
+		Speed:  *speed,
+		Loop:   *loop,
+		Retime: *retime,
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/ws", server)
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"healthy","service":"replay-server"}`))
+	})
+
+	log.Printf("Replay server streaming %d messages on ws://localhost%s/ws", len(messages), *addr)
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		log.Fatalf("Server error: %v", err)
+	}
+}
@@ -17,6 +17,9 @@ import (
 	"vnstock-hybrid/internal/database"
 	"vnstock-hybrid/internal/handlers"
 	"vnstock-hybrid/internal/services"
+	"vnstock-hybrid/pkg/calendar"
+	"vnstock-hybrid/pkg/realtime"
+	_ "vnstock-hybrid/pkg/synthetic" // registers the synthetic provider
 	"vnstock-hybrid/pkg/vnstock"
 )
 
@@ -43,9 +46,49 @@ func main() {
 		}
 	}
 
+	if cfg.MarketData.HolidaysFile != "" {
+		if err := calendar.LoadHolidays(cfg.MarketData.HolidaysFile); err != nil {
+			log.Fatalf("Failed to load holidays: %v", err)
+		}
+	}
+
 	// Initialize services
-	marketClient := vnstock.NewClient()
-	technicalSvc := services.NewTechnicalService(db, rdb, marketClient)
+	providerCfg, err := cfg.MarketData.ProviderConfig()
+	if err != nil {
+		log.Fatalf("Invalid market data configuration: %v", err)
+	}
+	if db != nil {
+		providerCfg.Composite.OnMismatch = services.NewMismatchRecorder(db)
+	}
+	marketData, err := vnstock.NewProvider(providerCfg)
+	if err != nil {
+		log.Fatalf("Failed to create market data provider: %v", err)
+	}
+	technicalSvc := services.NewTechnicalService(db, rdb, marketData)
+
+	// Daily bar sync after each trading session
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	if db != nil {
+		go services.NewSyncScheduler(db, marketData).Run(schedulerCtx)
+	}
+
+	// Live bars from the realtime feed
+	var ingestor *realtime.Ingestor
+	var live *services.LiveIndicators
+	if cfg.Realtime.FeedURL != "" {
+		resolutions, err := cfg.Realtime.ResolutionList()
+		if err != nil {
+			log.Fatalf("Invalid realtime configuration: %v", err)
+		}
+		feed := realtime.NewFeedClient(cfg.Realtime.FeedURL, cfg.Realtime.SymbolList(), cfg.Realtime.PriceScale)
+		ingestor = realtime.NewIngestor(feed, realtime.NewBarBuilder(resolutions, cfg.Realtime.History), realtime.NewHub())
+		go ingestor.Run(schedulerCtx)
+
+		// Indicators updated bar by bar from the live bars
+		live = services.NewLiveIndicators(rdb, technicalSvc)
+		go live.Run(schedulerCtx, ingestor.Hub())
+	}
 
 	// Setup Gin
 	if os.Getenv("GIN_MODE") != "debug" {
@@ -68,10 +111,14 @@ func main() {
 	r.GET("/analyze/:symbol", handlers.TechnicalAnalysis(technicalSvc))
 	r.POST("/analyze/batch", handlers.TechnicalBatch(technicalSvc))
 
+	if ingestor != nil {
+		r.GET("/realtime/:symbol", handlers.LiveBars(ingestor, technicalSvc.Instruments(), live))
+	}
+
 	// Internal API for other services
 	r.GET("/internal/indicators/:symbol", func(c *gin.Context) {
 		symbol := c.Param("symbol")
-		result, err := technicalSvc.Analyze(c.Request.Context(), symbol)
+		result, err := technicalSvc.Analyze(c.Request.Context(), symbol, services.AnalyzeOptions{})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
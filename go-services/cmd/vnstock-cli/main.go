@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"vnstock-hybrid/internal/config"
+	"vnstock-hybrid/internal/database"
+	"vnstock-hybrid/internal/services"
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+const usage = `Usage: vnstock-cli <command> [flags]
+
+Commands:
+  import   load a CSV or Parquet price file into the bar store
+  export   write stored bars to a CSV or Parquet file
+
+Run "vnstock-cli <command> -h" for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "import":
+		err = runImport(os.Args[2:])
+	case "export":
+		err = runExport(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatalf("%s failed: %v", os.Args[1], err)
+	}
+}
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	file := fs.String("file", "", "CSV or Parquet file to import (required)")
+	format := fs.String("format", "", "file format: csv or parquet (default: from file extension)")
+	symbol := fs.String("symbol", "", "symbol for files without a symbol column (default: file name)")
+	resolution := fs.String("resolution", string(vnstock.Resolution1D), "bar resolution of the file")
+	mapping := fs.String("map", "", "column mapping, e.g. date=Ngay,close=GiaDongCua")
+	skipInvalid := fs.Bool("skip-invalid", false, "skip invalid bars instead of aborting")
+	fs.Parse(args)
+
+	if *file == "" {
+		return fmt.Errorf("-file is required")
+	}
+
+	res, err := vnstock.ParseResolution(*resolution)
+	if err != nil {
+		return err
+	}
+
+	columns, err := vnstock.ParseColumnMapping(*mapping)
+	if err != nil {
+		return err
+	}
+
+	records, err := readRecords(*file, fileFormat(*file, *format), columns)
+	if err != nil {
+		return err
+	}
+
+	defaultSymbol := *symbol
+	if defaultSymbol == "" {
+		defaultSymbol = strings.ToUpper(strings.TrimSuffix(filepath.Base(*file), filepath.Ext(*file)))
+	}
+
+	// Group bars by symbol, validating OHLC consistency on the way
+	bySymbol := make(map[string][]vnstock.OHLCV)
+	var order []string
+	skipped := 0
+	for i, r := range records {
+		sym := strings.ToUpper(strings.TrimSpace(r.Symbol))
+		if sym == "" {
+			sym = defaultSymbol
+		}
+
+		if err := r.OHLCV.Validate(); err != nil {
+			if !*skipInvalid {
+				return fmt.Errorf("row %d (%s %s): %w", i+1, sym, r.Date.Format("2006-01-02"), err)
+			}
+			log.Printf("Skipping row %d (%s %s): %v", i+1, sym, r.Date.Format("2006-01-02"), err)
+			skipped++
+			continue
+		}
+
+		if _, ok := bySymbol[sym]; !ok {
+			order = append(order, sym)
+		}
+		bySymbol[sym] = append(bySymbol[sym], r.OHLCV)
+	}
+
+	bars, err := newBarStore()
+	if err != nil {
+		return err
+	}
+
+	// Stored bars of the same dates are replaced, so re-running a corrected
+	// file fixes them
+	ctx := context.Background()
+	total := 0
+	for _, sym := range order {
+		stored, err := bars.SaveBars(ctx, sym, res, bySymbol[sym])
+		if err != nil {
+			return err
+		}
+		log.Printf("%s: %d bars read, %d stored", sym, len(bySymbol[sym]), stored)
+		total += stored
+	}
+
+	log.Printf("Imported %d bars from %d rows (%d skipped)", total, len(records), skipped)
+	return nil
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	file := fs.String("file", "", "output CSV or Parquet file (required)")
+	format := fs.String("format", "", "file format: csv or parquet (default: from file extension)")
+	symbols := fs.String("symbol", "", "comma-separated symbols to export (required)")
+	resolution := fs.String("resolution", string(vnstock.Resolution1D), "bar resolution to export")
+	from := fs.String("from", "", "first date to export (YYYY-MM-DD)")
+	to := fs.String("to", "", "last date to export (YYYY-MM-DD)")
+	fs.Parse(args)
+
+	if *file == "" || *symbols == "" {
+		return fmt.Errorf("-file and -symbol are required")
+	}
+
+	res, err := vnstock.ParseResolution(*resolution)
+	if err != nil {
+		return err
+	}
+
+	fromDate, err := parseOptionalDate(*from)
+	if err != nil {
+		return err
+	}
+	toDate, err := parseOptionalDate(*to)
+	if err != nil {
+		return err
+	}
+	if !toDate.IsZero() {
+		// Include intraday bars of the last day
+		toDate = toDate.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	bars, err := newBarStore()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var records []vnstock.BarRecord
+	for _, sym := range strings.Split(*symbols, ",") {
+		sym = strings.ToUpper(strings.TrimSpace(sym))
+		data, err := bars.LoadRange(ctx, sym, res, fromDate, toDate)
+		if err != nil {
+			return err
+		}
+		for _, b := range data {
+			records = append(records, vnstock.BarRecord{Symbol: sym, OHLCV: b})
+		}
+	}
+
+	out, err := os.Create(*file)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	switch f := fileFormat(*file, *format); f {
+	case "csv":
+		err = vnstock.WriteCSV(out, records)
+	case "parquet":
+		err = vnstock.WriteParquet(out, records)
+	default:
+		err = fmt.Errorf("unsupported format %q", f)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Exported %d bars to %s", len(records), *file)
+	return out.Close()
+}
+
+func readRecords(path, format string, columns vnstock.ColumnMapping) ([]vnstock.BarRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch format {
+	case "csv":
+		return vnstock.ReadCSV(f, columns)
+	case "parquet":
+		info, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+		return vnstock.ReadParquet(f, info.Size(), columns)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// newBarStore connects to Postgres using the standard service configuration
+func newBarStore() (*services.BarSyncService, error) {
+	cfg := config.Load()
+	db, err := database.NewPostgresDB(cfg.Database)
+	if err != nil {
+		return nil, err
+	}
+	return services.NewBarSyncService(db, nil), nil
+}
+
+// fileFormat returns the explicit format, or the one implied by the file extension
+func fileFormat(path, format string) string {
+	if format != "" {
+		return strings.ToLower(format)
+	}
+	return strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+}
+
+func parseOptionalDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, vnstock.MarketLocation)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", s)
+	}
+	return t, nil
+}
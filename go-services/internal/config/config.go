@@ -1,17 +1,23 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
+
+	"vnstock-hybrid/pkg/vnstock"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	PubSub   PubSubConfig
-	Services ServicesConfig
+	Server     ServerConfig
+	Database   DatabaseConfig
+	Redis      RedisConfig
+	PubSub     PubSubConfig
+	Services   ServicesConfig
+	MarketData MarketDataConfig
+	Realtime   RealtimeConfig
 }
 
 type ServerConfig struct {
@@ -46,6 +52,47 @@ type ServicesConfig struct {
 	ForecastURL   string
 }
 
+// MarketDataConfig selects the market data provider: http, mock, synthetic, file or composite
+type MarketDataConfig struct {
+	Provider string
+	BaseURL  string
+	Timeout  time.Duration
+	DataDir  string
+	// HolidaysFile overrides the built-in exchange holiday list when set
+	HolidaysFile string
+
+	// Outbound resilience of the http provider
+	MaxAttempts      int
+	RetryBaseDelay   time.Duration
+	RetryMaxDelay    time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	RateLimit        float64
+	RateBurst        int
+
+	// Sources lists the sources of the composite provider, see vnstock.ParseSources
+	Sources         string
+	CompositeMode   string
+	CloseTolerance  float64
+	VolumeTolerance float64
+
+	// Seed makes the synthetic provider reproducible
+	Seed int64
+}
+
+// RealtimeConfig configures live tick ingestion; it is disabled without a feed URL
+type RealtimeConfig struct {
+	FeedURL string
+	// Symbols is a comma-separated subscription list; empty subscribes to the whole board
+	Symbols string
+	// Resolutions is a comma-separated list of live bar resolutions
+	Resolutions string
+	// PriceScale converts feed prices into VND
+	PriceScale float64
+	// History is the number of closed bars kept per symbol and resolution
+	History int
+}
+
 func Load() *Config {
 	return &Config{
 		Server: ServerConfig{
@@ -75,7 +122,103 @@ func Load() *Config {
 			SentimentURL:  getEnv("SENTIMENT_SERVICE_URL", "http://localhost:8000"),
 			ForecastURL:   getEnv("FORECAST_SERVICE_URL", "http://localhost:8082"),
 		},
+		MarketData: MarketDataConfig{
+			Provider:     getEnv("MARKET_DATA_PROVIDER", "http"),
+			BaseURL:      getEnv("MARKET_DATA_URL", "https://api.vietstock.vn/finance"),
+			Timeout:      getDurationEnv("MARKET_DATA_TIMEOUT", 30*time.Second),
+			DataDir:      getEnv("MARKET_DATA_DIR", "./data/market"),
+			HolidaysFile: getEnv("CALENDAR_HOLIDAYS_FILE", ""),
+
+			MaxAttempts:      getIntEnv("MARKET_DATA_MAX_ATTEMPTS", 4),
+			RetryBaseDelay:   getDurationEnv("MARKET_DATA_RETRY_BASE_DELAY", 500*time.Millisecond),
+			RetryMaxDelay:    getDurationEnv("MARKET_DATA_RETRY_MAX_DELAY", 30*time.Second),
+			BreakerThreshold: getIntEnv("MARKET_DATA_BREAKER_THRESHOLD", 5),
+			BreakerCooldown:  getDurationEnv("MARKET_DATA_BREAKER_COOLDOWN", 30*time.Second),
+			RateLimit:        getFloatEnv("MARKET_DATA_RATE_LIMIT", 10),
+			RateBurst:        getIntEnv("MARKET_DATA_RATE_BURST", 5),
+
+			Sources:         getEnv("MARKET_DATA_SOURCES", ""),
+			CompositeMode:   getEnv("MARKET_DATA_COMPOSITE_MODE", "failover"),
+			CloseTolerance:  getFloatEnv("MARKET_DATA_CLOSE_TOLERANCE", 0.5),
+			VolumeTolerance: getFloatEnv("MARKET_DATA_VOLUME_TOLERANCE", 5),
+
+			Seed: int64(getIntEnv("MARKET_DATA_SEED", 1)),
+		},
+		Realtime: RealtimeConfig{
+			FeedURL:     getEnv("REALTIME_FEED_URL", ""),
+			Symbols:     getEnv("REALTIME_SYMBOLS", ""),
+			Resolutions: getEnv("REALTIME_RESOLUTIONS", "1m,5m,15m,1h"),
+			PriceScale:  getFloatEnv("REALTIME_PRICE_SCALE", 1000),
+			History:     getIntEnv("REALTIME_HISTORY", 500),
+		},
+	}
+}
+
+// ProviderConfig builds the vnstock provider configuration
+func (c MarketDataConfig) ProviderConfig() (vnstock.ProviderConfig, error) {
+	cfg := vnstock.ProviderConfig{
+		Type:    c.Provider,
+		BaseURL: c.BaseURL,
+		Timeout: c.Timeout,
+		DataDir: c.DataDir,
+		Seed:    c.Seed,
+		Client: vnstock.ClientOptions{
+			Retry: vnstock.RetryPolicy{
+				MaxAttempts: c.MaxAttempts,
+				BaseDelay:   c.RetryBaseDelay,
+				MaxDelay:    c.RetryMaxDelay,
+			},
+			BreakerThreshold: c.BreakerThreshold,
+			BreakerCooldown:  c.BreakerCooldown,
+			RateLimit:        c.RateLimit,
+			RateBurst:        c.RateBurst,
+		},
+		Composite: vnstock.CompositeOptions{
+			Mode:            c.CompositeMode,
+			CloseTolerance:  c.CloseTolerance,
+			VolumeTolerance: c.VolumeTolerance,
+		},
+	}
+
+	if c.Provider == vnstock.ProviderComposite {
+		sources, err := vnstock.ParseSources(c.Sources)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Sources = sources
+	}
+
+	return cfg, nil
+}
+
+// SymbolList returns the subscribed symbols
+func (c RealtimeConfig) SymbolList() []string {
+	var symbols []string
+	for _, s := range strings.Split(c.Symbols, ",") {
+		if s = strings.ToUpper(strings.TrimSpace(s)); s != "" {
+			symbols = append(symbols, s)
+		}
 	}
+	return symbols
+}
+
+// ResolutionList parses the live bar resolutions, which must be intraday
+func (c RealtimeConfig) ResolutionList() ([]vnstock.Resolution, error) {
+	var resolutions []vnstock.Resolution
+	for _, s := range strings.Split(c.Resolutions, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		res, err := vnstock.ParseResolution(s)
+		if err != nil {
+			return nil, err
+		}
+		if !res.IsIntraday() {
+			return nil, fmt.Errorf("live bars must be intraday, got %s", res)
+		}
+		resolutions = append(resolutions, res)
+	}
+	return resolutions, nil
 }
 
 func getEnv(key, defaultValue string) string {
@@ -94,6 +237,15 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return defaultValue
+}
+
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if d, err := time.ParseDuration(value); err == nil {
@@ -0,0 +1,288 @@
+// Package formula implements a small expression language for custom
+// indicators and trading conditions, evaluated over price series with the
+// functions of the indicators package, e.g.
+//
+//	crossover(ema(close, 9), ema(close, 21)) and rsi(close, 14) < 40
+//
+// A formula is either a number or a condition. Every value is a series with
+// one point per bar; points where an indicator is still warming up are
+// invalid and make any condition depending on them invalid too.
+package formula
+
+import (
+	"math"
+	"sort"
+
+	"vnstock-hybrid/internal/indicators"
+)
+
+// Type is the type of a formula value
+type Type string
+
+const (
+	Number Type = "number"
+	Bool   Type = "bool"
+)
+
+// Formula is a parsed and type-checked expression
+type Formula struct {
+	source   string
+	root     node
+	typ      Type
+	lookback int
+}
+
+// Compile parses and type-checks a formula
+func Compile(src string) (*Formula, error) {
+	root, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+	typ, lookback, err := check(root)
+	if err != nil {
+		return nil, err
+	}
+	if lookback > maxLookback {
+		return nil, errorAt(root.position(), "formula needs %d bars of history, at most %d are allowed", lookback, maxLookback)
+	}
+	return &Formula{source: src, root: root, typ: typ, lookback: lookback}, nil
+}
+
+// String returns the source of the formula
+func (f *Formula) String() string {
+	return f.source
+}
+
+// Type returns whether the formula is a number or a condition
+func (f *Formula) Type() Type {
+	return f.typ
+}
+
+// Lookback returns how many bars the formula needs before its value is valid
+func (f *Formula) Lookback() int {
+	return f.lookback
+}
+
+// Eval evaluates the formula over price data. Conditions are 1 where true
+// and 0 where false; invalid points are NaN.
+func (f *Formula) Eval(in indicators.Inputs) indicators.Series {
+	return eval(f.root, in)
+}
+
+// Functions documents the built-in functions, sorted by name
+func Functions() []Function {
+	list := make([]Function, 0, len(functions))
+	for name, fn := range functions {
+		list = append(list, Function{Name: name, Signature: fn.doc, Returns: fn.result})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// check returns the type of an expression and the bars it looks back
+func check(n node) (Type, int, error) {
+	switch n := n.(type) {
+	case *numberNode:
+		return Number, 0, nil
+	case *boolNode:
+		return Bool, 0, nil
+	case *identNode:
+		if _, ok := inputs[n.name]; !ok {
+			return "", 0, errorAt(n.pos, "unknown input %q, expected open, high, low, close or volume", n.name)
+		}
+		return Number, 1, nil
+	case *unaryNode:
+		typ, lookback, err := check(n.x)
+		if err != nil {
+			return "", 0, err
+		}
+		want := Number
+		if n.op == "not" {
+			want = Bool
+		}
+		if typ != want {
+			return "", 0, errorAt(n.pos, "%s needs a %s, got a %s", n.op, want, typ)
+		}
+		return typ, lookback, nil
+	case *binaryNode:
+		return checkBinary(n)
+	case *callNode:
+		return checkCall(n)
+	}
+	return "", 0, errorAt(n.position(), "unsupported expression")
+}
+
+func checkBinary(n *binaryNode) (Type, int, error) {
+	x, xLookback, err := check(n.x)
+	if err != nil {
+		return "", 0, err
+	}
+	y, yLookback, err := check(n.y)
+	if err != nil {
+		return "", 0, err
+	}
+	lookback := max(xLookback, yLookback)
+
+	switch n.op {
+	case "and", "or":
+		if x != Bool || y != Bool {
+			return "", 0, errorAt(n.pos, "%s needs two conditions, got %s and %s", n.op, x, y)
+		}
+		return Bool, lookback, nil
+	case "==", "!=":
+		if x != y {
+			return "", 0, errorAt(n.pos, "cannot compare a %s with a %s", x, y)
+		}
+		return Bool, lookback, nil
+	case "<", "<=", ">", ">=":
+		if x != Number || y != Number {
+			return "", 0, errorAt(n.pos, "%s needs two numbers, got %s and %s", n.op, x, y)
+		}
+		return Bool, lookback, nil
+	}
+	if x != Number || y != Number {
+		return "", 0, errorAt(n.pos, "%s needs two numbers, got %s and %s", n.op, x, y)
+	}
+	return Number, lookback, nil
+}
+
+func checkCall(n *callNode) (Type, int, error) {
+	fn, ok := functions[n.name]
+	if !ok {
+		return "", 0, errorAt(n.pos, "unknown function %q", n.name)
+	}
+	if len(n.args) != len(fn.args) {
+		return "", 0, errorAt(n.pos, "%s takes %d arguments, got %d: %s", n.name, len(fn.args), len(n.args), fn.doc)
+	}
+
+	params := windowParams
+	var validate func([]float64) error
+	if fn.indicator != "" {
+		def, _ := indicators.Lookup(fn.indicator)
+		params, validate = def.Params, def.Validate
+	}
+
+	var lookback int
+	var consts []float64
+	for i, arg := range n.args {
+		if fn.args[i] == seriesArg {
+			typ, argLookback, err := check(arg)
+			if err != nil {
+				return "", 0, err
+			}
+			if typ != Number {
+				return "", 0, errorAt(arg.position(), "argument %d of %s must be a number, got a %s", i+1, n.name, typ)
+			}
+			lookback = max(lookback, argLookback)
+			continue
+		}
+
+		literal, ok := arg.(*numberNode)
+		if !ok {
+			return "", 0, errorAt(arg.position(), "argument %d of %s must be a number literal: %s", i+1, n.name, fn.doc)
+		}
+		if err := params[len(consts)].Check(literal.value); err != nil {
+			return "", 0, errorAt(arg.position(), "argument %d of %s: %v", i+1, n.name, err)
+		}
+		consts = append(consts, literal.value)
+	}
+
+	if validate != nil {
+		// Omitted trailing parameters take their defaults
+		full := make([]float64, len(params))
+		for i, p := range params {
+			full[i] = p.Default
+		}
+		copy(full, consts)
+		if err := validate(full); err != nil {
+			return "", 0, errorAt(n.pos, "%s: %v", n.name, err)
+		}
+	}
+
+	if fn.lookback != nil {
+		lookback += fn.lookback(consts)
+	}
+	return fn.result, lookback, nil
+}
+
+// eval computes an expression that has been checked
+func eval(n node, in indicators.Inputs) indicators.Series {
+	size := len(in.Closes)
+	switch n := n.(type) {
+	case *numberNode:
+		return constant(size, n.value)
+	case *boolNode:
+		return constant(size, truth(n.value))
+	case *identNode:
+		return inputs[n.name](in)
+	case *unaryNode:
+		x := eval(n.x, in)
+		if n.op == "not" {
+			return combine(x, x, func(a, _ float64) float64 { return 1 - a })
+		}
+		return combine(x, x, func(a, _ float64) float64 { return -a })
+	case *binaryNode:
+		return combine(eval(n.x, in), eval(n.y, in), operator(n.op))
+	case *callNode:
+		fn := functions[n.name]
+		var series []indicators.Series
+		var consts []float64
+		for i, arg := range n.args {
+			if fn.args[i] == seriesArg {
+				series = append(series, eval(arg, in))
+			} else {
+				consts = append(consts, arg.(*numberNode).value)
+			}
+		}
+		if result := fn.eval(in, series, consts); len(result) == size {
+			return result
+		}
+		// Too few bars for the indicator
+		return invalid(size)
+	}
+	return invalid(size)
+}
+
+// operator returns the point operation of a binary operator
+func operator(op string) func(a, b float64) float64 {
+	switch op {
+	case "+":
+		return func(a, b float64) float64 { return a + b }
+	case "-":
+		return func(a, b float64) float64 { return a - b }
+	case "*":
+		return func(a, b float64) float64 { return a * b }
+	case "/":
+		return func(a, b float64) float64 {
+			if b == 0 {
+				return math.NaN()
+			}
+			return a / b
+		}
+	case "<":
+		return func(a, b float64) float64 { return truth(a < b) }
+	case "<=":
+		return func(a, b float64) float64 { return truth(a <= b) }
+	case ">":
+		return func(a, b float64) float64 { return truth(a > b) }
+	case ">=":
+		return func(a, b float64) float64 { return truth(a >= b) }
+	case "==":
+		return func(a, b float64) float64 { return truth(a == b) }
+	case "!=":
+		return func(a, b float64) float64 { return truth(a != b) }
+	case "and":
+		return func(a, b float64) float64 { return truth(a == 1 && b == 1) }
+	default: // or
+		return func(a, b float64) float64 { return truth(a == 1 || b == 1) }
+	}
+}
+
+// constant returns a series of n copies of v
+func constant(n int, v float64) indicators.Series {
+	s := make(indicators.Series, n)
+	for i := range s {
+		s[i] = v
+	}
+	return s
+}
@@ -0,0 +1,170 @@
+package formula
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"vnstock-hybrid/internal/indicators"
+)
+
+// sine returns prices oscillating around 100, so averages cross repeatedly
+func sine(n int) indicators.Inputs {
+	in := indicators.Inputs{}
+	for i := 0; i < n; i++ {
+		price := 100 + 10*math.Sin(float64(i)/8)
+		in.Opens = append(in.Opens, price)
+		in.Highs = append(in.Highs, price+1)
+		in.Lows = append(in.Lows, price-1)
+		in.Closes = append(in.Closes, price)
+		in.Volumes = append(in.Volumes, 1000)
+	}
+	return in
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"close +",
+		"sma(close)",
+		"sma(close, 0)",
+		"sma(close, 2.5)",
+		"sma(close, high)",
+		"foo(close)",
+		"price > 10",
+		"close and true",
+		"not close",
+		"rsi(close, 14) < 30 or",
+		"(close > 1",
+		"close > 1 > 2",
+		"close # 2",
+		// Outside the bounds of the indicator registry
+		"psar(-1, 5)",
+		"psar(0.3, 0.2)",
+		"bb_upper(close, 1, -5)",
+		"rsi(close, 1)",
+		"macd(close, 26, 12)",
+		"highest(close, 1001)",
+		// Nested lookbacks add up
+		"sma(sma(sma(close, 1000), 1000), 1000)",
+	}
+	for _, src := range tests {
+		if _, err := Compile(src); err == nil {
+			t.Errorf("Compile(%q) succeeded, expected an error", src)
+		}
+	}
+}
+
+func TestErrorPositions(t *testing.T) {
+	// Positions count characters, not bytes
+	tests := map[string]string{
+		"é":             `position 1: unknown input "é", expected open, high, low, close or volume`,
+		"close ≥ 2":     `position 7: unexpected character '≥'`,
+		"é > close ≥ 2": `position 11: unexpected character '≥'`,
+	}
+	for src, want := range tests {
+		if _, err := Compile(src); err == nil || err.Error() != want {
+			t.Errorf("Compile(%q) = %v, want %s", src, err, want)
+		}
+	}
+}
+
+func TestFunctionsHaveParams(t *testing.T) {
+	for name, fn := range functions {
+		params := windowParams
+		if fn.indicator != "" {
+			def, ok := indicators.Lookup(fn.indicator)
+			if !ok {
+				t.Errorf("%s refers to unknown indicator %s", name, fn.indicator)
+				continue
+			}
+			params = def.Params
+		}
+		literals := 0
+		for _, kind := range fn.args {
+			if kind == paramArg {
+				literals++
+			}
+		}
+		if literals > len(params) {
+			t.Errorf("%s takes %d parameters, its indicator bounds %d", name, literals, len(params))
+		}
+	}
+}
+
+func TestOverflowIsInvalid(t *testing.T) {
+	f, err := Compile("close * 1" + strings.Repeat("0", 308))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.Eval(sine(5)); got.FirstValid() >= 0 {
+		t.Errorf("got %v, want overflowed points invalid", got)
+	}
+}
+
+func TestEvalMatchesIndicators(t *testing.T) {
+	in := sine(120)
+
+	f, err := Compile("ema(close, 9) - ema(close, 21)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Type() != Number || f.Lookback() != 22 {
+		t.Errorf("got type %s lookback %d, want number 22", f.Type(), f.Lookback())
+	}
+
+	got := f.Eval(in)
+	fast, slow := indicators.EMA(in.Closes, 9), indicators.EMA(in.Closes, 21)
+	for i := range got {
+		want := fast[i] - slow[i]
+		if indicators.IsValid(got[i]) != indicators.IsValid(want) || indicators.IsValid(want) && math.Abs(got[i]-want) > 1e-9 {
+			t.Fatalf("value[%d] = %v, want %v", i, got[i], want)
+		}
+	}
+}
+
+func TestEvalCondition(t *testing.T) {
+	in := sine(200)
+
+	f, err := Compile("crossover(ema(close,9), ema(close,21)) and rsi(close,14) > 50")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Type() != Bool {
+		t.Fatalf("got type %s, want bool", f.Type())
+	}
+
+	got := f.Eval(in)
+	if got.Valid(20) {
+		t.Errorf("condition valid at bar 20, before ema(close, 21) is")
+	}
+
+	fast, slow := indicators.EMA(in.Closes, 9), indicators.EMA(in.Closes, 21)
+	rsi := indicators.RSI(in.Closes, 14)
+	signals := 0
+	for i := 21; i < len(got); i++ {
+		want := fast[i] > slow[i] && fast[i-1] <= slow[i-1] && rsi[i] > 50
+		if (got[i] == 1) != want {
+			t.Errorf("condition[%d] = %v, want %v", i, got[i], want)
+		}
+		if want {
+			signals++
+		}
+	}
+	if signals == 0 {
+		t.Error("no crossover in a sine wave")
+	}
+}
+
+func TestNestedIndicatorsSkipWarmUp(t *testing.T) {
+	in := sine(100)
+
+	f, err := Compile("rsi(sma(close, 10), 14)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := f.Eval(in)
+	if first := got.FirstValid(); first != 9+14 {
+		t.Errorf("first valid value at %d, want %d", first, 9+14)
+	}
+}
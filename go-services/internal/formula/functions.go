@@ -0,0 +1,437 @@
+package formula
+
+import (
+	"math"
+
+	"vnstock-hybrid/internal/indicators"
+)
+
+// argKind is what a function accepts in one argument position
+type argKind int
+
+const (
+	// seriesArg is any number expression
+	seriesArg argKind = iota
+	// paramArg is a number literal within the bounds of its parameter
+	paramArg
+)
+
+// maxLookback bounds the bars a formula needs, nested calls included, so one
+// formula cannot demand years of minute bars
+const maxLookback = 1000
+
+// windowParams bound the window of the functions that are not indicators
+var windowParams = []indicators.Param{{Name: "n", Min: 1, Max: maxLookback, Integer: true}}
+
+// function is a built-in function of the formula language
+type function struct {
+	args []argKind
+	// indicator names the registry definition whose parameters, in order,
+	// bound the literal arguments; without one they are windowParams
+	indicator string
+	result    Type
+	// lookback returns the bars needed on top of those of the series
+	// arguments, from the constant arguments
+	lookback func(c []float64) int
+	// eval computes the result from the series and constant arguments, in
+	// the order they appear
+	eval func(in indicators.Inputs, x []indicators.Series, c []float64) indicators.Series
+	doc  string
+}
+
+// Function documents a built-in function
+type Function struct {
+	Name      string `json:"name"`
+	Signature string `json:"signature"`
+	Returns   Type   `json:"returns"`
+}
+
+// functions are the built-in functions by name
+var functions = map[string]function{
+	"sma": {
+		indicator: "sma", args: []argKind{seriesArg, paramArg}, result: Number, doc: "sma(x, n)",
+		lookback: periodLookback(0),
+		eval: func(_ indicators.Inputs, x []indicators.Series, c []float64) indicators.Series {
+			return warm(x[0], func(v []float64) indicators.Series { return indicators.SMA(v, int(c[0])) })
+		},
+	},
+	"ema": {
+		indicator: "ema", args: []argKind{seriesArg, paramArg}, result: Number, doc: "ema(x, n)",
+		lookback: periodLookback(0),
+		eval: func(_ indicators.Inputs, x []indicators.Series, c []float64) indicators.Series {
+			return warm(x[0], func(v []float64) indicators.Series { return indicators.EMA(v, int(c[0])) })
+		},
+	},
+	"rsi": {
+		indicator: "rsi", args: []argKind{seriesArg, paramArg}, result: Number, doc: "rsi(x, n)",
+		lookback: func(c []float64) int { return int(c[0]) + 1 },
+		eval: func(_ indicators.Inputs, x []indicators.Series, c []float64) indicators.Series {
+			return warm(x[0], func(v []float64) indicators.Series { return indicators.RSI(v, int(c[0])) })
+		},
+	},
+	"macd": {
+		indicator: "macd", args: []argKind{seriesArg, paramArg, paramArg}, result: Number, doc: "macd(x, fast, slow)",
+		lookback: periodLookback(1),
+		eval: func(_ indicators.Inputs, x []indicators.Series, c []float64) indicators.Series {
+			return warm(x[0], func(v []float64) indicators.Series {
+				fast, slow := indicators.EMA(v, int(c[0])), indicators.EMA(v, int(c[1]))
+				if fast == nil || slow == nil {
+					return nil
+				}
+				return combine(fast, slow, func(a, b float64) float64 { return a - b })
+			})
+		},
+	},
+	"macd_signal": {
+		indicator: "macd", args: []argKind{seriesArg, paramArg, paramArg, paramArg}, result: Number, doc: "macd_signal(x, fast, slow, signal)",
+		lookback: func(c []float64) int { return int(c[1] + c[2]) },
+		eval: func(_ indicators.Inputs, x []indicators.Series, c []float64) indicators.Series {
+			return warm(x[0], func(v []float64) indicators.Series {
+				if macd := indicators.CalculateMACDSeries(v, int(c[0]), int(c[1]), int(c[2])); macd != nil {
+					return macd.SignalLine
+				}
+				return nil
+			})
+		},
+	},
+	"macd_hist": {
+		indicator: "macd", args: []argKind{seriesArg, paramArg, paramArg, paramArg}, result: Number, doc: "macd_hist(x, fast, slow, signal)",
+		lookback: func(c []float64) int { return int(c[1] + c[2]) },
+		eval: func(_ indicators.Inputs, x []indicators.Series, c []float64) indicators.Series {
+			return warm(x[0], func(v []float64) indicators.Series {
+				if macd := indicators.CalculateMACDSeries(v, int(c[0]), int(c[1]), int(c[2])); macd != nil {
+					return macd.Histogram
+				}
+				return nil
+			})
+		},
+	},
+	"bb_upper": {
+		indicator: "bb", args: []argKind{seriesArg, paramArg, paramArg}, result: Number, doc: "bb_upper(x, n, k)",
+		lookback: periodLookback(0),
+		eval: func(_ indicators.Inputs, x []indicators.Series, c []float64) indicators.Series {
+			return warm(x[0], func(v []float64) indicators.Series {
+				if bb := indicators.CalculateBollingerBandsSeries(v, int(c[0]), c[1]); bb != nil {
+					return bb.Upper
+				}
+				return nil
+			})
+		},
+	},
+	"bb_lower": {
+		indicator: "bb", args: []argKind{seriesArg, paramArg, paramArg}, result: Number, doc: "bb_lower(x, n, k)",
+		lookback: periodLookback(0),
+		eval: func(_ indicators.Inputs, x []indicators.Series, c []float64) indicators.Series {
+			return warm(x[0], func(v []float64) indicators.Series {
+				if bb := indicators.CalculateBollingerBandsSeries(v, int(c[0]), c[1]); bb != nil {
+					return bb.Lower
+				}
+				return nil
+			})
+		},
+	},
+	"atr": {
+		indicator: "atr", args: []argKind{paramArg}, result: Number, doc: "atr(n)",
+		lookback: func(c []float64) int { return int(c[0]) + 1 },
+		eval: func(in indicators.Inputs, _ []indicators.Series, c []float64) indicators.Series {
+			return indicators.ATR(in.Highs, in.Lows, in.Closes, int(c[0]))
+		},
+	},
+	"adx": {
+		indicator: "adx", args: []argKind{paramArg}, result: Number, doc: "adx(n)",
+		lookback: func(c []float64) int { return 2 * int(c[0]) },
+		eval: func(in indicators.Inputs, _ []indicators.Series, c []float64) indicators.Series {
+			if adx := indicators.CalculateADXSeries(in.Highs, in.Lows, in.Closes, int(c[0])); adx != nil {
+				return adx.ADX
+			}
+			return nil
+		},
+	},
+	"plus_di": {
+		indicator: "adx", args: []argKind{paramArg}, result: Number, doc: "plus_di(n)",
+		lookback: func(c []float64) int { return 2 * int(c[0]) },
+		eval: func(in indicators.Inputs, _ []indicators.Series, c []float64) indicators.Series {
+			if adx := indicators.CalculateADXSeries(in.Highs, in.Lows, in.Closes, int(c[0])); adx != nil {
+				return adx.PlusDI
+			}
+			return nil
+		},
+	},
+	"minus_di": {
+		indicator: "adx", args: []argKind{paramArg}, result: Number, doc: "minus_di(n)",
+		lookback: func(c []float64) int { return 2 * int(c[0]) },
+		eval: func(in indicators.Inputs, _ []indicators.Series, c []float64) indicators.Series {
+			if adx := indicators.CalculateADXSeries(in.Highs, in.Lows, in.Closes, int(c[0])); adx != nil {
+				return adx.MinusDI
+			}
+			return nil
+		},
+	},
+	"stoch_k": {
+		indicator: "stoch", args: []argKind{paramArg}, result: Number, doc: "stoch_k(n)",
+		lookback: periodLookback(0),
+		eval: func(in indicators.Inputs, _ []indicators.Series, c []float64) indicators.Series {
+			if stoch := indicators.CalculateStochasticSeries(in.Highs, in.Lows, in.Closes, int(c[0]), 1); stoch != nil {
+				return stoch.K
+			}
+			return nil
+		},
+	},
+	"stoch_d": {
+		indicator: "stoch", args: []argKind{paramArg, paramArg}, result: Number, doc: "stoch_d(k, d)",
+		lookback: func(c []float64) int { return int(c[0] + c[1]) },
+		eval: func(in indicators.Inputs, _ []indicators.Series, c []float64) indicators.Series {
+			if stoch := indicators.CalculateStochasticSeries(in.Highs, in.Lows, in.Closes, int(c[0]), int(c[1])); stoch != nil {
+				return stoch.D
+			}
+			return nil
+		},
+	},
+	"vwap": {
+		result: Number, doc: "vwap()",
+		lookback: func(c []float64) int { return 1 },
+		eval: func(in indicators.Inputs, _ []indicators.Series, _ []float64) indicators.Series {
+			return indicators.VWAP(in.Highs, in.Lows, in.Closes, in.Volumes)
+		},
+	},
+	"psar": {
+		indicator: "psar", args: []argKind{paramArg, paramArg}, result: Number, doc: "psar(step, max_step)",
+		lookback: func(c []float64) int { return 2 },
+		eval: func(in indicators.Inputs, _ []indicators.Series, c []float64) indicators.Series {
+			if sar := indicators.CalculateParabolicSARSeries(in.Highs, in.Lows, c[0], c[1]); sar != nil {
+				return sar.SAR
+			}
+			return nil
+		},
+	},
+	"supertrend": {
+		indicator: "supertrend", args: []argKind{paramArg, paramArg}, result: Number, doc: "supertrend(n, k)",
+		lookback: func(c []float64) int { return int(c[0]) + 1 },
+		eval: func(in indicators.Inputs, _ []indicators.Series, c []float64) indicators.Series {
+			if st := indicators.CalculateSupertrendSeries(in.Highs, in.Lows, in.Closes, int(c[0]), c[1]); st != nil {
+				return st.Line
+			}
+			return nil
+		},
+	},
+	"keltner_upper": {
+		indicator: "keltner", args: []argKind{paramArg, paramArg, paramArg}, result: Number, doc: "keltner_upper(n, atr_n, k)",
+		lookback: func(c []float64) int { return max(int(c[0]), int(c[1])+1) },
+		eval: func(in indicators.Inputs, _ []indicators.Series, c []float64) indicators.Series {
+			if kc := indicators.CalculateKeltnerSeries(in.Highs, in.Lows, in.Closes, int(c[0]), int(c[1]), c[2]); kc != nil {
+				return kc.Upper
+			}
+			return nil
+		},
+	},
+	"keltner_lower": {
+		indicator: "keltner", args: []argKind{paramArg, paramArg, paramArg}, result: Number, doc: "keltner_lower(n, atr_n, k)",
+		lookback: func(c []float64) int { return max(int(c[0]), int(c[1])+1) },
+		eval: func(in indicators.Inputs, _ []indicators.Series, c []float64) indicators.Series {
+			if kc := indicators.CalculateKeltnerSeries(in.Highs, in.Lows, in.Closes, int(c[0]), int(c[1]), c[2]); kc != nil {
+				return kc.Lower
+			}
+			return nil
+		},
+	},
+	"vwma": {
+		indicator: "vwma", args: []argKind{paramArg}, result: Number, doc: "vwma(n)",
+		lookback: periodLookback(0),
+		eval: func(in indicators.Inputs, _ []indicators.Series, c []float64) indicators.Series {
+			return indicators.VWMA(in.Closes, in.Volumes, int(c[0]))
+		},
+	},
+	"obv": {
+		result: Number, doc: "obv()",
+		lookback: func(c []float64) int { return 1 },
+		eval: func(in indicators.Inputs, _ []indicators.Series, _ []float64) indicators.Series {
+			return indicators.OBV(in.Closes, in.Volumes)
+		},
+	},
+	"mfi": {
+		indicator: "mfi", args: []argKind{paramArg}, result: Number, doc: "mfi(n)",
+		lookback: func(c []float64) int { return int(c[0]) + 1 },
+		eval: func(in indicators.Inputs, _ []indicators.Series, c []float64) indicators.Series {
+			return indicators.MFI(in.Highs, in.Lows, in.Closes, in.Volumes, int(c[0]))
+		},
+	},
+	"ad": {
+		result: Number, doc: "ad()",
+		lookback: func(c []float64) int { return 1 },
+		eval: func(in indicators.Inputs, _ []indicators.Series, _ []float64) indicators.Series {
+			return indicators.AccumulationDistribution(in.Highs, in.Lows, in.Closes, in.Volumes)
+		},
+	},
+	"cmf": {
+		indicator: "cmf", args: []argKind{paramArg}, result: Number, doc: "cmf(n)",
+		lookback: periodLookback(0),
+		eval: func(in indicators.Inputs, _ []indicators.Series, c []float64) indicators.Series {
+			return indicators.CMF(in.Highs, in.Lows, in.Closes, in.Volumes, int(c[0]))
+		},
+	},
+	"highest": {
+		args: []argKind{seriesArg, paramArg}, result: Number, doc: "highest(x, n)",
+		lookback: periodLookback(0),
+		eval: func(_ indicators.Inputs, x []indicators.Series, c []float64) indicators.Series {
+			return rolling(x[0], int(c[0]), math.Max)
+		},
+	},
+	"lowest": {
+		args: []argKind{seriesArg, paramArg}, result: Number, doc: "lowest(x, n)",
+		lookback: periodLookback(0),
+		eval: func(_ indicators.Inputs, x []indicators.Series, c []float64) indicators.Series {
+			return rolling(x[0], int(c[0]), math.Min)
+		},
+	},
+	"prev": {
+		args: []argKind{seriesArg, paramArg}, result: Number, doc: "prev(x, n)",
+		lookback: periodLookback(0),
+		eval: func(_ indicators.Inputs, x []indicators.Series, c []float64) indicators.Series {
+			return shift(x[0], int(c[0]))
+		},
+	},
+	"change": {
+		args: []argKind{seriesArg, paramArg}, result: Number, doc: "change(x, n)",
+		lookback: periodLookback(0),
+		eval: func(_ indicators.Inputs, x []indicators.Series, c []float64) indicators.Series {
+			return combine(x[0], shift(x[0], int(c[0])), func(a, b float64) float64 { return a - b })
+		},
+	},
+	"abs": {
+		args: []argKind{seriesArg}, result: Number, doc: "abs(x)",
+		eval: func(_ indicators.Inputs, x []indicators.Series, _ []float64) indicators.Series {
+			return combine(x[0], x[0], func(a, _ float64) float64 { return math.Abs(a) })
+		},
+	},
+	"min": {
+		args: []argKind{seriesArg, seriesArg}, result: Number, doc: "min(a, b)",
+		eval: func(_ indicators.Inputs, x []indicators.Series, _ []float64) indicators.Series {
+			return combine(x[0], x[1], math.Min)
+		},
+	},
+	"max": {
+		args: []argKind{seriesArg, seriesArg}, result: Number, doc: "max(a, b)",
+		eval: func(_ indicators.Inputs, x []indicators.Series, _ []float64) indicators.Series {
+			return combine(x[0], x[1], math.Max)
+		},
+	},
+	"crossover": {
+		args: []argKind{seriesArg, seriesArg}, result: Bool, doc: "crossover(a, b)",
+		lookback: func(c []float64) int { return 1 },
+		eval: func(_ indicators.Inputs, x []indicators.Series, _ []float64) indicators.Series {
+			return cross(x[0], x[1])
+		},
+	},
+	"crossunder": {
+		args: []argKind{seriesArg, seriesArg}, result: Bool, doc: "crossunder(a, b)",
+		lookback: func(c []float64) int { return 1 },
+		eval: func(_ indicators.Inputs, x []indicators.Series, _ []float64) indicators.Series {
+			return cross(x[1], x[0])
+		},
+	},
+}
+
+// inputs are the price series a formula can refer to by name
+var inputs = map[string]func(in indicators.Inputs) indicators.Series{
+	"open":  func(in indicators.Inputs) indicators.Series { return in.Opens },
+	"high":  func(in indicators.Inputs) indicators.Series { return in.Highs },
+	"low":   func(in indicators.Inputs) indicators.Series { return in.Lows },
+	"close": func(in indicators.Inputs) indicators.Series { return in.Closes },
+	"volume": func(in indicators.Inputs) indicators.Series {
+		volumes := make(indicators.Series, len(in.Volumes))
+		for i, v := range in.Volumes {
+			volumes[i] = float64(v)
+		}
+		return volumes
+	},
+}
+
+// periodLookback looks back as many bars as the constant argument i
+func periodLookback(i int) func(c []float64) int {
+	return func(c []float64) int { return int(c[i]) }
+}
+
+// invalid returns a series of n invalid values
+func invalid(n int) indicators.Series {
+	s := make(indicators.Series, n)
+	for i := range s {
+		s[i] = math.NaN()
+	}
+	return s
+}
+
+// warm applies an indicator from the first valid value of x, so indicators
+// can be nested: the inner one's warm-up is skipped rather than poisoning the
+// outer one
+func warm(x indicators.Series, f func([]float64) indicators.Series) indicators.Series {
+	out := invalid(len(x))
+	first := x.FirstValid()
+	if first < 0 {
+		return out
+	}
+	if result := f(x[first:]); result != nil {
+		copy(out[first:], result)
+	}
+	return out
+}
+
+// combine applies op point by point; an invalid operand gives an invalid point
+func combine(a, b indicators.Series, op func(a, b float64) float64) indicators.Series {
+	out := make(indicators.Series, len(a))
+	for i := range a {
+		if !indicators.IsValid(a[i]) || !indicators.IsValid(b[i]) {
+			out[i] = math.NaN()
+			continue
+		}
+		out[i] = op(a[i], b[i])
+	}
+	return out
+}
+
+// shift returns x delayed by n bars
+func shift(x indicators.Series, n int) indicators.Series {
+	out := invalid(len(x))
+	for i := n; i < len(x); i++ {
+		out[i] = x[i-n]
+	}
+	return out
+}
+
+// rolling reduces each window of n values with op
+func rolling(x indicators.Series, n int, op func(a, b float64) float64) indicators.Series {
+	out := invalid(len(x))
+	for i := n - 1; i < len(x); i++ {
+		v := x[i-n+1]
+		for j := i - n + 2; j <= i && indicators.IsValid(v); j++ {
+			v = op(v, x[j])
+		}
+		if indicators.IsValid(v) && indicators.IsValid(x[i]) {
+			out[i] = v
+		}
+	}
+	return out
+}
+
+// cross is true on the bars where a moves from at or below b to above it
+func cross(a, b indicators.Series) indicators.Series {
+	out := invalid(len(a))
+	for i := 1; i < len(a); i++ {
+		points := []float64{a[i], b[i], a[i-1], b[i-1]}
+		valid := true
+		for _, v := range points {
+			valid = valid && indicators.IsValid(v)
+		}
+		if valid {
+			out[i] = truth(a[i] > b[i] && a[i-1] <= b[i-1])
+		}
+	}
+	return out
+}
+
+// truth encodes a boolean as a series value
+func truth(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
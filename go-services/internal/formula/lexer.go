@@ -0,0 +1,113 @@
+package formula
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenKind classifies a token of the formula language
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOp
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+// token is a lexeme with its character offset in the source
+type token struct {
+	kind  tokenKind
+	text  string
+	value float64
+	pos   int
+}
+
+// operators lists the symbolic operators, longest first so that <= is not
+// read as < followed by =
+var operators = []string{"<=", ">=", "==", "!=", "<", ">", "+", "-", "*", "/"}
+
+// lex splits a formula into tokens
+func lex(src string) ([]token, error) {
+	var tokens []token
+	// i is a byte offset into src and pos the character offset of the same place
+	for i, pos := 0, 0; i < len(src); {
+		c, size := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case unicode.IsSpace(c):
+			i, pos = i+size, pos+1
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: pos})
+			i, pos = i+1, pos+1
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: pos})
+			i, pos = i+1, pos+1
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: pos})
+			i, pos = i+1, pos+1
+		case unicode.IsDigit(c) || c == '.':
+			end, n := scan(src, i, func(c rune) bool { return unicode.IsDigit(c) || c == '.' })
+			value, err := strconv.ParseFloat(src[i:end], 64)
+			if err != nil {
+				return nil, errorAt(pos, "invalid number %q", src[i:end])
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: src[i:end], value: value, pos: pos})
+			i, pos = end, pos+n
+		case unicode.IsLetter(c) || c == '_':
+			end, n := scan(src, i, func(c rune) bool { return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' })
+			tokens = append(tokens, token{kind: tokenIdent, text: strings.ToLower(src[i:end]), pos: pos})
+			i, pos = end, pos+n
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, errorAt(pos, "unexpected character %q", c)
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: op, pos: pos})
+			// Operators are ASCII, one byte per character
+			i, pos = i+len(op), pos+len(op)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: utf8.RuneCountInString(src)}), nil
+}
+
+// scan returns the byte offset of the first character from offset i that is
+// not accepted, and how many characters were accepted
+func scan(src string, i int, accept func(rune) bool) (int, int) {
+	n := 0
+	for i < len(src) {
+		c, size := utf8.DecodeRuneInString(src[i:])
+		if !accept(c) {
+			break
+		}
+		i, n = i+size, n+1
+	}
+	return i, n
+}
+
+// Error is a syntax or type error in a formula
+type Error struct {
+	// Pos is the 1-based character position of the error in the source
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Pos, e.Msg)
+}
+
+// errorAt returns an error pointing at a character offset of the source
+func errorAt(pos int, format string, args ...interface{}) error {
+	return &Error{Pos: pos + 1, Msg: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,254 @@
+package formula
+
+// node is an expression of the syntax tree
+type node interface {
+	position() int
+}
+
+type numberNode struct {
+	value float64
+	pos   int
+}
+
+type boolNode struct {
+	value bool
+	pos   int
+}
+
+// identNode is a price input such as close
+type identNode struct {
+	name string
+	pos  int
+}
+
+type callNode struct {
+	name string
+	args []node
+	pos  int
+}
+
+type unaryNode struct {
+	op  string
+	x   node
+	pos int
+}
+
+type binaryNode struct {
+	op   string
+	x, y node
+	pos  int
+}
+
+func (n *numberNode) position() int { return n.pos }
+func (n *boolNode) position() int   { return n.pos }
+func (n *identNode) position() int  { return n.pos }
+func (n *callNode) position() int   { return n.pos }
+func (n *unaryNode) position() int  { return n.pos }
+func (n *binaryNode) position() int { return n.pos }
+
+// parser is a recursive descent parser. From lowest to highest precedence:
+// or, and, not, comparisons, + and -, * and /, unary minus.
+type parser struct {
+	tokens []token
+	next   int
+}
+
+// parse builds the syntax tree of a formula
+func parse(src string) (node, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, errorAt(tok.pos, "unexpected %q", tok.text)
+	}
+	return root, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	tok := p.tokens[p.next]
+	if tok.kind != tokenEOF {
+		p.next++
+	}
+	return tok
+}
+
+// keyword reports whether the next token is the identifier word
+func (p *parser) keyword(word string) bool {
+	tok := p.peek()
+	return tok.kind == tokenIdent && tok.text == word
+}
+
+func (p *parser) or() (node, error) {
+	x, err := p.and()
+	for err == nil && p.keyword("or") {
+		tok := p.advance()
+		var y node
+		if y, err = p.and(); err == nil {
+			x = &binaryNode{op: "or", x: x, y: y, pos: tok.pos}
+		}
+	}
+	return x, err
+}
+
+func (p *parser) and() (node, error) {
+	x, err := p.not()
+	for err == nil && p.keyword("and") {
+		tok := p.advance()
+		var y node
+		if y, err = p.not(); err == nil {
+			x = &binaryNode{op: "and", x: x, y: y, pos: tok.pos}
+		}
+	}
+	return x, err
+}
+
+func (p *parser) not() (node, error) {
+	if p.keyword("not") {
+		tok := p.advance()
+		x, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: "not", x: x, pos: tok.pos}, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (node, error) {
+	x, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind == tokenOp && isComparison(tok.text) {
+		p.advance()
+		y, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryNode{op: tok.text, x: x, y: y, pos: tok.pos}, nil
+	}
+	return x, nil
+}
+
+func (p *parser) sum() (node, error) {
+	x, err := p.product()
+	for err == nil {
+		tok := p.peek()
+		if tok.kind != tokenOp || (tok.text != "+" && tok.text != "-") {
+			break
+		}
+		p.advance()
+		var y node
+		if y, err = p.product(); err == nil {
+			x = &binaryNode{op: tok.text, x: x, y: y, pos: tok.pos}
+		}
+	}
+	return x, err
+}
+
+func (p *parser) product() (node, error) {
+	x, err := p.unary()
+	for err == nil {
+		tok := p.peek()
+		if tok.kind != tokenOp || (tok.text != "*" && tok.text != "/") {
+			break
+		}
+		p.advance()
+		var y node
+		if y, err = p.unary(); err == nil {
+			x = &binaryNode{op: tok.text, x: x, y: y, pos: tok.pos}
+		}
+	}
+	return x, err
+}
+
+func (p *parser) unary() (node, error) {
+	if tok := p.peek(); tok.kind == tokenOp && tok.text == "-" {
+		p.advance()
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		// Fold negative literals so they can be constant arguments
+		if n, ok := x.(*numberNode); ok {
+			return &numberNode{value: -n.value, pos: tok.pos}, nil
+		}
+		return &unaryNode{op: "-", x: x, pos: tok.pos}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (node, error) {
+	tok := p.advance()
+	switch tok.kind {
+	case tokenNumber:
+		return &numberNode{value: tok.value, pos: tok.pos}, nil
+	case tokenLParen:
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.advance(); closing.kind != tokenRParen {
+			return nil, errorAt(closing.pos, "expected )")
+		}
+		return x, nil
+	case tokenIdent:
+		switch tok.text {
+		case "true", "false":
+			return &boolNode{value: tok.text == "true", pos: tok.pos}, nil
+		case "and", "or", "not":
+			return nil, errorAt(tok.pos, "unexpected %q", tok.text)
+		}
+		if p.peek().kind != tokenLParen {
+			return &identNode{name: tok.text, pos: tok.pos}, nil
+		}
+		p.advance()
+		return p.call(tok)
+	case tokenEOF:
+		return nil, errorAt(tok.pos, "unexpected end of formula")
+	}
+	return nil, errorAt(tok.pos, "unexpected %q", tok.text)
+}
+
+// call parses the arguments of a function after its opening parenthesis
+func (p *parser) call(name token) (node, error) {
+	n := &callNode{name: name.text, pos: name.pos}
+	if p.peek().kind == tokenRParen {
+		p.advance()
+		return n, nil
+	}
+	for {
+		arg, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		n.args = append(n.args, arg)
+
+		switch tok := p.advance(); tok.kind {
+		case tokenComma:
+		case tokenRParen:
+			return n, nil
+		default:
+			return nil, errorAt(tok.pos, "expected , or ) in call to %s", name.text)
+		}
+	}
+}
+
+func isComparison(op string) bool {
+	switch op {
+	case "<", "<=", ">", ">=", "==", "!=":
+		return true
+	}
+	return false
+}
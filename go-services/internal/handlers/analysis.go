@@ -2,28 +2,76 @@ package handlers
 
 import (
 	"net/http"
-	"regexp"
 
 	"github.com/gin-gonic/gin"
 
+	"vnstock-hybrid/internal/indicators"
 	"vnstock-hybrid/internal/services"
+	"vnstock-hybrid/pkg/vnstock"
 )
 
-var symbolPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+// lookupSymbol resolves a symbol in the instrument master, answering 404 for
+// unknown symbols
+func lookupSymbol(c *gin.Context, instruments *services.InstrumentService, symbol string) (vnstock.SymbolInfo, bool) {
+	info, ok := instruments.Lookup(c.Request.Context(), symbol)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":  "unknown symbol",
+			"symbol": symbol,
+		})
+	}
+	return info, ok
+}
 
-// TechnicalAnalysis handles single symbol technical analysis
+// TechnicalAnalysis handles single symbol technical analysis.
+// Query parameters: resolution, price_mode, quality and indicators, a list of
+// extra indicator specs such as rsi(7),sma(200),bb(20,2.5).
 func TechnicalAnalysis(svc *services.TechnicalService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		symbol := c.Param("symbol")
 
-		if !symbolPattern.MatchString(symbol) {
+		if _, ok := lookupSymbol(c, svc.Instruments(), symbol); !ok {
+			return
+		}
+
+		resolution, err := vnstock.ParseResolution(c.DefaultQuery("resolution", string(vnstock.Resolution1D)))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		priceMode := c.DefaultQuery("price_mode", services.PriceModeAdjusted)
+		if !validPriceMode(priceMode) {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "invalid symbol format, expected 3 uppercase letters",
+				"error": "invalid price_mode, expected raw or adjusted",
 			})
 			return
 		}
 
-		result, err := svc.Analyze(c.Request.Context(), symbol)
+		quality := c.DefaultQuery("quality", vnstock.QualityRepair)
+		if !vnstock.ValidQualityMode(quality) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "invalid quality, expected flag, repair or reject",
+			})
+			return
+		}
+
+		specs, err := indicators.ParseSpecs(c.Query("indicators"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		result, err := svc.Analyze(c.Request.Context(), symbol, services.AnalyzeOptions{
+			Resolution: resolution,
+			PriceMode:  priceMode,
+			Quality:    quality,
+			Indicators: specs,
+		})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": err.Error(),
@@ -37,7 +85,12 @@ func TechnicalAnalysis(svc *services.TechnicalService) gin.HandlerFunc {
 
 // TechnicalBatchRequest represents batch analysis request
 type TechnicalBatchRequest struct {
-	Symbols []string `json:"symbols" binding:"required,min=1,max=50"`
+	Symbols    []string `json:"symbols" binding:"required,min=1,max=50"`
+	Resolution string   `json:"resolution"`
+	PriceMode  string   `json:"price_mode"`
+	Quality    string   `json:"quality"`
+	// Indicators lists extra indicator specs, e.g. "rsi(7),sma(200)"
+	Indicators string `json:"indicators"`
 }
 
 // TechnicalBatch handles batch technical analysis
@@ -54,16 +107,54 @@ func TechnicalBatch(svc *services.TechnicalService) gin.HandlerFunc {
 
 		// Validate symbols
 		for _, symbol := range req.Symbols {
-			if !symbolPattern.MatchString(symbol) {
+			if _, ok := svc.Instruments().Lookup(c.Request.Context(), symbol); !ok {
 				c.JSON(http.StatusBadRequest, gin.H{
-					"error":  "invalid symbol format",
+					"error":  "unknown symbol",
 					"symbol": symbol,
 				})
 				return
 			}
 		}
 
-		results, err := svc.AnalyzeBatch(c.Request.Context(), req.Symbols)
+		resolution := vnstock.Resolution1D
+		if req.Resolution != "" {
+			var err error
+			if resolution, err = vnstock.ParseResolution(req.Resolution); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+		}
+
+		if req.PriceMode != "" && !validPriceMode(req.PriceMode) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "invalid price_mode, expected raw or adjusted",
+			})
+			return
+		}
+
+		if req.Quality != "" && !vnstock.ValidQualityMode(req.Quality) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "invalid quality, expected flag, repair or reject",
+			})
+			return
+		}
+
+		specs, err := indicators.ParseSpecs(req.Indicators)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		results, err := svc.AnalyzeBatch(c.Request.Context(), req.Symbols, services.AnalyzeOptions{
+			Resolution: resolution,
+			PriceMode:  req.PriceMode,
+			Quality:    req.Quality,
+			Indicators: specs,
+		})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": err.Error(),
@@ -78,6 +169,10 @@ func TechnicalBatch(svc *services.TechnicalService) gin.HandlerFunc {
 	}
 }
 
+func validPriceMode(mode string) bool {
+	return mode == services.PriceModeRaw || mode == services.PriceModeAdjusted
+}
+
 // SentimentProxy proxies requests to the Python sentiment service
 func SentimentProxy(client *services.SentimentClient) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -125,7 +220,7 @@ func FullAnalysis(techSvc *services.TechnicalService, sentClient *services.Senti
 		ctx := c.Request.Context()
 
 		// Get technical analysis
-		techResults, err := techSvc.AnalyzeBatch(ctx, req.Symbols)
+		techResults, err := techSvc.AnalyzeBatch(ctx, req.Symbols, services.AnalyzeOptions{})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": err.Error(),
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"vnstock-hybrid/pkg/calendar"
+)
+
+// MarketCalendar reports whether an exchange trades at a given time.
+// Query parameters: exchange (default HOSE) and date (YYYY-MM-DD, default now).
+func MarketCalendar() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		exchange, err := calendar.ParseExchange(c.DefaultQuery("exchange", string(calendar.HOSE)))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		now := time.Now().In(calendar.Location)
+		if date := c.Query("date"); date != "" {
+			now, err = time.ParseInLocation("2006-01-02", date, calendar.Location)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date, expected YYYY-MM-DD"})
+				return
+			}
+		}
+
+		cal := calendar.For(exchange)
+		response := gin.H{
+			"exchange":             exchange,
+			"time":                 now,
+			"is_trading_day":       cal.IsTradingDay(now),
+			"is_open":              cal.IsOpen(now),
+			"next_open":            cal.NextOpen(now),
+			"previous_trading_day": cal.PreviousTradingDay(now).Format("2006-01-02"),
+			"next_trading_day":     cal.NextTradingDay(now).Format("2006-01-02"),
+		}
+		if s, ok := cal.SessionAt(now); ok {
+			response["session"] = s.Name
+		}
+
+		switch {
+		case now.Weekday() == time.Saturday || now.Weekday() == time.Sunday:
+			response["reason"] = "Weekend"
+		default:
+			if name, ok := cal.Holiday(now); ok {
+				response["reason"] = "Holiday: " + name
+			}
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
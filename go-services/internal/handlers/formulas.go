@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"vnstock-hybrid/internal/formula"
+	"vnstock-hybrid/internal/models"
+	"vnstock-hybrid/internal/services"
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+// FormulaRequest represents a formula to save
+type FormulaRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Expression  string `json:"expression" binding:"required"`
+	Description string `json:"description"`
+}
+
+// EvaluateRequest represents an expression to evaluate without saving it
+type EvaluateRequest struct {
+	Expression string `json:"expression" binding:"required"`
+	Symbol     string `json:"symbol" binding:"required"`
+	Resolution string `json:"resolution"`
+	PriceMode  string `json:"price_mode"`
+	Quality    string `json:"quality"`
+	Points     int    `json:"points"`
+}
+
+// SaveFormula validates and saves a named formula
+func SaveFormula(svc *services.FormulaService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req FormulaRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		f, err := svc.Save(c.Request.Context(), models.Formula{
+			Name:        req.Name,
+			Expression:  req.Expression,
+			Description: req.Description,
+		})
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, f)
+	}
+}
+
+// ListFormulas lists the saved formulas
+func ListFormulas(svc *services.FormulaService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		list, err := svc.List(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"formulas": list,
+			"count":    len(list),
+		})
+	}
+}
+
+// GetFormula returns a saved formula
+func GetFormula(svc *services.FormulaService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		f, err := svc.Get(c.Request.Context(), c.Param("name"))
+		if err != nil {
+			formulaError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, f)
+	}
+}
+
+// DeleteFormula removes a saved formula
+func DeleteFormula(svc *services.FormulaService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := svc.Delete(c.Request.Context(), c.Param("name")); err != nil {
+			formulaError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"deleted": c.Param("name")})
+	}
+}
+
+// EvaluateFormula evaluates a saved formula for a symbol.
+// Query parameters: resolution, price_mode, quality and points, the number of
+// recent values to return besides the latest (default 0).
+func EvaluateFormula(svc *services.FormulaService, instruments *services.InstrumentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		symbol := c.Param("symbol")
+		if _, ok := lookupSymbol(c, instruments, symbol); !ok {
+			return
+		}
+
+		points, err := strconv.Atoi(c.DefaultQuery("points", "0"))
+		if err != nil || points < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid points, expected a non-negative number"})
+			return
+		}
+		opts, ok := formulaOptions(c, c.Query("resolution"), c.Query("price_mode"), c.Query("quality"))
+		if !ok {
+			return
+		}
+
+		result, err := svc.EvaluateSaved(c.Request.Context(), c.Param("name"), symbol, opts, points)
+		if err != nil {
+			formulaError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// EvaluateExpression evaluates an unsaved expression for a symbol
+func EvaluateExpression(svc *services.FormulaService, instruments *services.InstrumentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req EvaluateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if req.Points < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid points, expected a non-negative number"})
+			return
+		}
+		if _, ok := lookupSymbol(c, instruments, req.Symbol); !ok {
+			return
+		}
+		opts, ok := formulaOptions(c, req.Resolution, req.PriceMode, req.Quality)
+		if !ok {
+			return
+		}
+
+		result, err := svc.Evaluate(c.Request.Context(), req.Expression, req.Symbol, opts, req.Points)
+		if err != nil {
+			formulaError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// FormulaFunctions lists the functions and inputs of the formula language
+func FormulaFunctions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		functions := formula.Functions()
+		c.JSON(http.StatusOK, gin.H{
+			"functions": functions,
+			"inputs":    []string{"open", "high", "low", "close", "volume"},
+			"count":     len(functions),
+		})
+	}
+}
+
+// formulaOptions validates the data options of an evaluation, answering 400
+// for invalid ones. Empty options take the analysis defaults.
+func formulaOptions(c *gin.Context, resolution, priceMode, quality string) (services.AnalyzeOptions, bool) {
+	opts := services.AnalyzeOptions{PriceMode: priceMode, Quality: quality}
+	if resolution != "" {
+		res, err := vnstock.ParseResolution(resolution)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return opts, false
+		}
+		opts.Resolution = res
+	}
+	if priceMode != "" && !validPriceMode(priceMode) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid price_mode, expected raw or adjusted"})
+		return opts, false
+	}
+	if quality != "" && !vnstock.ValidQualityMode(quality) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid quality, expected flag, repair or reject"})
+		return opts, false
+	}
+	return opts, true
+}
+
+// formulaError answers 404 for unknown formulas and 400 for invalid
+// expressions
+func formulaError(c *gin.Context, err error) {
+	var compileErr *formula.Error
+	switch {
+	case errors.Is(err, services.ErrFormulaNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "name": c.Param("name")})
+	case errors.As(err, &compileErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"vnstock-hybrid/internal/services"
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+// Fundamentals returns the financial statements of a stock and its valuation
+// ratios at the latest price
+func Fundamentals(svc *services.FundamentalService, instruments *services.InstrumentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		symbol := c.Param("symbol")
+
+		info, ok := lookupSymbol(c, instruments, symbol)
+		if !ok {
+			return
+		}
+		if info.Type != vnstock.InstrumentStock {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "fundamentals are only available for stocks",
+				"type":  info.Type,
+			})
+			return
+		}
+
+		result, err := svc.Get(c.Request.Context(), symbol)
+		if errors.Is(err, vnstock.ErrNoFinancials) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if err != nil {
+			// The statements could not be fetched or loaded
+			c.JSON(http.StatusBadGateway, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
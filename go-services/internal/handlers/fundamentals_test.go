@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"vnstock-hybrid/internal/services"
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+// financialsProvider serves the mock symbols but answers every request for
+// financial statements with err
+type financialsProvider struct {
+	*vnstock.MockProvider
+	err error
+}
+
+func (p financialsProvider) GetFinancials(ctx context.Context, symbol string, period vnstock.Period) ([]vnstock.FinancialReport, error) {
+	return nil, p.err
+}
+
+func TestFundamentalsStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"source down", errors.New("upstream unavailable"), http.StatusBadGateway},
+		{"no statements", vnstock.ErrNoFinancials, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		provider, err := vnstock.NewCompositeProvider([]vnstock.NamedProvider{
+			{Name: "primary", Provider: financialsProvider{MockProvider: vnstock.NewMockProvider(), err: tt.err}},
+		}, vnstock.CompositeOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		router := gin.New()
+		router.GET("/fundamentals/:symbol", Fundamentals(services.NewFundamentalService(nil, provider), services.NewInstrumentService(nil, provider)))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fundamentals/FPT", nil))
+		if w.Code != tt.want {
+			t.Errorf("%s: got status %d, want %d (%s)", tt.name, w.Code, tt.want, w.Body.String())
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"gorm.io/gorm"
 
 	"vnstock-hybrid/internal/services"
+	"vnstock-hybrid/pkg/vnstock"
 )
 
 // HealthCheck returns a basic health check handler
@@ -38,7 +39,7 @@ func HealthCheck(db *gorm.DB, rdb *redis.Client) gin.HandlerFunc {
 }
 
 // ReadinessCheck checks if the service is ready to accept requests
-func ReadinessCheck(db *gorm.DB, rdb *redis.Client, sentimentClient *services.SentimentClient) gin.HandlerFunc {
+func ReadinessCheck(db *gorm.DB, rdb *redis.Client, sentimentClient *services.SentimentClient, marketData vnstock.MarketDataProvider) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ready := true
 		checks := make(map[string]string)
@@ -74,6 +75,14 @@ func ReadinessCheck(db *gorm.DB, rdb *redis.Client, sentimentClient *services.Se
 			}
 		}
 
+		// Report market data circuit breakers. An open breaker does not fail
+		// readiness: analyses fall back to the local bar store.
+		if reporter, ok := marketData.(vnstock.BreakerReporter); ok {
+			for host, state := range reporter.BreakerStates() {
+				checks["market_data:"+host] = string(state)
+			}
+		}
+
 		status := http.StatusOK
 		if !ready {
 			status = http.StatusServiceUnavailable
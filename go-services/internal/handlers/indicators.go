@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"vnstock-hybrid/internal/indicators"
+)
+
+// IndicatorDefinitions lists the indicators that can be requested by spec,
+// with their parameters, inputs and outputs
+func IndicatorDefinitions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defs := indicators.Definitions()
+		c.JSON(http.StatusOK, gin.H{
+			"indicators": defs,
+			"count":      len(defs),
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+
+	"vnstock-hybrid/internal/services"
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+// Instruments lists the instrument master.
+// Query parameters: type (stock, etf, cw, future, index or bond; default all)
+// and all (true to include delisted instruments).
+func Instruments(instruments *services.InstrumentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var t vnstock.InstrumentType
+		if q := c.Query("type"); q != "" {
+			var err error
+			if t, err = vnstock.ParseInstrumentType(q); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		infos, err := instruments.List(c.Request.Context(), t, c.Query("all") != "true")
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		sort.Slice(infos, func(i, j int) bool { return infos[i].Symbol < infos[j].Symbol })
+
+		c.JSON(http.StatusOK, gin.H{
+			"instruments": infos,
+			"count":       len(infos),
+		})
+	}
+}
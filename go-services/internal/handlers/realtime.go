@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"vnstock-hybrid/internal/services"
+	"vnstock-hybrid/pkg/realtime"
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+// LiveBars returns the live bars built from the realtime feed for a symbol,
+// with its last trade, best bid and offer, and the indicators of the series.
+// Query parameters: resolution (default 1m).
+func LiveBars(ingestor *realtime.Ingestor, instruments *services.InstrumentService, live *services.LiveIndicators) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		symbol := c.Param("symbol")
+
+		if _, ok := lookupSymbol(c, instruments, symbol); !ok {
+			return
+		}
+
+		resolution, err := vnstock.ParseResolution(c.DefaultQuery("resolution", string(vnstock.Resolution1m)))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		built := false
+		for _, r := range ingestor.Builder().Resolutions() {
+			built = built || r == resolution
+		}
+		if !built {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":       "resolution is not built from the realtime feed",
+				"resolutions": ingestor.Builder().Resolutions(),
+			})
+			return
+		}
+
+		response := gin.H{
+			"symbol":     symbol,
+			"resolution": resolution,
+			"bars":       ingestor.Builder().Bars(symbol, resolution),
+		}
+		if trade, ok := ingestor.LastTrade(symbol); ok {
+			response["last_trade"] = trade
+		}
+		if quote, ok := ingestor.LatestQuote(symbol); ok {
+			response["quote"] = quote
+		}
+		if live != nil {
+			if snap, ok := live.Latest(symbol, resolution); ok {
+				response["indicators"] = snap
+			}
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,68 @@
+package indicators
+
+// moneyFlowMultiplier is where a bar closed within its range, from -1 at the
+// low to 1 at the high. A bar without range counts as neutral.
+func moneyFlowMultiplier(high, low, close float64) float64 {
+	if high == low {
+		return 0
+	}
+	return ((close - low) - (high - close)) / (high - low)
+}
+
+// AccumulationDistribution calculates the Accumulation/Distribution line: the
+// running total of volume weighted by where each bar closed within its range.
+// It starts on the first bar, so only its changes are meaningful.
+func AccumulationDistribution(highs, lows, closes []float64, volumes []int64) Series {
+	n := len(closes)
+	if n == 0 || len(highs) != n || len(lows) != n || len(volumes) != n {
+		return nil
+	}
+
+	ad := make(Series, n)
+	var total float64
+	for i := 0; i < n; i++ {
+		total += moneyFlowMultiplier(highs[i], lows[i], closes[i]) * float64(volumes[i])
+		ad[i] = total
+	}
+
+	return ad
+}
+
+// AccumulationDistributionLatest returns the most recent A/D value, NaN
+// without data
+func AccumulationDistributionLatest(highs, lows, closes []float64, volumes []int64) float64 {
+	return AccumulationDistribution(highs, lows, closes, volumes).Last()
+}
+
+// CMF calculates Chaikin Money Flow, the share of the volume of the last
+// period bars that flowed in (positive) or out (negative), from -1 to 1.
+// Windows without volume are invalid.
+func CMF(highs, lows, closes []float64, volumes []int64, period int) Series {
+	n := len(closes)
+	if n < period || len(highs) != n || len(lows) != n || len(volumes) != n {
+		return nil
+	}
+
+	cmf := newSeries(n)
+	var flowSum, volumeSum float64
+	for i := 0; i < n; i++ {
+		flowSum += moneyFlowMultiplier(highs[i], lows[i], closes[i]) * float64(volumes[i])
+		volumeSum += float64(volumes[i])
+		if i >= period {
+			j := i - period
+			flowSum -= moneyFlowMultiplier(highs[j], lows[j], closes[j]) * float64(volumes[j])
+			volumeSum -= float64(volumes[j])
+		}
+		if i >= period-1 && volumeSum > 0 {
+			cmf[i] = flowSum / volumeSum
+		}
+	}
+
+	return cmf
+}
+
+// CMFLatest returns the most recent CMF value, NaN without a full period of
+// volume
+func CMFLatest(highs, lows, closes []float64, volumes []int64, period int) float64 {
+	return CMF(highs, lows, closes, volumes, period).Last()
+}
@@ -4,22 +4,44 @@ import "math"
 
 // ADX represents Average Directional Index values
 type ADX struct {
-	ADX    float64 `json:"adx"`
-	PlusDI float64 `json:"plus_di"`
+	ADX     float64 `json:"adx"`
+	PlusDI  float64 `json:"plus_di"`
 	MinusDI float64 `json:"minus_di"`
 }
 
+// ADXSeries represents ADX values for entire series
+type ADXSeries struct {
+	ADX     Series
+	PlusDI  Series
+	MinusDI Series
+}
+
 // CalculateADX calculates ADX and returns latest values
 func CalculateADX(highs, lows, closes []float64, period int) *ADX {
+	series := CalculateADXSeries(highs, lows, closes, period)
+	if series == nil {
+		return nil
+	}
+
+	return &ADX{
+		ADX:     series.ADX.Last(),
+		PlusDI:  series.PlusDI.Last(),
+		MinusDI: series.MinusDI.Last(),
+	}
+}
+
+// CalculateADXSeries calculates ADX for entire series. The directional
+// indicators are valid from the period, the ADX a period later.
+func CalculateADXSeries(highs, lows, closes []float64, period int) *ADXSeries {
 	n := len(closes)
 	if n < period*2 {
 		return nil
 	}
 
-	// Calculate True Range, +DM, -DM
-	tr := make([]float64, n)
-	plusDM := make([]float64, n)
-	minusDM := make([]float64, n)
+	// Calculate True Range, +DM, -DM. The first bar has no previous close.
+	tr := newSeries(n)
+	plusDM := newSeries(n)
+	minusDM := newSeries(n)
 
 	for i := 1; i < n; i++ {
 		// True Range
@@ -32,6 +54,7 @@ func CalculateADX(highs, lows, closes []float64, period int) *ADX {
 		upMove := highs[i] - highs[i-1]
 		downMove := lows[i-1] - lows[i]
 
+		plusDM[i], minusDM[i] = 0, 0
 		if upMove > downMove && upMove > 0 {
 			plusDM[i] = upMove
 		}
@@ -46,11 +69,12 @@ func CalculateADX(highs, lows, closes []float64, period int) *ADX {
 	smoothMinusDM := wilderSmooth(minusDM, period)
 
 	// Calculate +DI and -DI
-	plusDI := make([]float64, n)
-	minusDI := make([]float64, n)
-	dx := make([]float64, n)
+	plusDI := newSeries(n)
+	minusDI := newSeries(n)
+	dx := newSeries(n)
 
 	for i := period; i < n; i++ {
+		plusDI[i], minusDI[i], dx[i] = 0, 0, 0
 		if smoothTR[i] != 0 {
 			plusDI[i] = (smoothPlusDM[i] / smoothTR[i]) * 100
 			minusDI[i] = (smoothMinusDM[i] / smoothTR[i]) * 100
@@ -62,36 +86,135 @@ func CalculateADX(highs, lows, closes []float64, period int) *ADX {
 		}
 	}
 
-	// Calculate ADX (smoothed DX)
+	// Calculate ADX (smoothed DX), first valid a period after DX
 	adxValues := wilderSmooth(dx, period)
 
-	return &ADX{
-		ADX:     adxValues[len(adxValues)-1],
-		PlusDI:  plusDI[len(plusDI)-1],
-		MinusDI: minusDI[len(minusDI)-1],
+	return &ADXSeries{
+		ADX:     adxValues,
+		PlusDI:  plusDI,
+		MinusDI: minusDI,
 	}
 }
 
-// wilderSmooth applies Wilder's smoothing method
-func wilderSmooth(values []float64, period int) []float64 {
+// wilderSmooth applies Wilder's smoothing method. The first value is the
+// average of the first period valid values; each later one moves 1/period of
+// the way towards the new value.
+func wilderSmooth(values Series, period int) Series {
 	n := len(values)
-	if n < period {
-		return nil
+	result := newSeries(n)
+	start := values.FirstValid()
+	if start < 0 || n-start < period {
+		return result
 	}
 
-	result := make([]float64, n)
-
-	// First value is sum of first period values
 	var sum float64
-	for i := 1; i <= period; i++ {
+	for i := start; i < start+period; i++ {
 		sum += values[i]
 	}
-	result[period] = sum
+	result[start+period-1] = sum / float64(period)
 
 	// Apply smoothing
-	for i := period + 1; i < n; i++ {
-		result[i] = result[i-1] - (result[i-1] / float64(period)) + values[i]
+	for i := start + period; i < n; i++ {
+		result[i] = (result[i-1]*float64(period-1) + values[i]) / float64(period)
+	}
+
+	return result
+}
+
+// ADXState is the streaming counterpart of CalculateADX
+type ADXState struct {
+	Period        int     `json:"period"`
+	Count         int     `json:"count"`
+	PrevHigh      float64 `json:"prev_high"`
+	PrevLow       float64 `json:"prev_low"`
+	PrevClose     float64 `json:"prev_close"`
+	SmoothTR      float64 `json:"smooth_tr"`
+	SmoothPlusDM  float64 `json:"smooth_plus_dm"`
+	SmoothMinusDM float64 `json:"smooth_minus_dm"`
+	ADX           float64 `json:"adx"`
+}
+
+// NewADXState creates an ADX state
+func NewADXState(period int) *ADXState {
+	return &ADXState{Period: period}
+}
+
+// Update advances the ADX by a closed bar. The directional indicators are
+// valid from the bar after the first period, the ADX a period later.
+func (s *ADXState) Update(bar Bar) ADX {
+	i := s.Count
+	s.Count++
+	prevHigh, prevLow, prevClose := s.PrevHigh, s.PrevLow, s.PrevClose
+	s.PrevHigh, s.PrevLow, s.PrevClose = bar.High, bar.Low, bar.Close
+	invalid := ADX{ADX: math.NaN(), PlusDI: math.NaN(), MinusDI: math.NaN()}
+	if i == 0 {
+		return invalid
+	}
+
+	tr := trueRange(bar, prevClose)
+	var plusDM, minusDM float64
+	upMove := bar.High - prevHigh
+	downMove := prevLow - bar.Low
+	if upMove > downMove && upMove > 0 {
+		plusDM = upMove
+	}
+	if downMove > upMove && downMove > 0 {
+		minusDM = downMove
+	}
+
+	// Like wilderSmooth, average the first period, then smooth
+	p := float64(s.Period)
+	switch {
+	case i < s.Period:
+		s.SmoothTR += tr
+		s.SmoothPlusDM += plusDM
+		s.SmoothMinusDM += minusDM
+		return invalid
+	case i == s.Period:
+		s.SmoothTR = (s.SmoothTR + tr) / p
+		s.SmoothPlusDM = (s.SmoothPlusDM + plusDM) / p
+		s.SmoothMinusDM = (s.SmoothMinusDM + minusDM) / p
+	default:
+		s.SmoothTR = (s.SmoothTR*(p-1) + tr) / p
+		s.SmoothPlusDM = (s.SmoothPlusDM*(p-1) + plusDM) / p
+		s.SmoothMinusDM = (s.SmoothMinusDM*(p-1) + minusDM) / p
+	}
+
+	result := ADX{ADX: math.NaN()}
+	if s.SmoothTR != 0 {
+		result.PlusDI = (s.SmoothPlusDM / s.SmoothTR) * 100
+		result.MinusDI = (s.SmoothMinusDM / s.SmoothTR) * 100
+	}
+	var dx float64
+	if diSum := result.PlusDI + result.MinusDI; diSum != 0 {
+		dx = (math.Abs(result.PlusDI-result.MinusDI) / diSum) * 100
 	}
 
+	// DX is smoothed the same way from its first valid value at the period
+	switch last := 2*s.Period - 1; {
+	case i < last:
+		s.ADX += dx
+		return result
+	case i == last:
+		s.ADX = (s.ADX + dx) / p
+	default:
+		s.ADX = (s.ADX*(p-1) + dx) / p
+	}
+	result.ADX = s.ADX
 	return result
 }
+
+// Peek returns the ADX a forming bar would give
+func (s *ADXState) Peek(bar Bar) ADX {
+	return s.clone().Update(bar)
+}
+
+// Ready reports whether CalculateADX would return values
+func (s *ADXState) Ready() bool {
+	return s.Count >= s.Period*2
+}
+
+func (s *ADXState) clone() *ADXState {
+	c := *s
+	return &c
+}
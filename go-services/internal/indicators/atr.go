@@ -3,7 +3,7 @@ package indicators
 import "math"
 
 // ATR calculates Average True Range
-func ATR(highs, lows, closes []float64, period int) []float64 {
+func ATR(highs, lows, closes []float64, period int) Series {
 	n := len(closes)
 	if n < period+1 {
 		return nil
@@ -21,7 +21,7 @@ func ATR(highs, lows, closes []float64, period int) []float64 {
 	}
 
 	// Calculate ATR using Wilder's smoothing (similar to EMA)
-	atr := make([]float64, n)
+	atr := newSeries(n)
 
 	// First ATR is simple average
 	var sum float64
@@ -33,17 +33,72 @@ func ATR(highs, lows, closes []float64, period int) []float64 {
 	// Apply Wilder's smoothing
 	multiplier := 1.0 / float64(period)
 	for i := period; i < n; i++ {
-		atr[i] = (atr[i-1] * float64(period-1) + tr[i]) * multiplier
+		atr[i] = (atr[i-1]*float64(period-1) + tr[i]) * multiplier
 	}
 
 	return atr
 }
 
-// ATRLatest returns the most recent ATR value
+// ATRLatest returns the most recent ATR value, NaN without enough bars
 func ATRLatest(highs, lows, closes []float64, period int) float64 {
-	atr := ATR(highs, lows, closes, period)
-	if atr == nil || len(atr) == 0 {
-		return 0
+	return ATR(highs, lows, closes, period).Last()
+}
+
+// ATRState is the streaming counterpart of ATR
+type ATRState struct {
+	Period    int     `json:"period"`
+	Count     int     `json:"count"`
+	PrevClose float64 `json:"prev_close"`
+	Sum       float64 `json:"sum"`
+	Value     float64 `json:"value"`
+}
+
+// NewATRState creates an ATR state
+func NewATRState(period int) *ATRState {
+	return &ATRState{Period: period}
+}
+
+// Update advances the ATR by a closed bar
+func (s *ATRState) Update(bar Bar) float64 {
+	tr := bar.High - bar.Low
+	if s.Count > 0 {
+		tr = trueRange(bar, s.PrevClose)
+	}
+	s.Count++
+	s.PrevClose = bar.Close
+
+	switch {
+	case s.Count < s.Period:
+		s.Sum += tr
+		return math.NaN()
+	case s.Count == s.Period:
+		s.Sum += tr
+		s.Value = s.Sum / float64(s.Period)
+	default:
+		s.Value = (s.Value*float64(s.Period-1) + tr) * (1.0 / float64(s.Period))
 	}
-	return atr[len(atr)-1]
+	return s.Value
+}
+
+// Peek returns the ATR a forming bar would give
+func (s *ATRState) Peek(bar Bar) float64 {
+	return s.clone().Update(bar)
+}
+
+// Ready reports whether a full period of true ranges has been seen
+func (s *ATRState) Ready() bool {
+	return s.Count >= s.Period
+}
+
+func (s *ATRState) clone() *ATRState {
+	c := *s
+	return &c
+}
+
+// trueRange returns the true range of a bar after a close
+func trueRange(bar Bar, prevClose float64) float64 {
+	highLow := bar.High - bar.Low
+	highPrevClose := math.Abs(bar.High - prevClose)
+	lowPrevClose := math.Abs(bar.Low - prevClose)
+	return math.Max(highLow, math.Max(highPrevClose, lowPrevClose))
 }
@@ -12,10 +12,10 @@ type BollingerBands struct {
 
 // BollingerBandsSeries represents Bollinger Bands for entire series
 type BollingerBandsSeries struct {
-	Upper  []float64
-	Middle []float64
-	Lower  []float64
-	Width  []float64
+	Upper  Series
+	Middle Series
+	Lower  Series
+	Width  Series
 }
 
 // CalculateBollingerBands calculates Bollinger Bands and returns the latest values
@@ -67,15 +67,9 @@ func CalculateBollingerBandsSeries(closes []float64, period int, stdDevMultiplie
 		return nil
 	}
 
-	upper := make([]float64, len(closes))
-	lower := make([]float64, len(closes))
-	width := make([]float64, len(closes))
-
-	for i := 0; i < period-1; i++ {
-		upper[i] = 0
-		lower[i] = 0
-		width[i] = 0
-	}
+	upper := newSeries(len(closes))
+	lower := newSeries(len(closes))
+	width := newSeries(len(closes))
 
 	for i := period - 1; i < len(closes); i++ {
 		middle := sma[i]
@@ -92,6 +86,7 @@ func CalculateBollingerBandsSeries(closes []float64, period int, stdDevMultiplie
 		upper[i] = middle + (stdDevMultiplier * stdDev)
 		lower[i] = middle - (stdDevMultiplier * stdDev)
 
+		width[i] = 0
 		if middle != 0 {
 			width[i] = (upper[i] - lower[i]) / middle
 		}
@@ -104,3 +99,60 @@ func CalculateBollingerBandsSeries(closes []float64, period int, stdDevMultiplie
 		Width:  width,
 	}
 }
+
+// BollingerState is the streaming counterpart of CalculateBollingerBandsSeries
+type BollingerState struct {
+	Multiplier float64   `json:"multiplier"`
+	Middle     *SMAState `json:"middle"`
+}
+
+// NewBollingerState creates a Bollinger Bands state
+func NewBollingerState(period int, stdDevMultiplier float64) *BollingerState {
+	return &BollingerState{
+		Multiplier: stdDevMultiplier,
+		Middle:     NewSMAState(period),
+	}
+}
+
+// Update advances the bands by a closed bar
+func (s *BollingerState) Update(bar Bar) BollingerBands {
+	middle := s.Middle.Update(bar)
+	if !s.Middle.Ready() {
+		return BollingerBands{Upper: math.NaN(), Middle: math.NaN(), Lower: math.NaN(), Width: math.NaN()}
+	}
+
+	// The deviation is taken over the window, which is O(period)
+	var sum float64
+	for _, price := range s.Middle.Window.Values {
+		diff := price - middle
+		sum += diff * diff
+	}
+	stdDev := math.Sqrt(sum / float64(s.Middle.Period))
+
+	bands := BollingerBands{
+		Upper:  middle + (s.Multiplier * stdDev),
+		Middle: middle,
+		Lower:  middle - (s.Multiplier * stdDev),
+	}
+	if middle != 0 {
+		bands.Width = (bands.Upper - bands.Lower) / middle
+	}
+	return bands
+}
+
+// Peek returns the bands a forming bar would give
+func (s *BollingerState) Peek(bar Bar) BollingerBands {
+	return s.clone().Update(bar)
+}
+
+// Ready reports whether a full period has been seen
+func (s *BollingerState) Ready() bool {
+	return s.Middle.Ready()
+}
+
+func (s *BollingerState) clone() *BollingerState {
+	return &BollingerState{
+		Multiplier: s.Multiplier,
+		Middle:     s.Middle.clone(),
+	}
+}
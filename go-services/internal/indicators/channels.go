@@ -0,0 +1,105 @@
+package indicators
+
+import "math"
+
+// KeltnerChannels represents the latest Keltner Channel values
+type KeltnerChannels struct {
+	Upper  float64 `json:"upper"`
+	Middle float64 `json:"middle"`
+	Lower  float64 `json:"lower"`
+}
+
+// KeltnerSeries represents Keltner Channels for entire series
+type KeltnerSeries struct {
+	Upper  Series
+	Middle Series
+	Lower  Series
+}
+
+// CalculateKeltner calculates Keltner Channels and returns latest values
+func CalculateKeltner(highs, lows, closes []float64, period, atrPeriod int, multiplier float64) *KeltnerChannels {
+	series := CalculateKeltnerSeries(highs, lows, closes, period, atrPeriod, multiplier)
+	if series == nil || !series.Upper.Valid(len(closes)-1) {
+		return nil
+	}
+
+	last := len(closes) - 1
+	return &KeltnerChannels{
+		Upper:  series.Upper[last],
+		Middle: series.Middle[last],
+		Lower:  series.Lower[last],
+	}
+}
+
+// CalculateKeltnerSeries calculates Keltner Channels for entire series: an EMA
+// of closes with bands multiplier ATRs away
+func CalculateKeltnerSeries(highs, lows, closes []float64, period, atrPeriod int, multiplier float64) *KeltnerSeries {
+	middle := EMA(closes, period)
+	atr := ATR(highs, lows, closes, atrPeriod)
+	if middle == nil || atr == nil {
+		return nil
+	}
+
+	n := len(closes)
+	upper, lower := newSeries(n), newSeries(n)
+	for i := 0; i < n; i++ {
+		if IsValid(middle[i]) && IsValid(atr[i]) {
+			upper[i] = middle[i] + multiplier*atr[i]
+			lower[i] = middle[i] - multiplier*atr[i]
+		}
+	}
+
+	return &KeltnerSeries{Upper: upper, Middle: middle, Lower: lower}
+}
+
+// DonchianChannels represents the latest Donchian Channel values
+type DonchianChannels struct {
+	Upper  float64 `json:"upper"`
+	Middle float64 `json:"middle"`
+	Lower  float64 `json:"lower"`
+}
+
+// DonchianSeries represents Donchian Channels for entire series
+type DonchianSeries struct {
+	Upper  Series
+	Middle Series
+	Lower  Series
+}
+
+// CalculateDonchian calculates Donchian Channels and returns latest values
+func CalculateDonchian(highs, lows []float64, period int) *DonchianChannels {
+	series := CalculateDonchianSeries(highs, lows, period)
+	if series == nil {
+		return nil
+	}
+
+	last := len(highs) - 1
+	return &DonchianChannels{
+		Upper:  series.Upper[last],
+		Middle: series.Middle[last],
+		Lower:  series.Lower[last],
+	}
+}
+
+// CalculateDonchianSeries calculates Donchian Channels for entire series: the
+// highest high and lowest low of each window of period bars, including the
+// bar itself
+func CalculateDonchianSeries(highs, lows []float64, period int) *DonchianSeries {
+	n := len(highs)
+	if n < period || len(lows) != n {
+		return nil
+	}
+
+	upper, middle, lower := newSeries(n), newSeries(n), newSeries(n)
+	for i := period - 1; i < n; i++ {
+		highest, lowest := highs[i-period+1], lows[i-period+1]
+		for j := i - period + 2; j <= i; j++ {
+			highest = math.Max(highest, highs[j])
+			lowest = math.Min(lowest, lows[j])
+		}
+		upper[i], lower[i] = highest, lowest
+		middle[i] = (highest + lowest) / 2
+	}
+
+	return &DonchianSeries{Upper: upper, Middle: middle, Lower: lower}
+}
@@ -1,39 +1,87 @@
 package indicators
 
-// EMA calculates Exponential Moving Average
-func EMA(prices []float64, period int) []float64 {
+import "math"
+
+// EMA calculates Exponential Moving Average. Leading invalid prices, such as
+// the warm-up of another indicator, are skipped.
+func EMA(prices []float64, period int) Series {
 	if len(prices) < period {
 		return nil
 	}
 
-	result := make([]float64, len(prices))
+	result := newSeries(len(prices))
 	multiplier := 2.0 / float64(period+1)
-
-	// Initialize with zeros for invalid periods
-	for i := 0; i < period-1; i++ {
-		result[i] = 0
+	start := Series(prices).FirstValid()
+	if start < 0 || len(prices)-start < period {
+		return result
 	}
 
 	// First EMA is SMA
 	var sum float64
-	for i := 0; i < period; i++ {
+	for i := start; i < start+period; i++ {
 		sum += prices[i]
 	}
-	result[period-1] = sum / float64(period)
+	result[start+period-1] = sum / float64(period)
 
 	// Calculate EMA for remaining values
-	for i := period; i < len(prices); i++ {
+	for i := start + period; i < len(prices); i++ {
 		result[i] = (prices[i]-result[i-1])*multiplier + result[i-1]
 	}
 
 	return result
 }
 
-// EMALatest returns the most recent EMA value
+// EMALatest returns the most recent EMA value, NaN without a full period
 func EMALatest(prices []float64, period int) float64 {
-	ema := EMA(prices, period)
-	if ema == nil || len(ema) == 0 {
-		return 0
+	return EMA(prices, period).Last()
+}
+
+// EMAState is the streaming counterpart of EMA over closes
+type EMAState struct {
+	Period int     `json:"period"`
+	Count  int     `json:"count"`
+	Sum    float64 `json:"sum"`
+	Value  float64 `json:"value"`
+}
+
+// NewEMAState creates an EMA state
+func NewEMAState(period int) *EMAState {
+	return &EMAState{Period: period}
+}
+
+// Update advances the EMA by a closed bar
+func (s *EMAState) Update(bar Bar) float64 {
+	return s.add(bar.Close)
+}
+
+// Peek returns the EMA a forming bar would give
+func (s *EMAState) Peek(bar Bar) float64 {
+	return s.clone().Update(bar)
+}
+
+// Ready reports whether the EMA has been seeded
+func (s *EMAState) Ready() bool {
+	return s.Count >= s.Period
+}
+
+// add advances the EMA by any value. Like EMA, the first value is the SMA of
+// the first period.
+func (s *EMAState) add(v float64) float64 {
+	s.Count++
+	switch {
+	case s.Count < s.Period:
+		s.Sum += v
+		return math.NaN()
+	case s.Count == s.Period:
+		s.Sum += v
+		s.Value = s.Sum / float64(s.Period)
+	default:
+		s.Value = (v-s.Value)*(2.0/float64(s.Period+1)) + s.Value
 	}
-	return ema[len(ema)-1]
+	return s.Value
+}
+
+func (s *EMAState) clone() *EMAState {
+	c := *s
+	return &c
 }
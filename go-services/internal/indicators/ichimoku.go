@@ -0,0 +1,124 @@
+package indicators
+
+import "math"
+
+// Ichimoku represents the latest Ichimoku Kinko Hyo values
+type Ichimoku struct {
+	Tenkan float64 `json:"tenkan"`
+	Kijun  float64 `json:"kijun"`
+	// SenkouA and SenkouB are the cloud under the latest bar, computed
+	// displacement bars earlier
+	SenkouA float64 `json:"senkou_a"`
+	SenkouB float64 `json:"senkou_b"`
+	// LeadingA and LeadingB are the spans computed at the latest bar, which
+	// form the cloud displacement bars ahead
+	LeadingA float64 `json:"leading_a"`
+	LeadingB float64 `json:"leading_b"`
+	// Chikou is the latest close, plotted displacement bars back
+	Chikou float64 `json:"chikou"`
+	// TKCross is 1 if Tenkan crossed above Kijun at the latest bar, -1 if it
+	// crossed below and 0 otherwise
+	TKCross int `json:"tk_cross"`
+	// CloudTwist is 1 if Leading A crossed above Leading B at the latest bar,
+	// turning the future cloud bullish, -1 if it crossed below and 0 otherwise
+	CloudTwist int `json:"cloud_twist"`
+}
+
+// CloudTop returns the upper edge of the cloud under the latest bar
+func (i *Ichimoku) CloudTop() float64 {
+	return math.Max(i.SenkouA, i.SenkouB)
+}
+
+// CloudBottom returns the lower edge of the cloud under the latest bar
+func (i *Ichimoku) CloudBottom() float64 {
+	return math.Min(i.SenkouA, i.SenkouB)
+}
+
+// IchimokuSeries represents Ichimoku values for entire series. Every series
+// is aligned with the input bars: SenkouA and SenkouB hold the cloud shifted
+// forward onto each bar, LeadingA and LeadingB the unshifted spans, whose
+// last displacement values are the cloud ahead of the latest bar, and Chikou
+// the close shifted back, invalid for the last displacement bars.
+type IchimokuSeries struct {
+	Tenkan   Series
+	Kijun    Series
+	SenkouA  Series
+	SenkouB  Series
+	LeadingA Series
+	LeadingB Series
+	Chikou   Series
+}
+
+// CalculateIchimoku calculates Ichimoku Kinko Hyo and returns latest values
+func CalculateIchimoku(highs, lows, closes []float64, tenkanPeriod, kijunPeriod, senkouBPeriod, displacement int) *Ichimoku {
+	n := len(closes)
+	series := CalculateIchimokuSeries(highs, lows, closes, tenkanPeriod, kijunPeriod, senkouBPeriod, displacement)
+	if series == nil || !series.SenkouA.Valid(n-1) || !series.SenkouB.Valid(n-1) {
+		return nil
+	}
+
+	last := n - 1
+	return &Ichimoku{
+		Tenkan:     series.Tenkan[last],
+		Kijun:      series.Kijun[last],
+		SenkouA:    series.SenkouA[last],
+		SenkouB:    series.SenkouB[last],
+		LeadingA:   series.LeadingA[last],
+		LeadingB:   series.LeadingB[last],
+		Chikou:     closes[last],
+		TKCross:    crossing(series.Tenkan, series.Kijun),
+		CloudTwist: crossing(series.LeadingA, series.LeadingB),
+	}
+}
+
+// CalculateIchimokuSeries calculates Ichimoku Kinko Hyo for entire series
+func CalculateIchimokuSeries(highs, lows, closes []float64, tenkanPeriod, kijunPeriod, senkouBPeriod, displacement int) *IchimokuSeries {
+	n := len(closes)
+	if n < max(tenkanPeriod, kijunPeriod, senkouBPeriod) || len(highs) != n || len(lows) != n || displacement < 1 {
+		return nil
+	}
+
+	// Each line is the middle of a Donchian channel
+	tenkan := CalculateDonchianSeries(highs, lows, tenkanPeriod).Middle
+	kijun := CalculateDonchianSeries(highs, lows, kijunPeriod).Middle
+	leadingB := CalculateDonchianSeries(highs, lows, senkouBPeriod).Middle
+	leadingA := newSeries(n)
+	for i := range leadingA {
+		if IsValid(tenkan[i]) && IsValid(kijun[i]) {
+			leadingA[i] = (tenkan[i] + kijun[i]) / 2
+		}
+	}
+
+	senkouA, senkouB, chikou := newSeries(n), newSeries(n), newSeries(n)
+	for i := displacement; i < n; i++ {
+		senkouA[i] = leadingA[i-displacement]
+		senkouB[i] = leadingB[i-displacement]
+		chikou[i-displacement] = closes[i]
+	}
+
+	return &IchimokuSeries{
+		Tenkan:   tenkan,
+		Kijun:    kijun,
+		SenkouA:  senkouA,
+		SenkouB:  senkouB,
+		LeadingA: leadingA,
+		LeadingB: leadingB,
+		Chikou:   chikou,
+	}
+}
+
+// crossing returns 1 if a crossed above b at the last value, -1 if it crossed
+// below and 0 otherwise
+func crossing(a, b Series) int {
+	n := len(a)
+	if !a.Valid(n-1) || !b.Valid(n-1) || !a.Valid(n-2) || !b.Valid(n-2) {
+		return 0
+	}
+	switch {
+	case a[n-1] > b[n-1] && a[n-2] <= b[n-2]:
+		return 1
+	case a[n-1] < b[n-1] && a[n-2] >= b[n-2]:
+		return -1
+	}
+	return 0
+}
@@ -1,6 +1,7 @@
 package indicators
 
 import (
+	"encoding/json"
 	"math"
 	"testing"
 )
@@ -146,3 +147,145 @@ func TestATR(t *testing.T) {
 		t.Errorf("ATR = %v, expected > 0", lastATR)
 	}
 }
+
+func TestWarmUpIsInvalid(t *testing.T) {
+	prices := []float64{10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}
+
+	sma := SMA(prices, 5)
+	if sma.FirstValid() != 4 || sma.Valid(3) || !sma.Valid(4) {
+		t.Errorf("SMA first valid = %d, expected 4", sma.FirstValid())
+	}
+
+	// An SMA of a warming-up series starts where that series is valid
+	smoothed := SMA(sma, 3)
+	if smoothed.FirstValid() != 6 || !almostEqual(smoothed[6], 13) {
+		t.Errorf("SMA of SMA first valid = %d (%v), expected 6 (13)", smoothed.FirstValid(), smoothed)
+	}
+
+	data, err := json.Marshal(SMA(prices[:6], 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "[null,null,null,null,12,13]" {
+		t.Errorf("SMA JSON = %s, expected nulls for warm-up", data)
+	}
+
+	if v := RSILatest(prices[:10], 14); IsValid(v) {
+		t.Errorf("RSI of 10 prices = %v, expected NaN", v)
+	}
+}
+
+func TestADXInRange(t *testing.T) {
+	bars := randomBars(200)
+	highs, lows, closes, _ := splitBars(bars)
+
+	adx := CalculateADX(highs, lows, closes, 14)
+	if adx == nil {
+		t.Fatal("ADX returned nil")
+	}
+	for _, v := range []float64{adx.ADX, adx.PlusDI, adx.MinusDI} {
+		if v < 0 || v > 100 {
+			t.Errorf("ADX = %+v, expected values within 0-100", adx)
+		}
+	}
+}
+
+func TestIchimokuShifts(t *testing.T) {
+	// A steady rise: the midpoint of n bars ending at i is i + 1 - n/2
+	var highs, lows, closes []float64
+	for i := 0; i < 20; i++ {
+		highs = append(highs, float64(i+1))
+		lows = append(lows, float64(i))
+		closes = append(closes, float64(i)+0.5)
+	}
+
+	ichimoku := CalculateIchimokuSeries(highs, lows, closes, 3, 5, 8, 4)
+	if ichimoku == nil {
+		t.Fatal("Ichimoku returned nil")
+	}
+	if !almostEqual(ichimoku.LeadingA[19], 18) || !almostEqual(ichimoku.LeadingB[19], 16) {
+		t.Errorf("leading spans = %v/%v, expected 18/16", ichimoku.LeadingA[19], ichimoku.LeadingB[19])
+	}
+	// The cloud under a bar was computed displacement bars earlier
+	if !almostEqual(ichimoku.SenkouA[19], 14) || !almostEqual(ichimoku.SenkouB[19], 12) {
+		t.Errorf("senkou spans = %v/%v, expected 14/12", ichimoku.SenkouA[19], ichimoku.SenkouB[19])
+	}
+	if ichimoku.SenkouB.FirstValid() != 11 || ichimoku.Chikou[15] != closes[19] || ichimoku.Chikou.Valid(16) {
+		t.Errorf("senkou B first valid = %d, chikou = %v", ichimoku.SenkouB.FirstValid(), ichimoku.Chikou)
+	}
+
+	if CalculateIchimoku(highs[:11], lows[:11], closes[:11], 3, 5, 8, 4) != nil {
+		t.Error("Ichimoku of 11 bars returned values without a cloud")
+	}
+	latest := CalculateIchimoku(highs, lows, closes, 3, 5, 8, 4)
+	if latest == nil || latest.CloudTop() != 14 || latest.TKCross != 0 || latest.Chikou != closes[19] {
+		t.Errorf("latest Ichimoku = %+v", latest)
+	}
+}
+
+func TestVolumeIndicators(t *testing.T) {
+	closes := []float64{10, 11, 10, 10, 12}
+	volumes := []int64{100, 200, 300, 400, 500}
+
+	obv := OBV(closes, volumes)
+	for i, want := range []float64{0, 200, -100, -100, 400} {
+		if obv[i] != want {
+			t.Errorf("OBV[%d] = %v, expected %v", i, obv[i], want)
+		}
+	}
+
+	// (11*200 + 10*300 + 10*400 + 12*500) / 1400
+	if vwma := VWMALatest(closes, volumes, 4); !almostEqual(vwma, 15200.0/1400) {
+		t.Errorf("VWMA = %v, expected %v", vwma, 15200.0/1400)
+	}
+
+	// Bars closing at their high are all inflow
+	highs := []float64{10, 11, 10, 10, 12}
+	lows := []float64{9, 10, 9, 9, 11}
+	if cmf := CMFLatest(highs, lows, closes, volumes, 3); !almostEqual(cmf, 1) {
+		t.Errorf("CMF = %v, expected 1", cmf)
+	}
+	if ad := AccumulationDistribution(highs, lows, closes, volumes); ad[4] != 1500 {
+		t.Errorf("A/D = %v, expected 1500", ad)
+	}
+
+	mfi := MFI(highs, lows, closes, volumes, 2)
+	if mfi.FirstValid() != 2 || mfi[4] != 100 {
+		t.Errorf("MFI = %v, expected valid from 2 and 100 after flat and rising bars", mfi)
+	}
+}
+
+func TestTrendOverlaysFollowReversal(t *testing.T) {
+	// 30 rising bars, then 30 falling ones
+	var highs, lows, closes []float64
+	for i := 0; i < 60; i++ {
+		mid := 100 + float64(i)
+		if i >= 30 {
+			mid = 160 - float64(i)
+		}
+		highs = append(highs, mid+1)
+		lows = append(lows, mid-1)
+		closes = append(closes, mid)
+	}
+
+	sar := CalculateParabolicSARSeries(highs, lows, 0.02, 0.2)
+	st := CalculateSupertrendSeries(highs, lows, closes, 10, 3)
+	if sar == nil || st == nil {
+		t.Fatal("trend overlays returned nil")
+	}
+	if sar.Direction[29] != 1 || sar.SAR[29] >= lows[29] || sar.Direction[59] != -1 || sar.SAR[59] <= highs[59] {
+		t.Errorf("SAR = %v/%v at the top, %v/%v at the end", sar.SAR[29], sar.Direction[29], sar.SAR[59], sar.Direction[59])
+	}
+	if st.Direction[29] != 1 || st.Line[29] >= closes[29] || st.Direction[59] != -1 || st.Line[59] <= closes[59] {
+		t.Errorf("Supertrend = %v/%v at the top, %v/%v at the end", st.Line[29], st.Direction[29], st.Line[59], st.Direction[59])
+	}
+
+	dc := CalculateDonchian(highs, lows, 20)
+	if dc == nil || dc.Upper != highs[40] || dc.Lower != lows[59] {
+		t.Errorf("Donchian = %+v, expected %v-%v", dc, lows[59], highs[40])
+	}
+	kc := CalculateKeltner(highs, lows, closes, 20, 10, 2)
+	if kc == nil || !(kc.Lower < kc.Middle && kc.Middle < kc.Upper) {
+		t.Errorf("Keltner = %+v, expected ordered bands", kc)
+	}
+}
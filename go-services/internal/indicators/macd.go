@@ -1,5 +1,7 @@
 package indicators
 
+import "math"
+
 // MACD represents MACD indicator values
 type MACD struct {
 	MACDLine   float64 `json:"macd_line"`
@@ -9,51 +11,27 @@ type MACD struct {
 
 // MACDSeries represents MACD values for entire series
 type MACDSeries struct {
-	MACDLine   []float64
-	SignalLine []float64
-	Histogram  []float64
+	MACDLine   Series
+	SignalLine Series
+	Histogram  Series
 }
 
 // CalculateMACD calculates MACD indicator and returns the latest values
 func CalculateMACD(closes []float64, fastPeriod, slowPeriod, signalPeriod int) *MACD {
-	if len(closes) < slowPeriod+signalPeriod {
-		return nil
-	}
-
-	emaFast := EMA(closes, fastPeriod)
-	emaSlow := EMA(closes, slowPeriod)
-
-	if len(emaFast) == 0 || len(emaSlow) == 0 {
-		return nil
-	}
-
-	// Calculate MACD line (Fast EMA - Slow EMA)
-	macdLine := make([]float64, len(closes))
-	startIdx := slowPeriod - 1 // Start from where slow EMA is valid
-
-	for i := startIdx; i < len(closes); i++ {
-		macdLine[i] = emaFast[i] - emaSlow[i]
-	}
-
-	// Calculate Signal line (EMA of MACD line)
-	validMACD := macdLine[startIdx:]
-	signalEMA := EMA(validMACD, signalPeriod)
-
-	if len(signalEMA) == 0 {
+	series := CalculateMACDSeries(closes, fastPeriod, slowPeriod, signalPeriod)
+	if series == nil {
 		return nil
 	}
 
-	lastMACD := macdLine[len(macdLine)-1]
-	lastSignal := signalEMA[len(signalEMA)-1]
-
 	return &MACD{
-		MACDLine:   lastMACD,
-		SignalLine: lastSignal,
-		Histogram:  lastMACD - lastSignal,
+		MACDLine:   series.MACDLine.Last(),
+		SignalLine: series.SignalLine.Last(),
+		Histogram:  series.Histogram.Last(),
 	}
 }
 
-// CalculateMACDSeries calculates MACD for entire price series
+// CalculateMACDSeries calculates MACD for entire price series. The line is
+// valid once the slow EMA is, the signal and histogram a signal period later.
 func CalculateMACDSeries(closes []float64, fastPeriod, slowPeriod, signalPeriod int) *MACDSeries {
 	if len(closes) < slowPeriod+signalPeriod {
 		return nil
@@ -66,41 +44,78 @@ func CalculateMACDSeries(closes []float64, fastPeriod, slowPeriod, signalPeriod
 		return nil
 	}
 
-	macdLine := make([]float64, len(closes))
-	startIdx := slowPeriod - 1
+	// Calculate MACD line (Fast EMA - Slow EMA); invalid until both are valid
+	macdLine := make(Series, len(closes))
+	for i := range closes {
+		macdLine[i] = emaFast[i] - emaSlow[i]
+	}
 
-	for i := 0; i < startIdx; i++ {
-		macdLine[i] = 0
+	// Calculate Signal line (EMA of MACD line), which skips its warm-up
+	signalLine := EMA(macdLine, signalPeriod)
+
+	histogram := make(Series, len(closes))
+	for i := range closes {
+		histogram[i] = macdLine[i] - signalLine[i]
 	}
 
-	for i := startIdx; i < len(closes); i++ {
-		macdLine[i] = emaFast[i] - emaSlow[i]
+	return &MACDSeries{
+		MACDLine:   macdLine,
+		SignalLine: signalLine,
+		Histogram:  histogram,
 	}
+}
 
-	// Calculate Signal line
-	validMACD := macdLine[startIdx:]
-	signalEMA := EMA(validMACD, signalPeriod)
+// MACDState is the streaming counterpart of CalculateMACDSeries
+type MACDState struct {
+	Fast   *EMAState `json:"fast"`
+	Slow   *EMAState `json:"slow"`
+	Signal *EMAState `json:"signal"`
+	Count  int       `json:"count"`
+}
 
-	signalLine := make([]float64, len(closes))
-	histogram := make([]float64, len(closes))
+// NewMACDState creates a MACD state
+func NewMACDState(fastPeriod, slowPeriod, signalPeriod int) *MACDState {
+	return &MACDState{
+		Fast:   NewEMAState(fastPeriod),
+		Slow:   NewEMAState(slowPeriod),
+		Signal: NewEMAState(signalPeriod),
+	}
+}
 
-	signalStartIdx := startIdx + signalPeriod - 1
-	for i := 0; i < signalStartIdx; i++ {
-		signalLine[i] = 0
-		histogram[i] = 0
+// Update advances the MACD by a closed bar
+func (s *MACDState) Update(bar Bar) MACD {
+	s.Count++
+	fast := s.Fast.Update(bar)
+	slow := s.Slow.Update(bar)
+	if !s.Slow.Ready() {
+		return MACD{MACDLine: math.NaN(), SignalLine: math.NaN(), Histogram: math.NaN()}
 	}
 
-	for i := 0; i < len(signalEMA); i++ {
-		idx := startIdx + i
-		if idx < len(signalLine) {
-			signalLine[idx] = signalEMA[i]
-			histogram[idx] = macdLine[idx] - signalEMA[i]
-		}
+	// The signal line starts where the slow EMA is valid
+	line := fast - slow
+	signal := s.Signal.add(line)
+	return MACD{
+		MACDLine:   line,
+		SignalLine: signal,
+		Histogram:  line - signal,
 	}
+}
 
-	return &MACDSeries{
-		MACDLine:   macdLine,
-		SignalLine: signalLine,
-		Histogram:  histogram,
+// Peek returns the MACD a forming bar would give
+func (s *MACDState) Peek(bar Bar) MACD {
+	return s.clone().Update(bar)
+}
+
+// Ready reports whether CalculateMACD would return values
+func (s *MACDState) Ready() bool {
+	return s.Count >= s.Slow.Period+s.Signal.Period
+}
+
+func (s *MACDState) clone() *MACDState {
+	return &MACDState{
+		Fast:   s.Fast.clone(),
+		Slow:   s.Slow.clone(),
+		Signal: s.Signal.clone(),
+		Count:  s.Count,
 	}
 }
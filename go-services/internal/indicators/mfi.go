@@ -0,0 +1,62 @@
+package indicators
+
+// MFI calculates the Money Flow Index, a volume-weighted RSI of typical
+// prices. It needs period price changes, so the first period bars are
+// invalid.
+func MFI(highs, lows, closes []float64, volumes []int64, period int) Series {
+	n := len(closes)
+	if n < period+1 || len(highs) != n || len(lows) != n || len(volumes) != n {
+		return nil
+	}
+
+	// Raw money flow, positive when the typical price rises and negative
+	// when it falls
+	flows := make([]float64, n)
+	prevTypical := (highs[0] + lows[0] + closes[0]) / 3
+	for i := 1; i < n; i++ {
+		typical := (highs[i] + lows[i] + closes[i]) / 3
+		flow := typical * float64(volumes[i])
+		if typical > prevTypical {
+			flows[i] = flow
+		} else if typical < prevTypical {
+			flows[i] = -flow
+		}
+		prevTypical = typical
+	}
+
+	mfi := newSeries(n)
+	var positive, negative float64
+	for i := 1; i < n; i++ {
+		if flows[i] > 0 {
+			positive += flows[i]
+		} else {
+			negative -= flows[i]
+		}
+		if i > period {
+			if old := flows[i-period]; old > 0 {
+				positive -= old
+			} else {
+				negative += old
+			}
+		}
+		if i < period {
+			continue
+		}
+
+		switch {
+		case negative <= 0 && positive <= 0:
+			mfi[i] = 50 // Neutral without money flow
+		case negative <= 0:
+			mfi[i] = 100
+		default:
+			mfi[i] = 100 - 100/(1+positive/negative)
+		}
+	}
+
+	return mfi
+}
+
+// MFILatest returns the most recent MFI value, NaN without a full period
+func MFILatest(highs, lows, closes []float64, volumes []int64, period int) float64 {
+	return MFI(highs, lows, closes, volumes, period).Last()
+}
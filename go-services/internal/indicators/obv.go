@@ -0,0 +1,28 @@
+package indicators
+
+// OBV calculates On-Balance Volume: the running total of volume, added on up
+// closes and subtracted on down closes. It starts at zero on the first bar,
+// so only its changes are meaningful.
+func OBV(closes []float64, volumes []int64) Series {
+	n := len(closes)
+	if n == 0 || len(volumes) != n {
+		return nil
+	}
+
+	obv := make(Series, n)
+	for i := 1; i < n; i++ {
+		obv[i] = obv[i-1]
+		if closes[i] > closes[i-1] {
+			obv[i] += float64(volumes[i])
+		} else if closes[i] < closes[i-1] {
+			obv[i] -= float64(volumes[i])
+		}
+	}
+
+	return obv
+}
+
+// OBVLatest returns the most recent OBV value, NaN without data
+func OBVLatest(closes []float64, volumes []int64) float64 {
+	return OBV(closes, volumes).Last()
+}
@@ -0,0 +1,88 @@
+package indicators
+
+import "math"
+
+// ParabolicSAR represents the latest Parabolic SAR
+type ParabolicSAR struct {
+	SAR float64 `json:"sar"`
+	// Uptrend is true while the SAR trails below the price
+	Uptrend bool `json:"uptrend"`
+	// Reversal marks a trend that flipped at the latest bar
+	Reversal bool `json:"reversal"`
+}
+
+// ParabolicSARSeries represents Parabolic SAR values for entire series.
+// Direction is 1 in an uptrend and -1 in a downtrend.
+type ParabolicSARSeries struct {
+	SAR       Series
+	Direction Series
+}
+
+// CalculateParabolicSAR calculates Wilder's Parabolic SAR and returns latest
+// values
+func CalculateParabolicSAR(highs, lows []float64, step, maxStep float64) *ParabolicSAR {
+	series := CalculateParabolicSARSeries(highs, lows, step, maxStep)
+	if series == nil {
+		return nil
+	}
+
+	n := len(series.SAR)
+	return &ParabolicSAR{
+		SAR:      series.SAR[n-1],
+		Uptrend:  series.Direction[n-1] > 0,
+		Reversal: series.Direction.Valid(n-2) && series.Direction[n-1] != series.Direction[n-2],
+	}
+}
+
+// CalculateParabolicSARSeries calculates Parabolic SAR for entire series. The
+// SAR of a bar is the stop for that bar; the first bar has none.
+func CalculateParabolicSARSeries(highs, lows []float64, step, maxStep float64) *ParabolicSARSeries {
+	n := len(highs)
+	if n < 2 || len(lows) != n {
+		return nil
+	}
+
+	sar, direction := newSeries(n), newSeries(n)
+
+	// The first two bars set the initial trend
+	up := highs[1]+lows[1] >= highs[0]+lows[0]
+	current, extreme := highs[0], lows[1]
+	if up {
+		current, extreme = lows[0], highs[1]
+	}
+	af := step
+	sar[1], direction[1] = current, trendDirection(up)
+
+	for i := 2; i < n; i++ {
+		current += af * (extreme - current)
+
+		if up {
+			// The SAR never rises into the previous two bars
+			current = math.Min(current, math.Min(lows[i-1], lows[i-2]))
+			if lows[i] < current {
+				up, current, extreme, af = false, extreme, lows[i], step
+			} else if highs[i] > extreme {
+				extreme, af = highs[i], math.Min(af+step, maxStep)
+			}
+		} else {
+			current = math.Max(current, math.Max(highs[i-1], highs[i-2]))
+			if highs[i] > current {
+				up, current, extreme, af = true, extreme, highs[i], step
+			} else if lows[i] < extreme {
+				extreme, af = lows[i], math.Min(af+step, maxStep)
+			}
+		}
+
+		sar[i], direction[i] = current, trendDirection(up)
+	}
+
+	return &ParabolicSARSeries{SAR: sar, Direction: direction}
+}
+
+// trendDirection encodes a trend as a series value
+func trendDirection(up bool) float64 {
+	if up {
+		return 1
+	}
+	return -1
+}
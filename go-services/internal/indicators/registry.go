@@ -0,0 +1,526 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Param describes a numeric parameter of an indicator
+type Param struct {
+	Name    string  `json:"name"`
+	Default float64 `json:"default"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+	// Integer params, such as periods, must be whole numbers
+	Integer bool `json:"integer"`
+}
+
+// Check returns an error if v is not a valid value of the parameter
+func (p Param) Check(v float64) error {
+	if math.IsNaN(v) || v < p.Min || v > p.Max {
+		return fmt.Errorf("%s must be between %g and %g, got %g", p.Name, p.Min, p.Max, v)
+	}
+	if p.Integer && v != math.Trunc(v) {
+		return fmt.Errorf("%s must be a whole number, got %g", p.Name, v)
+	}
+	return nil
+}
+
+// Inputs are the price arrays indicators are computed from
+type Inputs struct {
+	Opens   []float64
+	Highs   []float64
+	Lows    []float64
+	Closes  []float64
+	Volumes []int64
+}
+
+// Definition describes an indicator of the registry
+type Definition struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Params      []Param  `json:"params"`
+	Inputs      []string `json:"inputs"`
+	Outputs     []string `json:"outputs"`
+	// Validate, if set, checks the parameters together, once each is within
+	// its bounds
+	Validate func(params []float64) error `json:"-"`
+	// Lookback returns how many bars the indicator needs to become valid
+	Lookback func(params []float64) int `json:"-"`
+	// Compute returns one series per output, in the order of Outputs
+	Compute func(in Inputs, params []float64) []Series `json:"-"`
+}
+
+var registry = map[string]Definition{}
+
+// Register adds an indicator to the registry, replacing any of the same name
+func Register(def Definition) {
+	registry[def.Name] = def
+}
+
+// Lookup returns the definition of an indicator
+func Lookup(name string) (Definition, bool) {
+	def, ok := registry[name]
+	return def, ok
+}
+
+// Definitions returns every registered indicator, sorted by name
+func Definitions() []Definition {
+	defs := make([]Definition, 0, len(registry))
+	for _, def := range registry {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+// Spec is an indicator with its parameters, written like rsi(7) or
+// bb(20,2.5). Omitted trailing parameters take their defaults.
+type Spec struct {
+	Name   string
+	Params []float64
+}
+
+// ParseSpec parses and validates one indicator spec
+func ParseSpec(text string) (Spec, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	name, args := text, ""
+	if open := strings.IndexByte(text, '('); open >= 0 {
+		if !strings.HasSuffix(text, ")") {
+			return Spec{}, fmt.Errorf("invalid indicator spec %q: missing closing parenthesis", text)
+		}
+		name, args = strings.TrimSpace(text[:open]), text[open+1:len(text)-1]
+	}
+
+	def, ok := Lookup(name)
+	if !ok {
+		return Spec{}, fmt.Errorf("unknown indicator %q", name)
+	}
+
+	var values []string
+	if strings.TrimSpace(args) != "" {
+		values = strings.Split(args, ",")
+	}
+	if len(values) > len(def.Params) {
+		return Spec{}, fmt.Errorf("%s takes at most %d parameters, got %d", name, len(def.Params), len(values))
+	}
+
+	spec := Spec{Name: name, Params: make([]float64, len(def.Params))}
+	for i, p := range def.Params {
+		v := p.Default
+		if i < len(values) {
+			var err error
+			if v, err = strconv.ParseFloat(strings.TrimSpace(values[i]), 64); err != nil {
+				return Spec{}, fmt.Errorf("%s %s must be a number, got %q", name, p.Name, strings.TrimSpace(values[i]))
+			}
+		}
+		if err := p.Check(v); err != nil {
+			return Spec{}, fmt.Errorf("%s %w", name, err)
+		}
+		spec.Params[i] = v
+	}
+	if def.Validate != nil {
+		if err := def.Validate(spec.Params); err != nil {
+			return Spec{}, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return spec, nil
+}
+
+// ParseSpecs parses a comma-separated list of specs such as
+// "rsi(7),sma(200),bb(20,2.5)"
+func ParseSpecs(list string) ([]Spec, error) {
+	var specs []Spec
+	depth, start := 0, 0
+	for i := 0; i <= len(list); i++ {
+		if i < len(list) {
+			switch list[i] {
+			case '(':
+				depth++
+				continue
+			case ')':
+				depth--
+				continue
+			case ',':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+
+		if text := strings.TrimSpace(list[start:i]); text != "" {
+			spec, err := ParseSpec(text)
+			if err != nil {
+				return nil, err
+			}
+			specs = append(specs, spec)
+		}
+		start = i + 1
+	}
+	return specs, nil
+}
+
+// String returns the canonical form of the spec, with every parameter
+func (s Spec) String() string {
+	if len(s.Params) == 0 {
+		return s.Name
+	}
+	params := make([]string, len(s.Params))
+	for i, p := range s.Params {
+		params[i] = strconv.FormatFloat(p, 'g', -1, 64)
+	}
+	return s.Name + "(" + strings.Join(params, ",") + ")"
+}
+
+// Lookback returns how many bars the spec needs to become valid
+func (s Spec) Lookback() int {
+	return registry[s.Name].Lookback(s.Params)
+}
+
+// Compute evaluates the spec, returning a series per output. Too little data
+// gives series without valid values.
+func (s Spec) Compute(in Inputs) map[string]Series {
+	def := registry[s.Name]
+	series := def.Compute(in, s.Params)
+	result := make(map[string]Series, len(def.Outputs))
+	for i, name := range def.Outputs {
+		if i < len(series) && series[i] != nil {
+			result[name] = series[i]
+		} else {
+			result[name] = newSeries(len(in.Closes))
+		}
+	}
+	return result
+}
+
+// Values is the latest value of each output of an indicator, null while it
+// warms up
+type Values map[string]*float64
+
+// Latest evaluates the spec and returns the latest value of each output
+func (s Spec) Latest(in Inputs) Values {
+	values := Values{}
+	for name, series := range s.Compute(in) {
+		values[name] = Nullable(series.Last())
+	}
+	return values
+}
+
+// period returns a parameter as a whole number
+func period(params []float64, i int) int {
+	return int(params[i])
+}
+
+func init() {
+	periodParam := func(def float64) Param {
+		return Param{Name: "period", Default: def, Min: 1, Max: 1000, Integer: true}
+	}
+
+	Register(Definition{
+		Name:        "sma",
+		Description: "Simple moving average of closes",
+		Params:      []Param{periodParam(20)},
+		Inputs:      []string{"close"},
+		Outputs:     []string{"value"},
+		Lookback:    func(p []float64) int { return period(p, 0) },
+		Compute: func(in Inputs, p []float64) []Series {
+			return []Series{SMA(in.Closes, period(p, 0))}
+		},
+	})
+
+	Register(Definition{
+		Name:        "ema",
+		Description: "Exponential moving average of closes",
+		Params:      []Param{periodParam(20)},
+		Inputs:      []string{"close"},
+		Outputs:     []string{"value"},
+		Lookback:    func(p []float64) int { return period(p, 0) },
+		Compute: func(in Inputs, p []float64) []Series {
+			return []Series{EMA(in.Closes, period(p, 0))}
+		},
+	})
+
+	Register(Definition{
+		Name:        "rsi",
+		Description: "Relative Strength Index",
+		Params:      []Param{{Name: "period", Default: 14, Min: 2, Max: 500, Integer: true}},
+		Inputs:      []string{"close"},
+		Outputs:     []string{"value"},
+		Lookback:    func(p []float64) int { return period(p, 0) + 1 },
+		Compute: func(in Inputs, p []float64) []Series {
+			return []Series{RSI(in.Closes, period(p, 0))}
+		},
+	})
+
+	Register(Definition{
+		Name:        "macd",
+		Description: "Moving Average Convergence Divergence",
+		Params: []Param{
+			{Name: "fast", Default: 12, Min: 1, Max: 500, Integer: true},
+			{Name: "slow", Default: 26, Min: 2, Max: 500, Integer: true},
+			{Name: "signal", Default: 9, Min: 1, Max: 500, Integer: true},
+		},
+		Inputs:  []string{"close"},
+		Outputs: []string{"macd", "signal", "histogram"},
+		Validate: func(p []float64) error {
+			if p[0] >= p[1] {
+				return fmt.Errorf("fast period %g must be shorter than slow period %g", p[0], p[1])
+			}
+			return nil
+		},
+		Lookback: func(p []float64) int { return period(p, 1) + period(p, 2) },
+		Compute: func(in Inputs, p []float64) []Series {
+			macd := CalculateMACDSeries(in.Closes, period(p, 0), period(p, 1), period(p, 2))
+			if macd == nil {
+				return nil
+			}
+			return []Series{macd.MACDLine, macd.SignalLine, macd.Histogram}
+		},
+	})
+
+	Register(Definition{
+		Name:        "bb",
+		Description: "Bollinger Bands of closes",
+		Params: []Param{
+			{Name: "period", Default: 20, Min: 2, Max: 500, Integer: true},
+			{Name: "multiplier", Default: 2, Min: 0.1, Max: 10},
+		},
+		Inputs:   []string{"close"},
+		Outputs:  []string{"upper", "middle", "lower", "width"},
+		Lookback: func(p []float64) int { return period(p, 0) },
+		Compute: func(in Inputs, p []float64) []Series {
+			bb := CalculateBollingerBandsSeries(in.Closes, period(p, 0), p[1])
+			if bb == nil {
+				return nil
+			}
+			return []Series{bb.Upper, bb.Middle, bb.Lower, bb.Width}
+		},
+	})
+
+	Register(Definition{
+		Name:        "stoch",
+		Description: "Stochastic Oscillator",
+		Params: []Param{
+			{Name: "k", Default: 14, Min: 1, Max: 500, Integer: true},
+			{Name: "d", Default: 3, Min: 1, Max: 100, Integer: true},
+		},
+		Inputs:   []string{"high", "low", "close"},
+		Outputs:  []string{"k", "d"},
+		Lookback: func(p []float64) int { return period(p, 0) + period(p, 1) - 1 },
+		Compute: func(in Inputs, p []float64) []Series {
+			stoch := CalculateStochasticSeries(in.Highs, in.Lows, in.Closes, period(p, 0), period(p, 1))
+			if stoch == nil {
+				return nil
+			}
+			return []Series{stoch.K, stoch.D}
+		},
+	})
+
+	Register(Definition{
+		Name:        "adx",
+		Description: "Average Directional Index with the directional indicators",
+		Params:      []Param{{Name: "period", Default: 14, Min: 2, Max: 500, Integer: true}},
+		Inputs:      []string{"high", "low", "close"},
+		Outputs:     []string{"adx", "plus_di", "minus_di"},
+		Lookback:    func(p []float64) int { return 2 * period(p, 0) },
+		Compute: func(in Inputs, p []float64) []Series {
+			adx := CalculateADXSeries(in.Highs, in.Lows, in.Closes, period(p, 0))
+			if adx == nil {
+				return nil
+			}
+			return []Series{adx.ADX, adx.PlusDI, adx.MinusDI}
+		},
+	})
+
+	Register(Definition{
+		Name:        "ichimoku",
+		Description: "Ichimoku Kinko Hyo; senkou spans are the cloud under the latest bar, leading spans the cloud displacement bars ahead",
+		Params: []Param{
+			{Name: "tenkan", Default: 9, Min: 1, Max: 500, Integer: true},
+			{Name: "kijun", Default: 26, Min: 1, Max: 500, Integer: true},
+			{Name: "senkou_b", Default: 52, Min: 1, Max: 500, Integer: true},
+			{Name: "displacement", Default: 26, Min: 1, Max: 500, Integer: true},
+		},
+		Inputs:  []string{"high", "low"},
+		Outputs: []string{"tenkan", "kijun", "senkou_a", "senkou_b", "leading_a", "leading_b"},
+		Lookback: func(p []float64) int {
+			return max(period(p, 0), period(p, 1), period(p, 2)) + period(p, 3)
+		},
+		Compute: func(in Inputs, p []float64) []Series {
+			ichimoku := CalculateIchimokuSeries(in.Highs, in.Lows, in.Closes, period(p, 0), period(p, 1), period(p, 2), period(p, 3))
+			if ichimoku == nil {
+				return nil
+			}
+			return []Series{ichimoku.Tenkan, ichimoku.Kijun, ichimoku.SenkouA, ichimoku.SenkouB, ichimoku.LeadingA, ichimoku.LeadingB}
+		},
+	})
+
+	Register(Definition{
+		Name:        "psar",
+		Description: "Parabolic SAR; direction is 1 in an uptrend and -1 in a downtrend",
+		Params: []Param{
+			{Name: "step", Default: 0.02, Min: 0.001, Max: 1},
+			{Name: "max_step", Default: 0.2, Min: 0.001, Max: 1},
+		},
+		Inputs:  []string{"high", "low"},
+		Outputs: []string{"sar", "direction"},
+		Validate: func(p []float64) error {
+			if p[0] > p[1] {
+				return fmt.Errorf("step %g must not exceed max_step %g", p[0], p[1])
+			}
+			return nil
+		},
+		Lookback: func(p []float64) int { return 2 },
+		Compute: func(in Inputs, p []float64) []Series {
+			sar := CalculateParabolicSARSeries(in.Highs, in.Lows, p[0], p[1])
+			if sar == nil {
+				return nil
+			}
+			return []Series{sar.SAR, sar.Direction}
+		},
+	})
+
+	Register(Definition{
+		Name:        "supertrend",
+		Description: "Supertrend on the ATR; direction is 1 in an uptrend and -1 in a downtrend",
+		Params: []Param{
+			{Name: "period", Default: 10, Min: 1, Max: 500, Integer: true},
+			{Name: "multiplier", Default: 3, Min: 0.1, Max: 10},
+		},
+		Inputs:   []string{"high", "low", "close"},
+		Outputs:  []string{"value", "direction"},
+		Lookback: func(p []float64) int { return period(p, 0) + 1 },
+		Compute: func(in Inputs, p []float64) []Series {
+			st := CalculateSupertrendSeries(in.Highs, in.Lows, in.Closes, period(p, 0), p[1])
+			if st == nil {
+				return nil
+			}
+			return []Series{st.Line, st.Direction}
+		},
+	})
+
+	Register(Definition{
+		Name:        "keltner",
+		Description: "Keltner Channels: an EMA of closes with ATR bands",
+		Params: []Param{
+			{Name: "period", Default: 20, Min: 1, Max: 500, Integer: true},
+			{Name: "atr_period", Default: 10, Min: 1, Max: 500, Integer: true},
+			{Name: "multiplier", Default: 2, Min: 0.1, Max: 10},
+		},
+		Inputs:   []string{"high", "low", "close"},
+		Outputs:  []string{"upper", "middle", "lower"},
+		Lookback: func(p []float64) int { return max(period(p, 0), period(p, 1)+1) },
+		Compute: func(in Inputs, p []float64) []Series {
+			kc := CalculateKeltnerSeries(in.Highs, in.Lows, in.Closes, period(p, 0), period(p, 1), p[2])
+			if kc == nil {
+				return nil
+			}
+			return []Series{kc.Upper, kc.Middle, kc.Lower}
+		},
+	})
+
+	Register(Definition{
+		Name:        "donchian",
+		Description: "Donchian Channels: the highest high and lowest low of the period",
+		Params:      []Param{periodParam(20)},
+		Inputs:      []string{"high", "low"},
+		Outputs:     []string{"upper", "middle", "lower"},
+		Lookback:    func(p []float64) int { return period(p, 0) },
+		Compute: func(in Inputs, p []float64) []Series {
+			dc := CalculateDonchianSeries(in.Highs, in.Lows, period(p, 0))
+			if dc == nil {
+				return nil
+			}
+			return []Series{dc.Upper, dc.Middle, dc.Lower}
+		},
+	})
+
+	Register(Definition{
+		Name:        "atr",
+		Description: "Average True Range",
+		Params:      []Param{{Name: "period", Default: 14, Min: 1, Max: 500, Integer: true}},
+		Inputs:      []string{"high", "low", "close"},
+		Outputs:     []string{"value"},
+		Lookback:    func(p []float64) int { return period(p, 0) + 1 },
+		Compute: func(in Inputs, p []float64) []Series {
+			return []Series{ATR(in.Highs, in.Lows, in.Closes, period(p, 0))}
+		},
+	})
+
+	Register(Definition{
+		Name:        "vwap",
+		Description: "Volume Weighted Average Price over the loaded bars",
+		Params:      []Param{},
+		Inputs:      []string{"high", "low", "close", "volume"},
+		Outputs:     []string{"value"},
+		Lookback:    func(p []float64) int { return 1 },
+		Compute: func(in Inputs, p []float64) []Series {
+			return []Series{VWAP(in.Highs, in.Lows, in.Closes, in.Volumes)}
+		},
+	})
+
+	Register(Definition{
+		Name:        "vwma",
+		Description: "Volume weighted moving average of closes",
+		Params:      []Param{periodParam(20)},
+		Inputs:      []string{"close", "volume"},
+		Outputs:     []string{"value"},
+		Lookback:    func(p []float64) int { return period(p, 0) },
+		Compute: func(in Inputs, p []float64) []Series {
+			return []Series{VWMA(in.Closes, in.Volumes, period(p, 0))}
+		},
+	})
+
+	Register(Definition{
+		Name:        "obv",
+		Description: "On-Balance Volume over the loaded bars",
+		Params:      []Param{},
+		Inputs:      []string{"close", "volume"},
+		Outputs:     []string{"value"},
+		Lookback:    func(p []float64) int { return 1 },
+		Compute: func(in Inputs, p []float64) []Series {
+			return []Series{OBV(in.Closes, in.Volumes)}
+		},
+	})
+
+	Register(Definition{
+		Name:        "mfi",
+		Description: "Money Flow Index",
+		Params:      []Param{{Name: "period", Default: 14, Min: 2, Max: 500, Integer: true}},
+		Inputs:      []string{"high", "low", "close", "volume"},
+		Outputs:     []string{"value"},
+		Lookback:    func(p []float64) int { return period(p, 0) + 1 },
+		Compute: func(in Inputs, p []float64) []Series {
+			return []Series{MFI(in.Highs, in.Lows, in.Closes, in.Volumes, period(p, 0))}
+		},
+	})
+
+	Register(Definition{
+		Name:        "ad",
+		Description: "Accumulation/Distribution line over the loaded bars",
+		Params:      []Param{},
+		Inputs:      []string{"high", "low", "close", "volume"},
+		Outputs:     []string{"value"},
+		Lookback:    func(p []float64) int { return 1 },
+		Compute: func(in Inputs, p []float64) []Series {
+			return []Series{AccumulationDistribution(in.Highs, in.Lows, in.Closes, in.Volumes)}
+		},
+	})
+
+	Register(Definition{
+		Name:        "cmf",
+		Description: "Chaikin Money Flow",
+		Params:      []Param{periodParam(20)},
+		Inputs:      []string{"high", "low", "close", "volume"},
+		Outputs:     []string{"value"},
+		Lookback:    func(p []float64) int { return period(p, 0) },
+		Compute: func(in Inputs, p []float64) []Series {
+			return []Series{CMF(in.Highs, in.Lows, in.Closes, in.Volumes, period(p, 0))}
+		},
+	})
+}
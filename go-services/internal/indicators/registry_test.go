@@ -0,0 +1,58 @@
+package indicators
+
+import "testing"
+
+func TestParseSpecs(t *testing.T) {
+	specs, err := ParseSpecs("rsi(7), SMA(200),bb(20,2.5),macd,bb(10)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"rsi(7)", "sma(200)", "bb(20,2.5)", "macd(12,26,9)", "bb(10,2)"}
+	if len(specs) != len(want) {
+		t.Fatalf("parsed %d specs, expected %d", len(specs), len(want))
+	}
+	for i, spec := range specs {
+		if spec.String() != want[i] {
+			t.Errorf("spec %d = %s, expected %s", i, spec, want[i])
+		}
+	}
+
+	for _, bad := range []string{"foo(3)", "rsi(1)", "rsi(7.5)", "rsi(7,2)", "sma(x)", "bb(20,2.5"} {
+		if _, err := ParseSpecs(bad); err == nil {
+			t.Errorf("ParseSpecs(%q) succeeded, expected an error", bad)
+		}
+	}
+}
+
+func TestSpecMatchesFunctions(t *testing.T) {
+	bars := randomBars(120)
+	highs, lows, closes, volumes := splitBars(bars)
+	in := Inputs{Highs: highs, Lows: lows, Closes: closes, Volumes: volumes}
+
+	specs, err := ParseSpecs("rsi(7),bb(20,2.5),adx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rsi := specs[0].Latest(in)["value"]; rsi == nil || !almostEqual(*rsi, RSILatest(closes, 7)) {
+		t.Errorf("rsi(7) = %v, expected %v", rsi, RSILatest(closes, 7))
+	}
+
+	bb := specs[1].Latest(in)
+	want := CalculateBollingerBands(closes, 20, 2.5)
+	if bb["upper"] == nil || !almostEqual(*bb["upper"], want.Upper) || !almostEqual(*bb["lower"], want.Lower) {
+		t.Errorf("bb(20,2.5) = %v, expected %+v", bb, want)
+	}
+
+	// Too few bars leave every output null
+	short := Inputs{Highs: highs[:20], Lows: lows[:20], Closes: closes[:20], Volumes: volumes[:20]}
+	if specs[2].Lookback() != 28 {
+		t.Errorf("adx lookback = %d, expected 28", specs[2].Lookback())
+	}
+	for name, v := range specs[2].Latest(short) {
+		if v != nil {
+			t.Errorf("adx %s = %v on 20 bars, expected null", name, *v)
+		}
+	}
+}
@@ -1,17 +1,14 @@
 package indicators
 
+import "math"
+
 // RSI calculates the Relative Strength Index for the entire series
-func RSI(closes []float64, period int) []float64 {
+func RSI(closes []float64, period int) Series {
 	if len(closes) < period+1 {
 		return nil
 	}
 
-	result := make([]float64, len(closes))
-
-	// Initialize with zeros for invalid periods
-	for i := 0; i < period; i++ {
-		result[i] = 0
-	}
+	result := newSeries(len(closes))
 
 	var gains, losses float64
 
@@ -60,11 +57,74 @@ func RSI(closes []float64, period int) []float64 {
 	return result
 }
 
-// RSILatest returns the most recent RSI value
+// RSILatest returns the most recent RSI value, NaN without a full period
 func RSILatest(closes []float64, period int) float64 {
-	rsi := RSI(closes, period)
-	if rsi == nil || len(rsi) == 0 {
-		return 0
+	return RSI(closes, period).Last()
+}
+
+// RSIState is the streaming counterpart of RSI
+type RSIState struct {
+	Period    int     `json:"period"`
+	Count     int     `json:"count"`
+	PrevClose float64 `json:"prev_close"`
+	AvgGain   float64 `json:"avg_gain"`
+	AvgLoss   float64 `json:"avg_loss"`
+}
+
+// NewRSIState creates an RSI state
+func NewRSIState(period int) *RSIState {
+	return &RSIState{Period: period}
+}
+
+// Update advances the RSI by a closed bar
+func (s *RSIState) Update(bar Bar) float64 {
+	s.Count++
+	change := bar.Close - s.PrevClose
+	s.PrevClose = bar.Close
+	if s.Count == 1 {
+		return math.NaN()
+	}
+
+	var gain, loss float64
+	if change > 0 {
+		gain = change
+	} else {
+		loss = -change
+	}
+
+	// The first period of changes is averaged, later ones are smoothed
+	changes := s.Count - 1
+	switch {
+	case changes < s.Period:
+		s.AvgGain += gain
+		s.AvgLoss += loss
+		return math.NaN()
+	case changes == s.Period:
+		s.AvgGain = (s.AvgGain + gain) / float64(s.Period)
+		s.AvgLoss = (s.AvgLoss + loss) / float64(s.Period)
+	default:
+		s.AvgGain = (s.AvgGain*float64(s.Period-1) + gain) / float64(s.Period)
+		s.AvgLoss = (s.AvgLoss*float64(s.Period-1) + loss) / float64(s.Period)
 	}
-	return rsi[len(rsi)-1]
+
+	if s.AvgLoss == 0 {
+		return 100
+	}
+	rs := s.AvgGain / s.AvgLoss
+	return 100.0 - (100.0 / (1.0 + rs))
+}
+
+// Peek returns the RSI a forming bar would give
+func (s *RSIState) Peek(bar Bar) float64 {
+	return s.clone().Update(bar)
+}
+
+// Ready reports whether a full period of changes has been seen
+func (s *RSIState) Ready() bool {
+	return s.Count > s.Period
+}
+
+func (s *RSIState) clone() *RSIState {
+	c := *s
+	return &c
 }
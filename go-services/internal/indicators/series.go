@@ -0,0 +1,66 @@
+package indicators
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// Series holds one indicator value per input bar. Bars before the indicator
+// has enough history are NaN and encode as JSON null, so a warming-up
+// indicator is never mistaken for a zero reading.
+type Series []float64
+
+// newSeries returns a series of n invalid values
+func newSeries(n int) Series {
+	s := make(Series, n)
+	for i := range s {
+		s[i] = math.NaN()
+	}
+	return s
+}
+
+// Valid reports whether the value at i is valid
+func (s Series) Valid(i int) bool {
+	return i >= 0 && i < len(s) && IsValid(s[i])
+}
+
+// FirstValid returns the index of the first valid value, or -1 if there is none
+func (s Series) FirstValid() int {
+	for i, v := range s {
+		if IsValid(v) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Last returns the latest value, NaN for an empty series
+func (s Series) Last() float64 {
+	if len(s) == 0 {
+		return math.NaN()
+	}
+	return s[len(s)-1]
+}
+
+// MarshalJSON encodes invalid values as null
+func (s Series) MarshalJSON() ([]byte, error) {
+	values := make([]*float64, len(s))
+	for i := range s {
+		values[i] = Nullable(s[i])
+	}
+	return json.Marshal(values)
+}
+
+// IsValid reports whether an indicator value is valid
+func IsValid(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// Nullable returns a pointer to a valid value and nil for an invalid one, for
+// fields that encode a warming-up indicator as JSON null
+func Nullable(v float64) *float64 {
+	if !IsValid(v) {
+		return nil
+	}
+	return &v
+}
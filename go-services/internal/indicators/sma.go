@@ -1,27 +1,29 @@
 package indicators
 
-// SMA calculates Simple Moving Average
-func SMA(prices []float64, period int) []float64 {
+import "math"
+
+// SMA calculates Simple Moving Average. Leading invalid prices, such as the
+// warm-up of another indicator, are skipped.
+func SMA(prices []float64, period int) Series {
 	if len(prices) < period {
 		return nil
 	}
 
-	result := make([]float64, len(prices))
-
-	// Initialize with NaN-like behavior (0 for invalid periods)
-	for i := 0; i < period-1; i++ {
-		result[i] = 0
+	result := newSeries(len(prices))
+	start := Series(prices).FirstValid()
+	if start < 0 || len(prices)-start < period {
+		return result
 	}
 
 	// Calculate first SMA
 	var sum float64
-	for i := 0; i < period; i++ {
+	for i := start; i < start+period; i++ {
 		sum += prices[i]
 	}
-	result[period-1] = sum / float64(period)
+	result[start+period-1] = sum / float64(period)
 
 	// Calculate remaining SMAs using sliding window
-	for i := period; i < len(prices); i++ {
+	for i := start + period; i < len(prices); i++ {
 		sum = sum - prices[i-period] + prices[i]
 		result[i] = sum / float64(period)
 	}
@@ -29,10 +31,10 @@ func SMA(prices []float64, period int) []float64 {
 	return result
 }
 
-// SMALatest returns the most recent SMA value
+// SMALatest returns the most recent SMA value, NaN without a full period
 func SMALatest(prices []float64, period int) float64 {
 	if len(prices) < period {
-		return 0
+		return math.NaN()
 	}
 
 	var sum float64
@@ -42,3 +44,49 @@ func SMALatest(prices []float64, period int) float64 {
 	}
 	return sum / float64(period)
 }
+
+// SMAState is the streaming counterpart of SMA over closes
+type SMAState struct {
+	Period int     `json:"period"`
+	Window window  `json:"window"`
+	Sum    float64 `json:"sum"`
+}
+
+// NewSMAState creates an SMA state
+func NewSMAState(period int) *SMAState {
+	return &SMAState{Period: period, Window: newWindow(period)}
+}
+
+// Update advances the SMA by a closed bar
+func (s *SMAState) Update(bar Bar) float64 {
+	return s.add(bar.Close)
+}
+
+// Peek returns the SMA a forming bar would give
+func (s *SMAState) Peek(bar Bar) float64 {
+	return s.clone().Update(bar)
+}
+
+// Ready reports whether a full period has been seen
+func (s *SMAState) Ready() bool {
+	return s.Window.Full
+}
+
+// add advances the SMA by any value
+func (s *SMAState) add(v float64) float64 {
+	if old, evicted := s.Window.push(v); evicted {
+		s.Sum = s.Sum - old + v
+	} else {
+		s.Sum += v
+	}
+	if !s.Window.Full {
+		return math.NaN()
+	}
+	return s.Sum / float64(s.Period)
+}
+
+func (s *SMAState) clone() *SMAState {
+	c := *s
+	c.Window = s.Window.clone()
+	return &c
+}
@@ -1,5 +1,7 @@
 package indicators
 
+import "math"
+
 // Stochastic represents Stochastic Oscillator values
 type Stochastic struct {
 	K float64 `json:"k"`
@@ -8,8 +10,8 @@ type Stochastic struct {
 
 // StochasticSeries represents Stochastic values for entire series
 type StochasticSeries struct {
-	K []float64
-	D []float64
+	K Series
+	D Series
 }
 
 // CalculateStochastic calculates Stochastic Oscillator and returns latest values
@@ -23,7 +25,7 @@ func CalculateStochastic(highs, lows, closes []float64, kPeriod, dPeriod int) *S
 		return nil
 	}
 
-	// Calculate %D (SMA of %K)
+	// Calculate %D (SMA of %K, from the first valid %K)
 	dValues := SMA(kValues, dPeriod)
 	if dValues == nil {
 		return nil
@@ -58,17 +60,13 @@ func CalculateStochasticSeries(highs, lows, closes []float64, kPeriod, dPeriod i
 }
 
 // calculateRawK calculates raw %K values
-func calculateRawK(highs, lows, closes []float64, period int) []float64 {
+func calculateRawK(highs, lows, closes []float64, period int) Series {
 	n := len(closes)
 	if n < period {
 		return nil
 	}
 
-	kValues := make([]float64, n)
-
-	for i := 0; i < period-1; i++ {
-		kValues[i] = 0
-	}
+	kValues := newSeries(n)
 
 	for i := period - 1; i < n; i++ {
 		// Find highest high and lowest low in period
@@ -95,3 +93,64 @@ func calculateRawK(highs, lows, closes []float64, period int) []float64 {
 
 	return kValues
 }
+
+// StochasticState is the streaming counterpart of CalculateStochasticSeries
+type StochasticState struct {
+	Highs window    `json:"highs"`
+	Lows  window    `json:"lows"`
+	D     *SMAState `json:"d"`
+	Count int       `json:"count"`
+}
+
+// NewStochasticState creates a Stochastic Oscillator state
+func NewStochasticState(kPeriod, dPeriod int) *StochasticState {
+	return &StochasticState{
+		Highs: newWindow(kPeriod),
+		Lows:  newWindow(kPeriod),
+		D:     NewSMAState(dPeriod),
+	}
+}
+
+// Update advances the oscillator by a closed bar
+func (s *StochasticState) Update(bar Bar) Stochastic {
+	s.Count++
+	s.Highs.push(bar.High)
+	s.Lows.push(bar.Low)
+
+	// %K needs a full period, %D a period of valid %K
+	if !s.Highs.Full {
+		return Stochastic{K: math.NaN(), D: math.NaN()}
+	}
+
+	highestHigh, lowestLow := s.Highs.Values[0], s.Lows.Values[0]
+	for i := range s.Highs.Values {
+		highestHigh = math.Max(highestHigh, s.Highs.Values[i])
+		lowestLow = math.Min(lowestLow, s.Lows.Values[i])
+	}
+
+	k := 50.0 // Neutral when no range
+	if diff := highestHigh - lowestLow; diff != 0 {
+		k = ((bar.Close - lowestLow) / diff) * 100
+	}
+
+	return Stochastic{K: k, D: s.D.add(k)}
+}
+
+// Peek returns the oscillator a forming bar would give
+func (s *StochasticState) Peek(bar Bar) Stochastic {
+	return s.clone().Update(bar)
+}
+
+// Ready reports whether CalculateStochastic would return values
+func (s *StochasticState) Ready() bool {
+	return s.Count >= len(s.Highs.Values)+s.D.Period-1
+}
+
+func (s *StochasticState) clone() *StochasticState {
+	return &StochasticState{
+		Highs: s.Highs.clone(),
+		Lows:  s.Lows.clone(),
+		D:     s.D.clone(),
+		Count: s.Count,
+	}
+}
@@ -0,0 +1,158 @@
+package indicators
+
+// Streaming indicators
+//
+// Every batch function in this package has a state counterpart that consumes
+// one bar at a time. Update advances the state by a closed bar and returns the
+// value the batch function gives at that index; Peek returns the value a bar
+// would give without advancing, for a bar that is still forming. Each step
+// costs at most O(period), independent of how much history came before.
+//
+// States are plain structs with exported fields so they serialize to JSON and
+// can be stored between bars, e.g. in Redis. Values are NaN during warm-up,
+// as in the batch functions; Ready reports when they are valid.
+
+// Bar is the input of the streaming indicators
+type Bar struct {
+	High   float64 `json:"high"`
+	Low    float64 `json:"low"`
+	Close  float64 `json:"close"`
+	Volume int64   `json:"volume"`
+}
+
+// window is a fixed-size ring of the most recent values
+type window struct {
+	Values []float64 `json:"values"`
+	Next   int       `json:"next"`
+	Full   bool      `json:"full"`
+}
+
+func newWindow(size int) window {
+	return window{Values: make([]float64, size)}
+}
+
+// push adds v, returning the value it evicted and whether there was one
+func (w *window) push(v float64) (float64, bool) {
+	old, evicted := w.Values[w.Next], w.Full
+	w.Values[w.Next] = v
+	w.Next++
+	if w.Next == len(w.Values) {
+		w.Next = 0
+		w.Full = true
+	}
+	return old, evicted
+}
+
+// clone returns a copy that shares no memory with w
+func (w window) clone() window {
+	w.Values = append([]float64(nil), w.Values...)
+	return w
+}
+
+// StreamSet holds the streaming state of every indicator used by technical
+// analysis, with the same periods
+type StreamSet struct {
+	Bars       int              `json:"bars"`
+	RSI        *RSIState        `json:"rsi"`
+	MACD       *MACDState       `json:"macd"`
+	Bollinger  *BollingerState  `json:"bollinger"`
+	Stochastic *StochasticState `json:"stochastic"`
+	ADX        *ADXState        `json:"adx"`
+	SMA20      *SMAState        `json:"sma_20"`
+	SMA50      *SMAState        `json:"sma_50"`
+	EMA12      *EMAState        `json:"ema_12"`
+	EMA26      *EMAState        `json:"ema_26"`
+	ATR        *ATRState        `json:"atr"`
+	VWAP       *VWAPState       `json:"vwap"`
+}
+
+// Snapshot is the value of every indicator in a StreamSet after a bar.
+// Indicators still warming up are nil.
+type Snapshot struct {
+	RSI        *float64        `json:"rsi"`
+	MACD       *MACD           `json:"macd"`
+	Bollinger  *BollingerBands `json:"bollinger"`
+	Stochastic *Stochastic     `json:"stochastic"`
+	ADX        *ADX            `json:"adx"`
+	SMA20      *float64        `json:"sma_20"`
+	SMA50      *float64        `json:"sma_50"`
+	EMA12      *float64        `json:"ema_12"`
+	EMA26      *float64        `json:"ema_26"`
+	ATR        *float64        `json:"atr"`
+	VWAP       *float64        `json:"vwap"`
+}
+
+// NewStreamSet creates the indicator states of technical analysis
+func NewStreamSet() *StreamSet {
+	return &StreamSet{
+		RSI:        NewRSIState(14),
+		MACD:       NewMACDState(12, 26, 9),
+		Bollinger:  NewBollingerState(20, 2.0),
+		Stochastic: NewStochasticState(14, 3),
+		ADX:        NewADXState(14),
+		SMA20:      NewSMAState(20),
+		SMA50:      NewSMAState(50),
+		EMA12:      NewEMAState(12),
+		EMA26:      NewEMAState(26),
+		ATR:        NewATRState(14),
+		VWAP:       NewVWAPState(),
+	}
+}
+
+// Update advances every state by a closed bar
+func (s *StreamSet) Update(bar Bar) Snapshot {
+	s.Bars++
+	snap := Snapshot{
+		RSI:   Nullable(s.RSI.Update(bar)),
+		SMA20: Nullable(s.SMA20.Update(bar)),
+		SMA50: Nullable(s.SMA50.Update(bar)),
+		EMA12: Nullable(s.EMA12.Update(bar)),
+		EMA26: Nullable(s.EMA26.Update(bar)),
+		ATR:   Nullable(s.ATR.Update(bar)),
+		VWAP:  Nullable(s.VWAP.Update(bar)),
+	}
+	macd := s.MACD.Update(bar)
+	bb := s.Bollinger.Update(bar)
+	stoch := s.Stochastic.Update(bar)
+	adx := s.ADX.Update(bar)
+	s.fill(&snap, macd, bb, stoch, adx)
+	return snap
+}
+
+// Peek returns the snapshot a forming bar would give without advancing
+func (s *StreamSet) Peek(bar Bar) Snapshot {
+	return s.clone().Update(bar)
+}
+
+// fill sets the multi-value indicators of a snapshot once they are ready
+func (s *StreamSet) fill(snap *Snapshot, macd MACD, bb BollingerBands, stoch Stochastic, adx ADX) {
+	if s.MACD.Ready() {
+		snap.MACD = &macd
+	}
+	if s.Bollinger.Ready() {
+		snap.Bollinger = &bb
+	}
+	if s.Stochastic.Ready() {
+		snap.Stochastic = &stoch
+	}
+	if s.ADX.Ready() {
+		snap.ADX = &adx
+	}
+}
+
+func (s *StreamSet) clone() *StreamSet {
+	return &StreamSet{
+		Bars:       s.Bars,
+		RSI:        s.RSI.clone(),
+		MACD:       s.MACD.clone(),
+		Bollinger:  s.Bollinger.clone(),
+		Stochastic: s.Stochastic.clone(),
+		ADX:        s.ADX.clone(),
+		SMA20:      s.SMA20.clone(),
+		SMA50:      s.SMA50.clone(),
+		EMA12:      s.EMA12.clone(),
+		EMA26:      s.EMA26.clone(),
+		ATR:        s.ATR.clone(),
+		VWAP:       s.VWAP.clone(),
+	}
+}
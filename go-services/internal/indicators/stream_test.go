@@ -0,0 +1,141 @@
+package indicators
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+)
+
+// randomBars returns a reproducible random walk
+func randomBars(n int) []Bar {
+	rng := rand.New(rand.NewSource(7))
+	bars := make([]Bar, n)
+	price := 100.0
+	for i := range bars {
+		price *= 1 + 0.02*rng.NormFloat64()
+		spread := price * 0.01 * rng.Float64()
+		bars[i] = Bar{
+			High:   price + spread,
+			Low:    price - spread,
+			Close:  price - spread + 2*spread*rng.Float64(),
+			Volume: rng.Int63n(100000),
+		}
+	}
+	return bars
+}
+
+func splitBars(bars []Bar) (highs, lows, closes []float64, volumes []int64) {
+	for _, b := range bars {
+		highs = append(highs, b.High)
+		lows = append(lows, b.Low)
+		closes = append(closes, b.Close)
+		volumes = append(volumes, b.Volume)
+	}
+	return highs, lows, closes, volumes
+}
+
+func TestStreamingMatchesBatch(t *testing.T) {
+	bars := randomBars(300)
+	highs, lows, closes, volumes := splitBars(bars)
+
+	sma := SMA(closes, 20)
+	ema := EMA(closes, 12)
+	rsi := RSI(closes, 14)
+	atr := ATR(highs, lows, closes, 14)
+	vwap := VWAP(highs, lows, closes, volumes)
+	macd := CalculateMACDSeries(closes, 12, 26, 9)
+	bb := CalculateBollingerBandsSeries(closes, 20, 2)
+	stoch := CalculateStochasticSeries(highs, lows, closes, 14, 3)
+
+	smaState, emaState, rsiState := NewSMAState(20), NewEMAState(12), NewRSIState(14)
+	atrState, vwapState := NewATRState(14), NewVWAPState()
+	macdState, bbState := NewMACDState(12, 26, 9), NewBollingerState(20, 2)
+	stochState, adxState := NewStochasticState(14, 3), NewADXState(14)
+
+	check := func(name string, i int, got, want float64) {
+		t.Helper()
+		if IsValid(got) != IsValid(want) || IsValid(want) && !almostEqual(got, want) {
+			t.Errorf("%s[%d] = %v, batch gives %v", name, i, got, want)
+		}
+	}
+
+	for i, bar := range bars {
+		check("SMA", i, smaState.Update(bar), sma[i])
+		check("EMA", i, emaState.Update(bar), ema[i])
+		check("RSI", i, rsiState.Update(bar), rsi[i])
+		check("ATR", i, atrState.Update(bar), atr[i])
+		check("VWAP", i, vwapState.Update(bar), vwap[i])
+
+		m := macdState.Update(bar)
+		check("MACD", i, m.MACDLine, macd.MACDLine[i])
+		check("MACD signal", i, m.SignalLine, macd.SignalLine[i])
+		check("MACD histogram", i, m.Histogram, macd.Histogram[i])
+
+		b := bbState.Update(bar)
+		check("BB upper", i, b.Upper, bb.Upper[i])
+		check("BB lower", i, b.Lower, bb.Lower[i])
+		check("BB width", i, b.Width, bb.Width[i])
+
+		s := stochState.Update(bar)
+		check("%K", i, s.K, stoch.K[i])
+		check("%D", i, s.D, stoch.D[i])
+
+		a := adxState.Update(bar)
+		if want := CalculateADX(highs[:i+1], lows[:i+1], closes[:i+1], 14); want != nil {
+			check("ADX", i, a.ADX, want.ADX)
+			check("+DI", i, a.PlusDI, want.PlusDI)
+			check("-DI", i, a.MinusDI, want.MinusDI)
+		} else if adxState.Ready() {
+			t.Errorf("ADX ready at %d, batch is not", i)
+		}
+	}
+}
+
+func TestStreamSetSurvivesJSON(t *testing.T) {
+	bars := randomBars(120)
+
+	whole := NewStreamSet()
+	for _, bar := range bars[:len(bars)-1] {
+		whole.Update(bar)
+	}
+
+	// Store the state halfway, as between two live bars
+	split := NewStreamSet()
+	for _, bar := range bars[:60] {
+		split.Update(bar)
+	}
+	data, err := json.Marshal(split)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := &StreamSet{}
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	for _, bar := range bars[60 : len(bars)-1] {
+		restored.Update(bar)
+	}
+
+	last := bars[len(bars)-1]
+	peeked := restored.Peek(last)
+	want := whole.Update(last)
+	got := restored.Update(last)
+
+	for _, snap := range []Snapshot{peeked, got} {
+		if snap.MACD == nil || snap.Bollinger == nil || snap.Stochastic == nil || snap.ADX == nil {
+			t.Fatalf("indicators not ready after %d bars: %+v", len(bars), snap)
+		}
+		if !almostEqual(*snap.RSI, *want.RSI) || !almostEqual(*snap.SMA50, *want.SMA50) ||
+			!almostEqual(snap.MACD.SignalLine, want.MACD.SignalLine) ||
+			!almostEqual(snap.Stochastic.D, want.Stochastic.D) ||
+			!almostEqual(snap.ADX.ADX, want.ADX.ADX) {
+			t.Errorf("snapshot %+v differs from uninterrupted %+v", snap, want)
+		}
+	}
+
+	// The closes seen since the halfway point must be reflected
+	_, _, closes, _ := splitBars(bars)
+	if sma := SMALatest(closes, 50); !almostEqual(*got.SMA50, sma) {
+		t.Errorf("SMA50 = %v, batch gives %v", *got.SMA50, sma)
+	}
+}
@@ -0,0 +1,80 @@
+package indicators
+
+// Supertrend represents the latest Supertrend values
+type Supertrend struct {
+	// Value is the trailing line: below the price in an uptrend, above it in
+	// a downtrend
+	Value   float64 `json:"value"`
+	Uptrend bool    `json:"uptrend"`
+	// Reversal marks a trend that flipped at the latest bar
+	Reversal bool `json:"reversal"`
+}
+
+// SupertrendSeries represents Supertrend values for entire series. Direction
+// is 1 in an uptrend and -1 in a downtrend.
+type SupertrendSeries struct {
+	Line      Series
+	Direction Series
+}
+
+// CalculateSupertrend calculates Supertrend and returns latest values
+func CalculateSupertrend(highs, lows, closes []float64, period int, multiplier float64) *Supertrend {
+	series := CalculateSupertrendSeries(highs, lows, closes, period, multiplier)
+	if series == nil {
+		return nil
+	}
+
+	n := len(closes)
+	return &Supertrend{
+		Value:    series.Line[n-1],
+		Uptrend:  series.Direction[n-1] > 0,
+		Reversal: series.Direction.Valid(n-2) && series.Direction[n-1] != series.Direction[n-2],
+	}
+}
+
+// CalculateSupertrendSeries calculates Supertrend for entire series: bands of
+// multiplier ATRs around the middle of each bar that only move in the
+// direction of the trend, flipping when a close crosses them
+func CalculateSupertrendSeries(highs, lows, closes []float64, period int, multiplier float64) *SupertrendSeries {
+	atr := ATR(highs, lows, closes, period)
+	first := atr.FirstValid()
+	if first < 0 {
+		return nil
+	}
+
+	n := len(closes)
+	line, direction := newSeries(n), newSeries(n)
+	var upper, lower float64
+	up := true
+
+	for i := first; i < n; i++ {
+		mid := (highs[i] + lows[i]) / 2
+		basicUpper, basicLower := mid+multiplier*atr[i], mid-multiplier*atr[i]
+
+		if i == first {
+			upper, lower = basicUpper, basicLower
+		} else {
+			if basicUpper < upper || closes[i-1] > upper {
+				upper = basicUpper
+			}
+			if basicLower > lower || closes[i-1] < lower {
+				lower = basicLower
+			}
+		}
+
+		if up && closes[i] < lower {
+			up = false
+		} else if !up && closes[i] > upper {
+			up = true
+		}
+
+		if up {
+			line[i] = lower
+		} else {
+			line[i] = upper
+		}
+		direction[i] = trendDirection(up)
+	}
+
+	return &SupertrendSeries{Line: line, Direction: direction}
+}
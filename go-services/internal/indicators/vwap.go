@@ -1,13 +1,16 @@
 package indicators
 
-// VWAP calculates Volume Weighted Average Price
-func VWAP(highs, lows, closes []float64, volumes []int64) []float64 {
+import "math"
+
+// VWAP calculates Volume Weighted Average Price. Bars before any volume has
+// traded have no VWAP.
+func VWAP(highs, lows, closes []float64, volumes []int64) Series {
 	n := len(closes)
 	if n == 0 || len(highs) != n || len(lows) != n || len(volumes) != n {
 		return nil
 	}
 
-	vwap := make([]float64, n)
+	vwap := newSeries(n)
 	var cumulativeTPV float64 // Cumulative Typical Price * Volume
 	var cumulativeVolume float64
 
@@ -26,11 +29,49 @@ func VWAP(highs, lows, closes []float64, volumes []int64) []float64 {
 	return vwap
 }
 
-// VWAPLatest returns the most recent VWAP value
+// VWAPLatest returns the most recent VWAP value, NaN without volume
 func VWAPLatest(highs, lows, closes []float64, volumes []int64) float64 {
-	vwap := VWAP(highs, lows, closes, volumes)
-	if vwap == nil || len(vwap) == 0 {
-		return 0
+	return VWAP(highs, lows, closes, volumes).Last()
+}
+
+// VWAPState is the streaming counterpart of VWAP
+type VWAPState struct {
+	CumulativeTPV    float64 `json:"cumulative_tpv"`
+	CumulativeVolume float64 `json:"cumulative_volume"`
+}
+
+// NewVWAPState creates a VWAP state
+func NewVWAPState() *VWAPState {
+	return &VWAPState{}
+}
+
+// Update advances the VWAP by a closed bar
+func (s *VWAPState) Update(bar Bar) float64 {
+	typicalPrice := (bar.High + bar.Low + bar.Close) / 3
+	s.CumulativeTPV += typicalPrice * float64(bar.Volume)
+	s.CumulativeVolume += float64(bar.Volume)
+	if s.CumulativeVolume == 0 {
+		return math.NaN()
 	}
-	return vwap[len(vwap)-1]
+	return s.CumulativeTPV / s.CumulativeVolume
+}
+
+// Peek returns the VWAP a forming bar would give
+func (s *VWAPState) Peek(bar Bar) float64 {
+	return s.clone().Update(bar)
+}
+
+// Ready reports whether any volume has traded
+func (s *VWAPState) Ready() bool {
+	return s.CumulativeVolume > 0
+}
+
+// Reset starts a new anchor, e.g. a new session
+func (s *VWAPState) Reset() {
+	*s = VWAPState{}
+}
+
+func (s *VWAPState) clone() *VWAPState {
+	c := *s
+	return &c
 }
@@ -0,0 +1,32 @@
+package indicators
+
+// VWMA calculates the Volume Weighted Moving Average of closes. Windows
+// without volume are invalid.
+func VWMA(closes []float64, volumes []int64, period int) Series {
+	n := len(closes)
+	if n < period || len(volumes) != n {
+		return nil
+	}
+
+	vwma := newSeries(n)
+	var priceVolume, volumeSum float64
+	for i := 0; i < n; i++ {
+		priceVolume += closes[i] * float64(volumes[i])
+		volumeSum += float64(volumes[i])
+		if i >= period {
+			priceVolume -= closes[i-period] * float64(volumes[i-period])
+			volumeSum -= float64(volumes[i-period])
+		}
+		if i >= period-1 && volumeSum > 0 {
+			vwma[i] = priceVolume / volumeSum
+		}
+	}
+
+	return vwma
+}
+
+// VWMALatest returns the most recent VWMA value, NaN without a full period of
+// volume
+func VWMALatest(closes []float64, volumes []int64, period int) float64 {
+	return VWMA(closes, volumes, period).Last()
+}
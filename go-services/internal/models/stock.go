@@ -6,19 +6,135 @@ import (
 	"gorm.io/gorm"
 )
 
-type Stock struct {
-	Symbol    string    `gorm:"primaryKey;size:10" json:"symbol"`
-	Name      string    `gorm:"size:255;not null" json:"name"`
-	Exchange  string    `gorm:"size:10;not null" json:"exchange"`
-	Industry  string    `gorm:"size:100" json:"industry"`
-	IsActive  bool      `gorm:"default:true" json:"is_active"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+// Instrument is an entry of the instrument master: a listed security or a
+// market index. Covered warrants and futures are delisted after their last
+// trading day.
+type Instrument struct {
+	Symbol       string     `gorm:"primaryKey;size:10" json:"symbol"`
+	Name         string     `gorm:"size:255;not null" json:"name"`
+	Type         string     `gorm:"size:10;not null;default:stock;index" json:"type"`
+	Exchange     string     `gorm:"size:10;not null" json:"exchange"`
+	Industry     string     `gorm:"size:100" json:"industry"`
+	Underlying   string     `gorm:"size:10" json:"underlying,omitempty"`
+	ListedDate   *time.Time `json:"listed_date,omitempty"`
+	DelistedDate *time.Time `json:"delisted_date,omitempty"`
+	IsActive     bool       `gorm:"default:true" json:"is_active"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// OHLCVBar is a locally stored price bar, unique per (symbol, resolution, date)
+type OHLCVBar struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Symbol     string    `gorm:"size:10;not null;uniqueIndex:idx_ohlcv_symbol_res_date" json:"symbol"`
+	Resolution string    `gorm:"size:5;not null;uniqueIndex:idx_ohlcv_symbol_res_date" json:"resolution"`
+	Date       time.Time `gorm:"not null;uniqueIndex:idx_ohlcv_symbol_res_date" json:"date"`
+	Open       float64   `gorm:"type:decimal(12,2)" json:"open"`
+	High       float64   `gorm:"type:decimal(12,2)" json:"high"`
+	Low        float64   `gorm:"type:decimal(12,2)" json:"low"`
+	Close      float64   `gorm:"type:decimal(12,2)" json:"close"`
+	Volume     int64     `json:"volume"`
+	Value      float64   `gorm:"type:decimal(20,0)" json:"value"`
+	Source     string    `gorm:"size:20" json:"source,omitempty"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// BarMismatch records a bar on which two market data sources disagree
+type BarMismatch struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	Symbol         string    `gorm:"size:10;not null;index:idx_mismatch_symbol_date" json:"symbol"`
+	Resolution     string    `gorm:"size:5;not null;index:idx_mismatch_symbol_date" json:"resolution"`
+	Date           time.Time `gorm:"not null;index:idx_mismatch_symbol_date" json:"date"`
+	Field          string    `gorm:"size:10;not null" json:"field"`
+	Source         string    `gorm:"size:20;not null" json:"source"`
+	Reference      string    `gorm:"size:20;not null" json:"reference"`
+	SourceValue    float64   `gorm:"type:decimal(20,2)" json:"source_value"`
+	ReferenceValue float64   `gorm:"type:decimal(20,2)" json:"reference_value"`
+	DiffPercent    float64   `gorm:"type:decimal(10,4)" json:"diff_percent"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// CorporateAction is a dividend, rights issue or split affecting a symbol's price history
+type CorporateAction struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	Symbol            string    `gorm:"size:10;not null;uniqueIndex:idx_ca_symbol_type_exdate" json:"symbol"`
+	Type              string    `gorm:"size:20;not null;uniqueIndex:idx_ca_symbol_type_exdate" json:"type"`
+	ExDate            time.Time `gorm:"not null;uniqueIndex:idx_ca_symbol_type_exdate" json:"ex_date"`
+	Ratio             float64   `gorm:"type:decimal(12,6)" json:"ratio"`
+	CashAmount        float64   `gorm:"type:decimal(12,2)" json:"cash_amount"`
+	SubscriptionPrice float64   `gorm:"type:decimal(12,2)" json:"subscription_price"`
+	Description       string    `gorm:"type:text" json:"description"`
+	CreatedAt         time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// InvestorFlow is a stored session of foreign and proprietary trading, unique per (symbol, date)
+type InvestorFlow struct {
+	ID                    uint      `gorm:"primaryKey" json:"id"`
+	Symbol                string    `gorm:"size:10;not null;uniqueIndex:idx_flow_symbol_date" json:"symbol"`
+	Date                  time.Time `gorm:"not null;uniqueIndex:idx_flow_symbol_date" json:"date"`
+	ForeignBuyVolume      int64     `json:"foreign_buy_volume"`
+	ForeignSellVolume     int64     `json:"foreign_sell_volume"`
+	ForeignBuyValue       float64   `gorm:"type:decimal(20,0)" json:"foreign_buy_value"`
+	ForeignSellValue      float64   `gorm:"type:decimal(20,0)" json:"foreign_sell_value"`
+	ForeignRoom           int64     `json:"foreign_room"`
+	ProprietaryBuyVolume  int64     `json:"proprietary_buy_volume"`
+	ProprietarySellVolume int64     `json:"proprietary_sell_volume"`
+	ProprietaryBuyValue   float64   `gorm:"type:decimal(20,0)" json:"proprietary_buy_value"`
+	ProprietarySellValue  float64   `gorm:"type:decimal(20,0)" json:"proprietary_sell_value"`
+	CreatedAt             time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// FinancialStatement holds a company's income statement, balance sheet and
+// cash flow statement for a fiscal quarter or year, unique per (symbol, period,
+// year, quarter). Amounts are in VND.
+type FinancialStatement struct {
+	ID      uint   `gorm:"primaryKey" json:"id"`
+	Symbol  string `gorm:"size:10;not null;uniqueIndex:idx_fin_symbol_period" json:"symbol"`
+	Period  string `gorm:"size:10;not null;uniqueIndex:idx_fin_symbol_period" json:"period"`
+	Year    int    `gorm:"not null;uniqueIndex:idx_fin_symbol_period" json:"year"`
+	Quarter int    `gorm:"not null;uniqueIndex:idx_fin_symbol_period" json:"quarter"`
+
+	// Income statement
+	Revenue         float64 `gorm:"type:decimal(20,0)" json:"revenue"`
+	GrossProfit     float64 `gorm:"type:decimal(20,0)" json:"gross_profit"`
+	OperatingProfit float64 `gorm:"type:decimal(20,0)" json:"operating_profit"`
+	NetIncome       float64 `gorm:"type:decimal(20,0)" json:"net_income"`
+
+	// Balance sheet
+	TotalAssets      float64 `gorm:"type:decimal(20,0)" json:"total_assets"`
+	TotalLiabilities float64 `gorm:"type:decimal(20,0)" json:"total_liabilities"`
+	TotalDebt        float64 `gorm:"type:decimal(20,0)" json:"total_debt"`
+	Equity           float64 `gorm:"type:decimal(20,0)" json:"equity"`
+
+	// Cash flow statement
+	OperatingCashFlow  float64 `gorm:"type:decimal(20,0)" json:"operating_cash_flow"`
+	InvestingCashFlow  float64 `gorm:"type:decimal(20,0)" json:"investing_cash_flow"`
+	FinancingCashFlow  float64 `gorm:"type:decimal(20,0)" json:"financing_cash_flow"`
+	CapitalExpenditure float64 `gorm:"type:decimal(20,0)" json:"capital_expenditure"`
+
+	SharesOutstanding int64     `json:"shares_outstanding"`
+	CreatedAt         time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// Formula is a named expression of the formula language, saved so it can be
+// evaluated per symbol without a release
+type Formula struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Name        string    `gorm:"size:64;not null;uniqueIndex" json:"name"`
+	Expression  string    `gorm:"type:text;not null" json:"expression"`
+	Description string    `gorm:"type:text" json:"description"`
+	Type        string    `gorm:"size:10;not null" json:"type"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 type TechnicalAnalysis struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	Symbol    string    `gorm:"size:10;not null;index:idx_tech_symbol_time" json:"symbol"`
-	Timestamp time.Time `gorm:"not null;index:idx_tech_symbol_time" json:"timestamp"`
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Symbol     string    `gorm:"size:10;not null;index:idx_tech_symbol_time" json:"symbol"`
+	Resolution string    `gorm:"size:5;not null;default:1D" json:"resolution"`
+	PriceMode  string    `gorm:"size:10;not null;default:adjusted" json:"price_mode"`
+	Timestamp  time.Time `gorm:"not null;index:idx_tech_symbol_time" json:"timestamp"`
 
 	// Price data
 	OpenPrice  float64 `gorm:"type:decimal(12,2)" json:"open_price"`
@@ -94,6 +210,26 @@ type DailyReport struct {
 	CreatedAt            time.Time `gorm:"autoCreateTime" json:"created_at"`
 }
 
+func (OHLCVBar) TableName() string {
+	return "ohlcv_bars"
+}
+
+func (CorporateAction) TableName() string {
+	return "corporate_actions"
+}
+
+func (InvestorFlow) TableName() string {
+	return "investor_flows"
+}
+
+func (FinancialStatement) TableName() string {
+	return "financial_statements"
+}
+
+func (Formula) TableName() string {
+	return "formulas"
+}
+
 func (TechnicalAnalysis) TableName() string {
 	return "technical_analysis"
 }
@@ -104,7 +240,13 @@ func (SentimentAnalysis) TableName() string {
 
 func AutoMigrate(db *gorm.DB) error {
 	return db.AutoMigrate(
-		&Stock{},
+		&Instrument{},
+		&OHLCVBar{},
+		&BarMismatch{},
+		&CorporateAction{},
+		&InvestorFlow{},
+		&FinancialStatement{},
+		&Formula{},
 		&TechnicalAnalysis{},
 		&SentimentAnalysis{},
 		&Forecast{},
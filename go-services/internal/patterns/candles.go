@@ -0,0 +1,274 @@
+package patterns
+
+import (
+	"math"
+	"sort"
+)
+
+// CandlePattern is a candlestick pattern completed by the latest candle
+type CandlePattern struct {
+	Name string `json:"name"`
+	Bias Bias   `json:"bias"`
+	// Candles is the number of candles forming the pattern
+	Candles int `json:"candles"`
+	// Reversal patterns signal the end of the trend before them; the others
+	// its continuation or indecision
+	Reversal bool `json:"reversal"`
+	// Trend is the direction of the price before the pattern
+	Trend Trend `json:"trend"`
+	// Strength, from 0 to 1, is the reliability of the pattern in its
+	// context
+	Strength float64 `json:"strength"`
+}
+
+// candleRule defines a candlestick pattern
+type candleRule struct {
+	name        string
+	bias        Bias
+	candles     int
+	reversal    bool
+	reliability float64
+	// match reports whether c, the candles of the pattern, form it
+	match func(c []Candle, ctx context) bool
+}
+
+// Shape thresholds, as fractions of the candle range
+const (
+	dojiBody      = 0.1
+	smallBody     = 0.35
+	longShadow    = 0.6
+	shortShadow   = 0.15
+	marubozuBody  = 0.9
+	spinningBody  = 0.3
+	tweezerMargin = 0.1 // of the average range
+)
+
+// isDoji is a candle whose open and close nearly meet
+func isDoji(c Candle) bool {
+	return c.span() > 0 && c.body() <= dojiBody*c.span()
+}
+
+// isHammerShape is a small body at the top of a long lower shadow
+func isHammerShape(c Candle) bool {
+	r := c.span()
+	return r > 0 && c.body() > dojiBody*r && c.body() <= smallBody*r &&
+		c.lowerShadow() >= 2*c.body() && c.upperShadow() <= shortShadow*r
+}
+
+// isInvertedHammerShape is a small body at the bottom of a long upper shadow
+func isInvertedHammerShape(c Candle) bool {
+	r := c.span()
+	return r > 0 && c.body() > dojiBody*r && c.body() <= smallBody*r &&
+		c.upperShadow() >= 2*c.body() && c.lowerShadow() <= shortShadow*r
+}
+
+// isLong is a candle with at least an average body
+func isLong(c Candle, ctx context) bool {
+	return c.body() > 0 && c.body() >= ctx.avgBody
+}
+
+var candleRules = []candleRule{
+	{
+		name: "doji", bias: Neutral, candles: 1, reliability: 0.3,
+		match: func(c []Candle, _ context) bool {
+			return isDoji(c[0]) && c[0].upperShadow() < longShadow*c[0].span() && c[0].lowerShadow() < longShadow*c[0].span()
+		},
+	},
+	{
+		name: "dragonfly_doji", bias: Bullish, candles: 1, reversal: true, reliability: 0.5,
+		match: func(c []Candle, _ context) bool {
+			return isDoji(c[0]) && c[0].lowerShadow() >= longShadow*c[0].span() && c[0].upperShadow() <= dojiBody*c[0].span()
+		},
+	},
+	{
+		name: "gravestone_doji", bias: Bearish, candles: 1, reversal: true, reliability: 0.5,
+		match: func(c []Candle, _ context) bool {
+			return isDoji(c[0]) && c[0].upperShadow() >= longShadow*c[0].span() && c[0].lowerShadow() <= dojiBody*c[0].span()
+		},
+	},
+	{
+		name: "spinning_top", bias: Neutral, candles: 1, reliability: 0.2,
+		match: func(c []Candle, _ context) bool {
+			r, body := c[0].span(), c[0].body()
+			return r > 0 && body > dojiBody*r && body <= spinningBody*r && c[0].upperShadow() > body && c[0].lowerShadow() > body
+		},
+	},
+	{
+		name: "hammer", bias: Bullish, candles: 1, reversal: true, reliability: 0.6,
+		match: func(c []Candle, ctx context) bool {
+			return ctx.trend == Downtrend && isHammerShape(c[0])
+		},
+	},
+	{
+		name: "hanging_man", bias: Bearish, candles: 1, reversal: true, reliability: 0.5,
+		match: func(c []Candle, ctx context) bool {
+			return ctx.trend == Uptrend && isHammerShape(c[0])
+		},
+	},
+	{
+		name: "inverted_hammer", bias: Bullish, candles: 1, reversal: true, reliability: 0.5,
+		match: func(c []Candle, ctx context) bool {
+			return ctx.trend == Downtrend && isInvertedHammerShape(c[0])
+		},
+	},
+	{
+		name: "shooting_star", bias: Bearish, candles: 1, reversal: true, reliability: 0.6,
+		match: func(c []Candle, ctx context) bool {
+			return ctx.trend == Uptrend && isInvertedHammerShape(c[0])
+		},
+	},
+	{
+		name: "bullish_marubozu", bias: Bullish, candles: 1, reliability: 0.5,
+		match: func(c []Candle, ctx context) bool {
+			return c[0].bullish() && c[0].body() >= marubozuBody*c[0].span() && isLong(c[0], ctx)
+		},
+	},
+	{
+		name: "bearish_marubozu", bias: Bearish, candles: 1, reliability: 0.5,
+		match: func(c []Candle, ctx context) bool {
+			return c[0].bearish() && c[0].body() >= marubozuBody*c[0].span() && isLong(c[0], ctx)
+		},
+	},
+	{
+		name: "bullish_engulfing", bias: Bullish, candles: 2, reversal: true, reliability: 0.7,
+		match: func(c []Candle, _ context) bool {
+			prev, cur := c[0], c[1]
+			return prev.bearish() && cur.bullish() && cur.Open <= prev.Close && cur.Close >= prev.Open && cur.body() > prev.body()
+		},
+	},
+	{
+		name: "bearish_engulfing", bias: Bearish, candles: 2, reversal: true, reliability: 0.7,
+		match: func(c []Candle, _ context) bool {
+			prev, cur := c[0], c[1]
+			return prev.bullish() && cur.bearish() && cur.Open >= prev.Close && cur.Close <= prev.Open && cur.body() > prev.body()
+		},
+	},
+	{
+		name: "bullish_harami", bias: Bullish, candles: 2, reversal: true, reliability: 0.5,
+		match: func(c []Candle, ctx context) bool {
+			prev, cur := c[0], c[1]
+			return prev.bearish() && isLong(prev, ctx) && cur.bullish() &&
+				cur.bodyTop() <= prev.Open && cur.bodyBottom() >= prev.Close && cur.body() <= prev.body()/2
+		},
+	},
+	{
+		name: "bearish_harami", bias: Bearish, candles: 2, reversal: true, reliability: 0.5,
+		match: func(c []Candle, ctx context) bool {
+			prev, cur := c[0], c[1]
+			return prev.bullish() && isLong(prev, ctx) && cur.bearish() &&
+				cur.bodyTop() <= prev.Close && cur.bodyBottom() >= prev.Open && cur.body() <= prev.body()/2
+		},
+	},
+	{
+		name: "piercing_line", bias: Bullish, candles: 2, reversal: true, reliability: 0.65,
+		match: func(c []Candle, ctx context) bool {
+			prev, cur := c[0], c[1]
+			return prev.bearish() && isLong(prev, ctx) && cur.bullish() &&
+				cur.Open <= prev.Close && cur.Close > prev.midBody() && cur.Close < prev.Open
+		},
+	},
+	{
+		name: "dark_cloud_cover", bias: Bearish, candles: 2, reversal: true, reliability: 0.65,
+		match: func(c []Candle, ctx context) bool {
+			prev, cur := c[0], c[1]
+			return prev.bullish() && isLong(prev, ctx) && cur.bearish() &&
+				cur.Open >= prev.Close && cur.Close < prev.midBody() && cur.Close > prev.Open
+		},
+	},
+	{
+		name: "tweezer_bottom", bias: Bullish, candles: 2, reversal: true, reliability: 0.5,
+		match: func(c []Candle, ctx context) bool {
+			prev, cur := c[0], c[1]
+			return ctx.trend == Downtrend && prev.bearish() && cur.bullish() &&
+				math.Abs(prev.Low-cur.Low) <= tweezerMargin*ctx.avgRange
+		},
+	},
+	{
+		name: "tweezer_top", bias: Bearish, candles: 2, reversal: true, reliability: 0.5,
+		match: func(c []Candle, ctx context) bool {
+			prev, cur := c[0], c[1]
+			return ctx.trend == Uptrend && prev.bullish() && cur.bearish() &&
+				math.Abs(prev.High-cur.High) <= tweezerMargin*ctx.avgRange
+		},
+	},
+	{
+		name: "morning_star", bias: Bullish, candles: 3, reversal: true, reliability: 0.8,
+		match: func(c []Candle, ctx context) bool {
+			first, star, last := c[0], c[1], c[2]
+			return first.bearish() && isLong(first, ctx) && star.body() <= first.body()*spinningBody &&
+				star.midBody() < first.Close && last.bullish() && last.Close > first.midBody()
+		},
+	},
+	{
+		name: "evening_star", bias: Bearish, candles: 3, reversal: true, reliability: 0.8,
+		match: func(c []Candle, ctx context) bool {
+			first, star, last := c[0], c[1], c[2]
+			return first.bullish() && isLong(first, ctx) && star.body() <= first.body()*spinningBody &&
+				star.midBody() > first.Close && last.bearish() && last.Close < first.midBody()
+		},
+	},
+	{
+		name: "three_white_soldiers", bias: Bullish, candles: 3, reliability: 0.8,
+		match: func(c []Candle, ctx context) bool {
+			for i, cur := range c {
+				if !cur.bullish() || cur.body() < ctx.avgBody/2 || cur.upperShadow() > cur.body()/2 {
+					return false
+				}
+				// Each opens within the previous body and closes higher
+				if i > 0 && (cur.Open < c[i-1].Open || cur.Open > c[i-1].Close || cur.Close <= c[i-1].Close) {
+					return false
+				}
+			}
+			return true
+		},
+	},
+	{
+		name: "three_black_crows", bias: Bearish, candles: 3, reliability: 0.8,
+		match: func(c []Candle, ctx context) bool {
+			for i, cur := range c {
+				if !cur.bearish() || cur.body() < ctx.avgBody/2 || cur.lowerShadow() > cur.body()/2 {
+					return false
+				}
+				if i > 0 && (cur.Open > c[i-1].Open || cur.Open < c[i-1].Close || cur.Close >= c[i-1].Close) {
+					return false
+				}
+			}
+			return true
+		},
+	},
+}
+
+// DetectCandles returns the candlestick patterns completed by the last
+// candle, strongest first
+func DetectCandles(candles []Candle) []CandlePattern {
+	var found []CandlePattern
+	for _, rule := range candleRules {
+		start := len(candles) - rule.candles
+		if start < 0 {
+			continue
+		}
+		ctx := contextBefore(candles, start)
+		if !rule.match(candles[start:], ctx) {
+			continue
+		}
+		found = append(found, CandlePattern{
+			Name:     rule.name,
+			Bias:     rule.bias,
+			Candles:  rule.candles,
+			Reversal: rule.reversal,
+			Trend:    ctx.trend,
+			Strength: strength(rule.reliability, rule.bias, rule.reversal, ctx.trend),
+		})
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Strength > found[j].Strength })
+	return found
+}
+
+// CandleNames returns the names of the recognized candlestick patterns
+func CandleNames() []string {
+	names := make([]string, len(candleRules))
+	for i, rule := range candleRules {
+		names[i] = rule.name
+	}
+	return names
+}
@@ -0,0 +1,79 @@
+package patterns
+
+import "testing"
+
+// trendCandles returns n candles moving by step per bar, each with a body of
+// half its range
+func trendCandles(n int, start, step float64) []Candle {
+	candles := make([]Candle, n)
+	for i := range candles {
+		open := start + float64(i)*step
+		close := open + step
+		candles[i] = Candle{
+			Open:  open,
+			Close: close,
+			High:  max(open, close) + 0.5,
+			Low:   min(open, close) - 0.5,
+		}
+	}
+	return candles
+}
+
+// find returns the detected pattern of a name
+func find(found []CandlePattern, name string) (CandlePattern, bool) {
+	for _, p := range found {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return CandlePattern{}, false
+}
+
+func TestDetectCandles(t *testing.T) {
+	down := trendCandles(10, 120, -1) // closes at 110
+	up := trendCandles(10, 100, 1)    // closes at 110
+
+	tests := []struct {
+		name    string
+		candles []Candle
+		want    string
+		trend   Trend
+	}{
+		{"hammer after a fall", append(down, Candle{Open: 109, High: 110.2, Low: 106, Close: 110}), "hammer", Downtrend},
+		{"same shape after a rise", append(up, Candle{Open: 109, High: 110.2, Low: 106, Close: 110}), "hanging_man", Uptrend},
+		{"shooting star", append(up, Candle{Open: 111, High: 114, Low: 109.8, Close: 110}), "shooting_star", Uptrend},
+		{"bullish engulfing", append(down, Candle{Open: 110, High: 110.2, Low: 108.8, Close: 109}, Candle{Open: 108.8, High: 110.6, Low: 108.6, Close: 110.5}), "bullish_engulfing", Downtrend},
+		{"bearish engulfing", append(up, Candle{Open: 110, High: 111.2, Low: 109.8, Close: 111}, Candle{Open: 111.2, High: 111.4, Low: 109.4, Close: 109.5}), "bearish_engulfing", Uptrend},
+		{"morning star", append(down, Candle{Open: 110, High: 110.2, Low: 107.8, Close: 108}, Candle{Open: 107.6, High: 107.9, Low: 107.2, Close: 107.5}, Candle{Open: 107.8, High: 109.8, Low: 107.7, Close: 109.5}), "morning_star", Downtrend},
+		{"three white soldiers", append(down, Candle{Open: 110, High: 111.1, Low: 109.9, Close: 111}, Candle{Open: 110.5, High: 112.1, Low: 110.4, Close: 112}, Candle{Open: 111.5, High: 113.1, Low: 111.4, Close: 113}), "three_white_soldiers", Downtrend},
+		{"doji", append(up, Candle{Open: 110, High: 111, Low: 109, Close: 110.05}), "doji", Uptrend},
+	}
+
+	for _, tt := range tests {
+		found := DetectCandles(tt.candles)
+		p, ok := find(found, tt.want)
+		if !ok {
+			t.Errorf("%s: %s not found in %+v", tt.name, tt.want, found)
+			continue
+		}
+		if p.Trend != tt.trend || p.Strength <= 0 || p.Strength > 1 {
+			t.Errorf("%s: got %+v, expected trend %s and strength within (0, 1]", tt.name, p, tt.trend)
+		}
+	}
+
+	// Plain trend candles form no reversal
+	for _, p := range DetectCandles(up) {
+		if p.Reversal {
+			t.Errorf("reversal %+v in a steady rise", p)
+		}
+	}
+}
+
+func TestStrengthFollowsContext(t *testing.T) {
+	against := strength(0.7, Bullish, true, Downtrend)
+	flat := strength(0.7, Bullish, true, Sideways)
+	with := strength(0.7, Bullish, true, Uptrend)
+	if !(against > flat && flat > with) {
+		t.Errorf("reversal strengths %v, %v, %v, expected to fall as the trend agrees", against, flat, with)
+	}
+}
@@ -0,0 +1,487 @@
+package patterns
+
+import (
+	"math"
+	"time"
+)
+
+// pivotBars is how many bars on each side a swing high or low must exceed
+const pivotBars = 3
+
+const (
+	// levelTolerance is how far apart, as a fraction of price, two tops or
+	// bottoms may be to count as one level
+	levelTolerance = 0.03
+	// flatTolerance is the largest move, as a fraction of price, of a flat
+	// triangle side
+	flatTolerance = 0.015
+	// minDepth is the smallest move, as a fraction of price, between the
+	// swings of a formation
+	minDepth = 0.03
+
+	// A flag is a pole of at least minPoleMove over at most poleBars bars,
+	// then minFlagBars to maxFlagBars bars retracing at most maxFlagRetrace
+	// of it
+	poleBars       = 10
+	minPoleMove    = 0.08
+	minFlagBars    = 3
+	maxFlagBars    = 15
+	maxFlagRetrace = 0.5
+)
+
+// Chart pattern statuses
+const (
+	// Forming patterns have not closed beyond their breakout level
+	Forming = "forming"
+	// Completed patterns have closed beyond it since their last pivot
+	Completed = "completed"
+)
+
+// Chart pattern types
+const (
+	DoubleTop               = "double_top"
+	DoubleBottom            = "double_bottom"
+	HeadAndShoulders        = "head_and_shoulders"
+	InverseHeadAndShoulders = "inverse_head_and_shoulders"
+	AscendingTriangle       = "ascending_triangle"
+	DescendingTriangle      = "descending_triangle"
+	SymmetricalTriangle     = "symmetrical_triangle"
+	BullFlag                = "bull_flag"
+	BearFlag                = "bear_flag"
+)
+
+// Point is a price at a bar
+type Point struct {
+	Index int       `json:"index"`
+	Time  time.Time `json:"time"`
+	Price float64   `json:"price"`
+}
+
+// Pivot is a swing high or low
+type Pivot struct {
+	Point
+	High bool `json:"high"`
+}
+
+// Line is a straight line through two points, extended both ways
+type Line struct {
+	From Point `json:"from"`
+	To   Point `json:"to"`
+}
+
+// At returns the price of the line at a bar
+func (l Line) At(index int) float64 {
+	if l.To.Index == l.From.Index {
+		return l.To.Price
+	}
+	slope := (l.To.Price - l.From.Price) / float64(l.To.Index-l.From.Index)
+	return l.From.Price + slope*float64(index-l.From.Index)
+}
+
+// ChartPattern is a chart formation among the recent swings
+type ChartPattern struct {
+	Type string `json:"type"`
+	// Bias is the direction of the expected breakout; a symmetrical
+	// triangle is neutral until it breaks out
+	Bias Bias `json:"bias"`
+	// Pivots are the swings forming the pattern, oldest first
+	Pivots []Pivot `json:"pivots"`
+	// Neckline is the line whose break completes the pattern: the neckline
+	// of tops, bottoms and head-and-shoulders, the breakout side of a
+	// triangle or flag. It ends at the latest bar.
+	Neckline Line `json:"neckline"`
+	// Breakout is the level of the neckline at the latest bar
+	Breakout float64 `json:"breakout_level"`
+	Status   string  `json:"status"`
+	// Height is the measured move: the depth of the pattern, or the pole of
+	// a flag
+	Height float64 `json:"height"`
+	// Target is the breakout level plus the measured move in the direction
+	// of the bias, never below zero
+	Target float64 `json:"target"`
+}
+
+// FindPivots returns the swing highs and lows of candles, alternating: a
+// swing high has no higher high within bars bars on either side, and of
+// consecutive swings of one kind only the most extreme is kept
+func FindPivots(candles []Candle, bars int) []Pivot {
+	var pivots []Pivot
+	add := func(p Pivot) {
+		if n := len(pivots); n > 0 && pivots[n-1].High == p.High {
+			if last := pivots[n-1]; (p.High && p.Price >= last.Price) || (!p.High && p.Price <= last.Price) {
+				pivots[n-1] = p
+			}
+			return
+		}
+		pivots = append(pivots, p)
+	}
+
+	for i := bars; i < len(candles)-bars; i++ {
+		isHigh, isLow := true, true
+		for j := i - bars; j <= i+bars; j++ {
+			// Of equal swings the later one counts
+			switch {
+			case j < i:
+				isHigh = isHigh && candles[j].High <= candles[i].High
+				isLow = isLow && candles[j].Low >= candles[i].Low
+			case j > i:
+				isHigh = isHigh && candles[j].High < candles[i].High
+				isLow = isLow && candles[j].Low > candles[i].Low
+			}
+		}
+		if isHigh {
+			add(Pivot{Point: point(candles, i, candles[i].High), High: true})
+		}
+		if isLow {
+			add(Pivot{Point: point(candles, i, candles[i].Low)})
+		}
+	}
+	return pivots
+}
+
+// DetectCharts returns the chart patterns among the recent swings of candles,
+// at most one of each type
+func DetectCharts(candles []Candle) []ChartPattern {
+	pivots := FindPivots(candles, pivotBars)
+	var found []ChartPattern
+	for _, detect := range []func([]Candle, []Pivot) *ChartPattern{
+		doubleTop, doubleBottom, headAndShoulders, inverseHeadAndShoulders, triangle, bullFlag, bearFlag,
+	} {
+		if p := detect(candles, pivots); p != nil {
+			found = append(found, *p)
+		}
+	}
+
+	// A formation ending at a later swing supersedes an opposite one
+	var current []ChartPattern
+	for _, p := range found {
+		superseded := false
+		for _, q := range found {
+			superseded = superseded || (opposite(p.Bias, q.Bias) && lastPivot(q) > lastPivot(p))
+		}
+		if !superseded {
+			current = append(current, p)
+		}
+	}
+	return current
+}
+
+// opposite reports whether two biases point opposite ways
+func opposite(a, b Bias) bool {
+	return (a == Bullish && b == Bearish) || (a == Bearish && b == Bullish)
+}
+
+// lastPivot returns the bar of the last swing of a pattern
+func lastPivot(p ChartPattern) int {
+	return p.Pivots[len(p.Pivots)-1].Index
+}
+
+// ChartTypes returns the recognized chart pattern types
+func ChartTypes() []string {
+	return []string{
+		DoubleTop, DoubleBottom, HeadAndShoulders, InverseHeadAndShoulders,
+		AscendingTriangle, DescendingTriangle, SymmetricalTriangle, BullFlag, BearFlag,
+	}
+}
+
+// recent returns the start indexes of the runs of size pivots ending at one
+// of the last two pivots, latest first, so a pattern is still current
+func recent(pivots []Pivot, size int) []int {
+	var starts []int
+	for end := len(pivots) - 1; end >= len(pivots)-2 && end >= size-1; end-- {
+		starts = append(starts, end-size+1)
+	}
+	return starts
+}
+
+func doubleTop(candles []Candle, pivots []Pivot) *ChartPattern {
+	return doubleSwing(candles, pivots, true)
+}
+
+func doubleBottom(candles []Candle, pivots []Pivot) *ChartPattern {
+	return doubleSwing(candles, pivots, false)
+}
+
+// doubleSwing finds two tops (or bottoms) at one level with a retracement
+// between them, whose level marks the neckline
+func doubleSwing(candles []Candle, pivots []Pivot, top bool) *ChartPattern {
+	for _, start := range recent(pivots, 3) {
+		a, mid, b := pivots[start], pivots[start+1], pivots[start+2]
+		if a.High != top || b.High != top {
+			continue
+		}
+
+		extreme := math.Max(a.Price, b.Price)
+		inner := math.Min(a.Price, b.Price)
+		if !top {
+			extreme, inner = math.Min(a.Price, b.Price), math.Max(a.Price, b.Price)
+		}
+		if math.Abs(a.Price-b.Price)/extreme > levelTolerance || math.Abs(inner-mid.Price)/mid.Price < minDepth {
+			continue
+		}
+		// A swing beyond the level since the second top fails the pattern
+		if beyond(candles, b.Index, extreme*(1+direction(top)*levelTolerance), top) {
+			continue
+		}
+
+		p := &ChartPattern{
+			Type:     DoubleBottom,
+			Bias:     Bullish,
+			Pivots:   []Pivot{a, mid, b},
+			Neckline: Line{From: mid.Point, To: point(candles, len(candles)-1, mid.Price)},
+			Height:   math.Abs(extreme - mid.Price),
+		}
+		if top {
+			p.Type, p.Bias = DoubleTop, Bearish
+		}
+		return finish(candles, p, b.Index)
+	}
+	return nil
+}
+
+func headAndShoulders(candles []Candle, pivots []Pivot) *ChartPattern {
+	return shoulders(candles, pivots, true)
+}
+
+func inverseHeadAndShoulders(candles []Candle, pivots []Pivot) *ChartPattern {
+	return shoulders(candles, pivots, false)
+}
+
+// shoulders finds a head beyond two shoulders of similar level, with the
+// neckline through the swings between them
+func shoulders(candles []Candle, pivots []Pivot, top bool) *ChartPattern {
+	sign := direction(top)
+	for _, start := range recent(pivots, 5) {
+		left, neck1, head, neck2, right := pivots[start], pivots[start+1], pivots[start+2], pivots[start+3], pivots[start+4]
+		if left.High != top || head.High != top || right.High != top {
+			continue
+		}
+
+		// The head stands out from both shoulders, which are at one level
+		shoulder := math.Max(sign*left.Price, sign*right.Price) * sign
+		if sign*(head.Price-shoulder)/head.Price < minDepth ||
+			math.Abs(left.Price-right.Price)/math.Max(left.Price, right.Price) > 2*levelTolerance {
+			continue
+		}
+		if beyond(candles, right.Index, head.Price, top) {
+			continue
+		}
+
+		neckline := Line{From: neck1.Point, To: neck2.Point}
+		neckline.To = point(candles, len(candles)-1, neckline.At(len(candles)-1))
+		p := &ChartPattern{
+			Type:     InverseHeadAndShoulders,
+			Bias:     Bullish,
+			Pivots:   []Pivot{left, neck1, head, neck2, right},
+			Neckline: neckline,
+			Height:   math.Abs(head.Price - neckline.At(head.Index)),
+		}
+		if top {
+			p.Type, p.Bias = HeadAndShoulders, Bearish
+		}
+		return finish(candles, p, right.Index)
+	}
+	return nil
+}
+
+// triangle finds converging sides through the last two swing highs and lows:
+// a flat top over rising lows, a flat bottom under falling highs, or both
+// sides closing in
+func triangle(candles []Candle, pivots []Pivot) *ChartPattern {
+	if len(pivots) < 4 {
+		return nil
+	}
+	swings := pivots[len(pivots)-4:]
+	var highs, lows []Pivot
+	for _, p := range swings {
+		if p.High {
+			highs = append(highs, p)
+		} else {
+			lows = append(lows, p)
+		}
+	}
+
+	upper := Line{From: highs[0].Point, To: highs[1].Point}
+	lower := Line{From: lows[0].Point, To: lows[1].Point}
+	first, last, end := swings[0].Index, swings[3].Index, len(candles)-1
+	height := upper.At(first) - lower.At(first)
+	// The sides must close in and not have met yet
+	if height <= 0 || upper.At(last)-lower.At(last) >= height || upper.At(end) <= lower.At(end) {
+		return nil
+	}
+
+	highMove := (highs[1].Price - highs[0].Price) / highs[0].Price
+	lowMove := (lows[1].Price - lows[0].Price) / lows[0].Price
+	upper.To = point(candles, end, upper.At(end))
+	lower.To = point(candles, end, lower.At(end))
+
+	var p *ChartPattern
+	switch {
+	case math.Abs(highMove) <= flatTolerance && lowMove > flatTolerance:
+		p = &ChartPattern{Type: AscendingTriangle, Bias: Bullish, Neckline: upper}
+	case math.Abs(lowMove) <= flatTolerance && highMove < -flatTolerance:
+		p = &ChartPattern{Type: DescendingTriangle, Bias: Bearish, Neckline: lower}
+	case highMove < -flatTolerance && lowMove > flatTolerance:
+		// Neutral until it breaks out either way
+		p = &ChartPattern{Type: SymmetricalTriangle, Bias: Neutral, Neckline: upper}
+		if crossed(candles, last, lower, false) {
+			p.Bias, p.Neckline = Bearish, lower
+		} else if crossed(candles, last, upper, true) {
+			p.Bias = Bullish
+		}
+	default:
+		return nil
+	}
+
+	// A break of the other side fails a directional triangle
+	if (p.Bias == Bullish && p.Type != SymmetricalTriangle && crossed(candles, last, lower, false)) ||
+		(p.Bias == Bearish && p.Type != SymmetricalTriangle && crossed(candles, last, upper, true)) {
+		return nil
+	}
+	p.Pivots = append([]Pivot(nil), swings...)
+	p.Height = height
+	return finish(candles, p, last)
+}
+
+func bullFlag(candles []Candle, pivots []Pivot) *ChartPattern {
+	return flag(candles, pivots, true)
+}
+
+func bearFlag(candles []Candle, pivots []Pivot) *ChartPattern {
+	return flag(candles, pivots, false)
+}
+
+// flag finds a sharp pole ending at a recent swing, followed by a short,
+// shallow pullback whose breakout resumes the move
+func flag(candles []Candle, pivots []Pivot, bull bool) *ChartPattern {
+	sign := direction(bull)
+	end := len(candles) - 1
+
+	// The pole ends at the latest swing in its direction
+	k := len(pivots) - 1
+	for k >= 0 && pivots[k].High != bull {
+		k--
+	}
+	if k < 0 || k < len(pivots)-2 {
+		return nil
+	}
+	tip := pivots[k]
+	if bars := end - tip.Index; bars < minFlagBars || bars > maxFlagBars || tip.Index < 1 {
+		return nil
+	}
+
+	// The pole starts at the opposite extreme of the bars before the tip
+	var base Point
+	for i := tip.Index - 1; i >= max(tip.Index-poleBars, 0); i-- {
+		price := candles[i].Low
+		if !bull {
+			price = candles[i].High
+		}
+		if i == tip.Index-1 || sign*(price-base.Price) < 0 {
+			base = point(candles, i, price)
+		}
+	}
+	pole := sign * (tip.Price - base.Price)
+	if pole <= 0 || pole/base.Price < minPoleMove {
+		return nil
+	}
+
+	// The flag pulls back at most maxFlagRetrace of the pole
+	retrace := 0.0
+	for i := tip.Index + 1; i <= end; i++ {
+		extreme := candles[i].Low
+		if !bull {
+			extreme = candles[i].High
+		}
+		retrace = math.Max(retrace, sign*(tip.Price-extreme))
+	}
+	if retrace > maxFlagRetrace*pole {
+		return nil
+	}
+
+	// The breakout side runs from the tip through the latest swing of the
+	// flag in the same direction, or level with the tip
+	neckline := Line{From: tip.Point, To: point(candles, end, tip.Price)}
+	swings := []Pivot{{Point: base, High: !bull}, tip}
+	for _, p := range pivots[k+1:] {
+		swings = append(swings, p)
+		if p.High == bull && sign*(p.Price-tip.Price) < 0 {
+			neckline = Line{From: tip.Point, To: p.Point}
+		}
+	}
+	neckline.To = point(candles, end, neckline.At(end))
+
+	p := &ChartPattern{
+		Type:     BearFlag,
+		Bias:     Bearish,
+		Pivots:   swings,
+		Neckline: neckline,
+		Height:   pole,
+	}
+	if bull {
+		p.Type, p.Bias = BullFlag, Bullish
+	}
+	return finish(candles, p, tip.Index)
+}
+
+// finish sets the breakout level, status and target of a pattern whose
+// breakout is looked for after the bar from
+func finish(candles []Candle, p *ChartPattern, from int) *ChartPattern {
+	end := len(candles) - 1
+	p.Breakout = p.Neckline.At(end)
+	p.Status = Forming
+
+	switch p.Bias {
+	case Bullish:
+		if crossed(candles, from, p.Neckline, true) {
+			p.Status = Completed
+		}
+		p.Target = p.Breakout + p.Height
+	case Bearish:
+		if crossed(candles, from, p.Neckline, false) {
+			p.Status = Completed
+		}
+		// A pattern taller than its breakout level cannot measure a move
+		// below zero
+		p.Target = math.Max(p.Breakout-p.Height, 0)
+	default:
+		// An unbroken symmetrical triangle shows the upside target
+		p.Target = p.Breakout + p.Height
+	}
+	return p
+}
+
+// crossed reports whether a close after the bar from is above the line, or
+// below it if up is false
+func crossed(candles []Candle, from int, line Line, up bool) bool {
+	for i := from + 1; i < len(candles); i++ {
+		if (up && candles[i].Close > line.At(i)) || (!up && candles[i].Close < line.At(i)) {
+			return true
+		}
+	}
+	return false
+}
+
+// beyond reports whether a high after the bar from is above level, or a low
+// below it if high is false
+func beyond(candles []Candle, from int, level float64, high bool) bool {
+	for i := from + 1; i < len(candles); i++ {
+		if (high && candles[i].High > level) || (!high && candles[i].Low < level) {
+			return true
+		}
+	}
+	return false
+}
+
+// point returns a price at a bar
+func point(candles []Candle, i int, price float64) Point {
+	return Point{Index: i, Time: candles[i].Time, Price: price}
+}
+
+// direction is 1 for tops and upward moves and -1 for bottoms
+func direction(up bool) float64 {
+	if up {
+		return 1
+	}
+	return -1
+}
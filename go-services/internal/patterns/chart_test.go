@@ -0,0 +1,92 @@
+package patterns
+
+import "testing"
+
+// path returns candles whose closes run straight between the given prices,
+// steps bars per leg, each bar with a range of 1 around its close
+func path(steps int, prices ...float64) []Candle {
+	var candles []Candle
+	for i := 0; i+1 < len(prices); i++ {
+		for s := 0; s < steps; s++ {
+			close := prices[i] + (prices[i+1]-prices[i])*float64(s)/float64(steps)
+			candles = append(candles, Candle{Open: close, High: close + 0.5, Low: close - 0.5, Close: close})
+		}
+	}
+	last := prices[len(prices)-1]
+	return append(candles, Candle{Open: last, High: last + 0.5, Low: last - 0.5, Close: last})
+}
+
+// findChart returns the detected chart pattern of a type
+func findChart(found []ChartPattern, typ string) (ChartPattern, bool) {
+	for _, p := range found {
+		if p.Type == typ {
+			return p, true
+		}
+	}
+	return ChartPattern{}, false
+}
+
+func TestFindPivotsAlternate(t *testing.T) {
+	pivots := FindPivots(path(5, 100, 120, 105, 125, 110, 118), pivotBars)
+	if len(pivots) != 4 {
+		t.Fatalf("got %d pivots %+v, expected 4", len(pivots), pivots)
+	}
+	for i, p := range pivots {
+		if p.High != (i%2 == 0) {
+			t.Errorf("pivot %d %+v out of order", i, p)
+		}
+	}
+	if pivots[0].Index != 5 || pivots[0].Price != 120.5 {
+		t.Errorf("first pivot %+v, expected the high of bar 5", pivots[0])
+	}
+}
+
+func TestDetectCharts(t *testing.T) {
+	tests := []struct {
+		name    string
+		candles []Candle
+		want    string
+		bias    Bias
+		status  string
+		target  float64
+	}{
+		// Tops at 120.5, neckline at the 109.5 low, broken by the last close
+		{"double top", path(6, 100, 120, 110, 120, 105), DoubleTop, Bearish, Completed, 98.5},
+		{"double top forming", path(6, 100, 120, 110, 120, 113), DoubleTop, Bearish, Forming, 98.5},
+		// Taller than the neckline is high: the target stops at zero
+		{"tall double top", path(6, 10, 40, 12, 40, 11), DoubleTop, Bearish, Completed, 0},
+		{"double bottom", path(6, 120, 100, 110, 100, 115), DoubleBottom, Bullish, Completed, 121.5},
+		// Head 130.5 over a flat neckline at 109.5
+		{"head and shoulders", path(6, 100, 120, 110, 130, 110, 120, 104), HeadAndShoulders, Bearish, Completed, 88.5},
+		{"inverse head and shoulders", path(6, 130, 110, 120, 100, 120, 110, 125), InverseHeadAndShoulders, Bullish, Completed, 141.5},
+		// Flat highs at 120.5 over lows rising from 99.5, 26 apart at the
+		// first high
+		{"ascending triangle", path(6, 95, 120, 100, 120, 110, 117), AscendingTriangle, Bullish, Forming, 146.5},
+		{"descending triangle", path(6, 125, 100, 120, 100, 110, 103), DescendingTriangle, Bearish, Forming, 73.5},
+		// A 21 point pole from 99.5 to 120.5, then a pullback broken upwards
+		{"bull flag", path(4, 100, 100, 120, 117, 119, 122), BullFlag, Bullish, Completed, 141.5},
+		{"bear flag", path(4, 120, 120, 100, 103, 101, 98), BearFlag, Bearish, Completed, 78.5},
+	}
+
+	for _, tt := range tests {
+		p, ok := findChart(DetectCharts(tt.candles), tt.want)
+		if !ok {
+			t.Errorf("%s: %s not found in %+v", tt.name, tt.want, DetectCharts(tt.candles))
+			continue
+		}
+		if p.Bias != tt.bias || p.Status != tt.status {
+			t.Errorf("%s: got bias %s status %s, expected %s %s", tt.name, p.Bias, p.Status, tt.bias, tt.status)
+		}
+		if diff := p.Target - tt.target; diff > 0.01 || diff < -0.01 {
+			t.Errorf("%s: target %.2f (breakout %.2f, height %.2f), expected %.2f", tt.name, p.Target, p.Breakout, p.Height, tt.target)
+		}
+		if p.Neckline.To.Index != len(tt.candles)-1 {
+			t.Errorf("%s: neckline %+v does not reach the last bar", tt.name, p.Neckline)
+		}
+	}
+
+	// Tops too far apart are not a double top
+	if p, ok := findChart(DetectCharts(path(6, 100, 120, 110, 130, 105)), DoubleTop); ok {
+		t.Errorf("double top %+v from unequal tops", p)
+	}
+}
@@ -0,0 +1,110 @@
+// Package patterns recognizes price patterns in OHLC bars: candlestick
+// patterns of one to three candles, each judged against the trend before it,
+// and chart formations drawn between swing highs and lows.
+package patterns
+
+import (
+	"math"
+	"time"
+)
+
+// Candle is the OHLC of one bar
+type Candle struct {
+	Time  time.Time `json:"time"`
+	Open  float64   `json:"open"`
+	High  float64   `json:"high"`
+	Low   float64   `json:"low"`
+	Close float64   `json:"close"`
+}
+
+// Bias is the direction a pattern points to
+type Bias string
+
+const (
+	Bullish Bias = "bullish"
+	Bearish Bias = "bearish"
+	Neutral Bias = "neutral"
+)
+
+// Trend is the direction of the price before a pattern
+type Trend string
+
+const (
+	Uptrend   Trend = "uptrend"
+	Downtrend Trend = "downtrend"
+	Sideways  Trend = "sideways"
+)
+
+// trendBars is the number of bars before a pattern that set its context
+const trendBars = 10
+
+// trendRanges is how many average bar ranges the close must move over
+// trendBars bars to count as a trend
+const trendRanges = 2.0
+
+func (c Candle) body() float64        { return math.Abs(c.Close - c.Open) }
+func (c Candle) span() float64        { return c.High - c.Low }
+func (c Candle) upperShadow() float64 { return c.High - math.Max(c.Open, c.Close) }
+func (c Candle) lowerShadow() float64 { return math.Min(c.Open, c.Close) - c.Low }
+func (c Candle) bodyTop() float64     { return math.Max(c.Open, c.Close) }
+func (c Candle) bodyBottom() float64  { return math.Min(c.Open, c.Close) }
+func (c Candle) midBody() float64     { return (c.Open + c.Close) / 2 }
+func (c Candle) bullish() bool        { return c.Close > c.Open }
+func (c Candle) bearish() bool        { return c.Close < c.Open }
+
+// context describes the bars before a pattern
+type context struct {
+	trend Trend
+	// avgBody and avgRange are the average body and range of those bars,
+	// the yardstick for long and short candles
+	avgBody, avgRange float64
+}
+
+// contextBefore returns the context of a pattern starting at start
+func contextBefore(candles []Candle, start int) context {
+	first := max(start-trendBars, 0)
+	bars := candles[first:start]
+	if len(bars) == 0 {
+		return context{trend: Sideways}
+	}
+
+	var ctx context
+	for _, c := range bars {
+		ctx.avgBody += c.body()
+		ctx.avgRange += c.span()
+	}
+	ctx.avgBody /= float64(len(bars))
+	ctx.avgRange /= float64(len(bars))
+
+	ctx.trend = Sideways
+	if len(bars) < 3 || ctx.avgRange == 0 {
+		return ctx
+	}
+	move := (bars[len(bars)-1].Close - bars[0].Close) / ctx.avgRange
+	if move >= trendRanges {
+		ctx.trend = Uptrend
+	} else if move <= -trendRanges {
+		ctx.trend = Downtrend
+	}
+	return ctx
+}
+
+// strength scales the reliability of a pattern by its context: a reversal
+// pattern counts fully against the trend it reverses, less without a trend
+// and least in the direction of the trend; a continuation pattern counts
+// fully unless it runs against the trend
+func strength(reliability float64, bias Bias, reversal bool, trend Trend) float64 {
+	factor := 1.0
+	with := (bias == Bullish && trend == Uptrend) || (bias == Bearish && trend == Downtrend)
+	against := (bias == Bullish && trend == Downtrend) || (bias == Bearish && trend == Uptrend)
+	switch {
+	case bias == Neutral:
+	case reversal && trend == Sideways:
+		factor = 0.7
+	case reversal && with:
+		factor = 0.5
+	case !reversal && against:
+		factor = 0.8
+	}
+	return math.Round(reliability*factor*100) / 100
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"vnstock-hybrid/internal/models"
+	"vnstock-hybrid/pkg/calendar"
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+// BarSyncService keeps the local ohlcv_bars store in sync with a market data provider
+type BarSyncService struct {
+	db         *gorm.DB
+	marketData vnstock.MarketDataProvider
+}
+
+// NewBarSyncService creates a new bar sync service
+func NewBarSyncService(db *gorm.DB, provider vnstock.MarketDataProvider) *BarSyncService {
+	return &BarSyncService{
+		db:         db,
+		marketData: provider,
+	}
+}
+
+// Sync fetches the bars missing from the last lookbackDays trading days of the
+// local store and returns how many were stored. A symbol without stored bars is
+// backfilled; otherwise only the trading days after the earliest gap (or after
+// the last stored bar) are requested from the provider.
+func (s *BarSyncService) Sync(ctx context.Context, symbol string, resolution vnstock.Resolution, lookbackDays int) (int, error) {
+	dates, err := s.storedDates(ctx, symbol, resolution, lookbackDays)
+	if err != nil {
+		return 0, err
+	}
+
+	days := lookbackDays
+	if len(dates) > 0 {
+		from := dates[len(dates)-1]
+		if gap := firstGap(dates); !gap.IsZero() {
+			from = gap
+		}
+		days = calendar.TradingDaysBetween(from, time.Now()) + 1
+		if days > lookbackDays {
+			days = lookbackDays
+		}
+	}
+
+	history, err := s.marketData.GetHistoricalData(ctx, symbol, resolution, days)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch %s history: %w", symbol, err)
+	}
+
+	return s.SaveBars(ctx, symbol, resolution, history)
+}
+
+// SaveBars stores bars, replacing stored bars of the same date, and returns how
+// many were written. A re-fetched bar thereby corrects one stored while its
+// session was still trading.
+func (s *BarSyncService) SaveBars(ctx context.Context, symbol string, resolution vnstock.Resolution, bars []vnstock.OHLCV) (int, error) {
+	if len(bars) == 0 {
+		return 0, nil
+	}
+
+	rows := make([]models.OHLCVBar, len(bars))
+	for i, b := range bars {
+		rows[i] = models.OHLCVBar{
+			Symbol:     symbol,
+			Resolution: string(resolution),
+			Date:       b.Date,
+			Open:       b.Open,
+			High:       b.High,
+			Low:        b.Low,
+			Close:      b.Close,
+			Volume:     b.Volume,
+			Value:      b.Value,
+			Source:     b.Source,
+		}
+	}
+
+	result := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "symbol"}, {Name: "resolution"}, {Name: "date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"open", "high", "low", "close", "volume", "value", "source"}),
+		}).
+		CreateInBatches(rows, 500)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to store %s bars: %w", symbol, result.Error)
+	}
+
+	return int(result.RowsAffected), nil
+}
+
+// LoadBars returns up to limit of the most recent stored bars, oldest first
+func (s *BarSyncService) LoadBars(ctx context.Context, symbol string, resolution vnstock.Resolution, limit int) ([]vnstock.OHLCV, error) {
+	var rows []models.OHLCVBar
+	err := s.db.WithContext(ctx).
+		Where("symbol = ? AND resolution = ?", symbol, string(resolution)).
+		Order("date DESC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s bars: %w", symbol, err)
+	}
+
+	bars := toOHLCV(rows)
+	for i, j := 0, len(bars)-1; i < j; i, j = i+1, j-1 {
+		bars[i], bars[j] = bars[j], bars[i]
+	}
+
+	return bars, nil
+}
+
+// LoadRange returns the stored bars between from and to inclusive, oldest first.
+// A zero from or to leaves that end of the range open.
+func (s *BarSyncService) LoadRange(ctx context.Context, symbol string, resolution vnstock.Resolution, from, to time.Time) ([]vnstock.OHLCV, error) {
+	query := s.db.WithContext(ctx).Where("symbol = ? AND resolution = ?", symbol, string(resolution))
+	if !from.IsZero() {
+		query = query.Where("date >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("date <= ?", to)
+	}
+
+	var rows []models.OHLCVBar
+	if err := query.Order("date ASC").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load %s bars: %w", symbol, err)
+	}
+
+	return toOHLCV(rows), nil
+}
+
+// storedDates returns the dates of the stored bars within the last lookbackDays trading days
+func (s *BarSyncService) storedDates(ctx context.Context, symbol string, resolution vnstock.Resolution, lookbackDays int) ([]time.Time, error) {
+	var dates []time.Time
+	err := s.db.WithContext(ctx).
+		Model(&models.OHLCVBar{}).
+		Where("symbol = ? AND resolution = ? AND date >= ?", symbol, string(resolution), calendar.AddTradingDays(time.Now(), -lookbackDays)).
+		Order("date ASC").
+		Pluck("date", &dates).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to read stored %s dates: %w", symbol, err)
+	}
+	return dates, nil
+}
+
+// firstGap returns the date after which the first missing trading day in dates
+// begins, or the zero time
+func firstGap(dates []time.Time) time.Time {
+	for i := 1; i < len(dates); i++ {
+		if calendar.TradingDaysBetween(dates[i-1], dates[i]) > 1 {
+			return dates[i-1]
+		}
+	}
+	return time.Time{}
+}
+
+// NewMismatchRecorder returns a vnstock.CompositeOptions.OnMismatch hook that
+// stores mismatches in the bar_mismatches table
+func NewMismatchRecorder(db *gorm.DB) func([]vnstock.Mismatch) {
+	return func(mismatches []vnstock.Mismatch) {
+		rows := make([]models.BarMismatch, len(mismatches))
+		for i, m := range mismatches {
+			rows[i] = models.BarMismatch{
+				Symbol:         m.Symbol,
+				Resolution:     string(m.Resolution),
+				Date:           m.Date,
+				Field:          m.Field,
+				Source:         m.Source,
+				Reference:      m.Reference,
+				SourceValue:    m.SourceValue,
+				ReferenceValue: m.ReferenceValue,
+				DiffPercent:    math.Min(m.DiffPercent, 999999),
+			}
+		}
+		if err := db.CreateInBatches(rows, 500).Error; err != nil {
+			log.Printf("Warning: failed to record bar mismatches: %v", err)
+		}
+	}
+}
+
+func toOHLCV(rows []models.OHLCVBar) []vnstock.OHLCV {
+	bars := make([]vnstock.OHLCV, len(rows))
+	for i, r := range rows {
+		bars[i] = vnstock.OHLCV{
+			Date:   r.Date,
+			Open:   r.Open,
+			High:   r.High,
+			Low:    r.Low,
+			Close:  r.Close,
+			Volume: r.Volume,
+			Value:  r.Value,
+			Source: r.Source,
+		}
+	}
+	return bars
+}
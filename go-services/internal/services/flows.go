@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"vnstock-hybrid/internal/models"
+	"vnstock-hybrid/pkg/calendar"
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+// flowDays is the number of trading days of investor flows kept for analysis
+const flowDays = 20
+
+// flowStreak is the number of consecutive sessions of net buying or selling
+// that counts as a sustained flow
+const flowStreak = 5
+
+// FlowSummary condenses the recent investor flows of a stock
+type FlowSummary struct {
+	Date time.Time `json:"date"`
+	// ForeignNetValue5D and ForeignNetValue20D are the foreign net values of
+	// the last 5 and 20 sessions, in VND
+	ForeignNetValue5D  float64 `json:"foreign_net_value_5d"`
+	ForeignNetValue20D float64 `json:"foreign_net_value_20d"`
+	// ForeignStreak counts the latest consecutive sessions of foreign net
+	// buying, or of net selling when negative
+	ForeignStreak int   `json:"foreign_streak"`
+	ForeignRoom   int64 `json:"foreign_room"`
+	// RoomLimited marks a foreign room smaller than flowStreak sessions of
+	// average foreign buying
+	RoomLimited bool `json:"room_limited"`
+
+	ProprietaryNetValue5D float64 `json:"proprietary_net_value_5d"`
+	ProprietaryStreak     int     `json:"proprietary_streak"`
+}
+
+// SyncFlows fetches the investor flows missing from the local store and returns
+// how many sessions were stored. The latest stored session is fetched again
+// because providers revise it after the close.
+func (s *BarSyncService) SyncFlows(ctx context.Context, symbol string, lookbackDays int) (int, error) {
+	fp, ok := s.marketData.(vnstock.FlowProvider)
+	if !ok || vnstock.IsIndex(symbol) {
+		return 0, vnstock.ErrNoFlows
+	}
+
+	var last models.InvestorFlow
+	days := lookbackDays
+	err := s.db.WithContext(ctx).Where("symbol = ?", symbol).Order("date DESC").First(&last).Error
+	switch {
+	case err == nil:
+		days = calendar.TradingDaysBetween(last.Date, time.Now()) + 1
+		if days > lookbackDays {
+			days = lookbackDays
+		}
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return 0, fmt.Errorf("failed to read stored %s flows: %w", symbol, err)
+	}
+
+	flows, err := fp.GetFlows(ctx, symbol, days)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch %s flows: %w", symbol, err)
+	}
+	if len(flows) == 0 {
+		return 0, nil
+	}
+
+	rows := make([]models.InvestorFlow, len(flows))
+	for i, f := range flows {
+		rows[i] = models.InvestorFlow{
+			Symbol:                symbol,
+			Date:                  f.Date,
+			ForeignBuyVolume:      f.ForeignBuyVolume,
+			ForeignSellVolume:     f.ForeignSellVolume,
+			ForeignBuyValue:       f.ForeignBuyValue,
+			ForeignSellValue:      f.ForeignSellValue,
+			ForeignRoom:           f.ForeignRoom,
+			ProprietaryBuyVolume:  f.ProprietaryBuyVolume,
+			ProprietarySellVolume: f.ProprietarySellVolume,
+			ProprietaryBuyValue:   f.ProprietaryBuyValue,
+			ProprietarySellValue:  f.ProprietarySellValue,
+		}
+	}
+
+	result := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "symbol"}, {Name: "date"}},
+			UpdateAll: true,
+		}).
+		CreateInBatches(rows, 500)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to store %s flows: %w", symbol, result.Error)
+	}
+
+	return int(result.RowsAffected), nil
+}
+
+// LoadFlows returns up to limit of the most recent stored flows, oldest first
+func (s *BarSyncService) LoadFlows(ctx context.Context, symbol string, limit int) ([]vnstock.Flow, error) {
+	var rows []models.InvestorFlow
+	err := s.db.WithContext(ctx).
+		Where("symbol = ?", symbol).
+		Order("date DESC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s flows: %w", symbol, err)
+	}
+
+	flows := make([]vnstock.Flow, len(rows))
+	for i, r := range rows {
+		flows[len(rows)-1-i] = vnstock.Flow{
+			Date:                  r.Date,
+			ForeignBuyVolume:      r.ForeignBuyVolume,
+			ForeignSellVolume:     r.ForeignSellVolume,
+			ForeignBuyValue:       r.ForeignBuyValue,
+			ForeignSellValue:      r.ForeignSellValue,
+			ForeignRoom:           r.ForeignRoom,
+			ProprietaryBuyVolume:  r.ProprietaryBuyVolume,
+			ProprietarySellVolume: r.ProprietarySellVolume,
+			ProprietaryBuyValue:   r.ProprietaryBuyValue,
+			ProprietarySellValue:  r.ProprietarySellValue,
+		}
+	}
+	return flows, nil
+}
+
+// loadFlows returns the recent flows of a stock, preferring the local store
+// like loadHistory. Stocks without flows return nil.
+func (s *TechnicalService) loadFlows(ctx context.Context, symbol string) []vnstock.Flow {
+	if s.bars == nil {
+		fp, ok := s.marketData.(vnstock.FlowProvider)
+		if !ok {
+			return nil
+		}
+		flows, err := fp.GetFlows(ctx, symbol, flowDays)
+		if err != nil {
+			if !errors.Is(err, vnstock.ErrNoFlows) {
+				log.Printf("Warning: failed to fetch %s flows: %v", symbol, err)
+			}
+			return nil
+		}
+		return flows
+	}
+
+	if _, err := s.bars.SyncFlows(ctx, symbol, flowDays); err != nil && !errors.Is(err, vnstock.ErrNoFlows) {
+		log.Printf("Warning: flow sync failed for %s: %v", symbol, err)
+	}
+	flows, err := s.bars.LoadFlows(ctx, symbol, flowDays)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return nil
+	}
+	return flows
+}
+
+// summarizeFlows condenses flows, oldest first, into a FlowSummary
+func summarizeFlows(flows []vnstock.Flow) *FlowSummary {
+	if len(flows) == 0 {
+		return nil
+	}
+
+	latest := flows[len(flows)-1]
+	summary := &FlowSummary{
+		Date:        latest.Date,
+		ForeignRoom: latest.ForeignRoom,
+	}
+	var foreignBuy int64
+	for i, f := range flows {
+		foreignBuy += f.ForeignBuyVolume
+		recent := len(flows) - i
+		if recent <= 20 {
+			summary.ForeignNetValue20D += f.ForeignNetValue()
+		}
+		if recent <= 5 {
+			summary.ForeignNetValue5D += f.ForeignNetValue()
+			summary.ProprietaryNetValue5D += f.ProprietaryNetValue()
+		}
+	}
+
+	avgBuy := float64(foreignBuy) / float64(len(flows))
+	summary.RoomLimited = float64(latest.ForeignRoom) < flowStreak*avgBuy
+
+	summary.ForeignStreak = netStreak(flows, vnstock.Flow.ForeignNetValue)
+	summary.ProprietaryStreak = netStreak(flows, vnstock.Flow.ProprietaryNetValue)
+	return summary
+}
+
+// netStreak counts the latest consecutive sessions with a positive net value,
+// or with a negative one as a negative count
+func netStreak(flows []vnstock.Flow, net func(vnstock.Flow) float64) int {
+	streak := 0
+	for i := len(flows) - 1; i >= 0; i-- {
+		v := net(flows[i])
+		switch {
+		case v > 0 && streak >= 0:
+			streak++
+		case v < 0 && streak <= 0:
+			streak--
+		default:
+			return streak
+		}
+	}
+	return streak
+}
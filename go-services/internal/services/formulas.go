@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"vnstock-hybrid/internal/formula"
+	"vnstock-hybrid/internal/indicators"
+	"vnstock-hybrid/internal/models"
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+// ErrFormulaNotFound is returned for names that have no saved formula
+var ErrFormulaNotFound = errors.New("formula not found")
+
+// maxFormulaPoints caps how many recent values an evaluation returns
+const maxFormulaPoints = 500
+
+// formulaName is the form of formula names, which appear in URLs
+var formulaName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// FormulaService stores named formulas and evaluates them per symbol over the
+// history technical analysis uses. Without a database formulas are only kept
+// in memory.
+type FormulaService struct {
+	db        *gorm.DB
+	technical *TechnicalService
+
+	mu     sync.RWMutex
+	memory map[string]models.Formula
+}
+
+// FormulaResult is the value of a formula for a symbol. Numbers are floats
+// and conditions booleans; both are null while the formula warms up.
+type FormulaResult struct {
+	Symbol     string             `json:"symbol"`
+	Name       string             `json:"name,omitempty"`
+	Expression string             `json:"expression"`
+	Type       formula.Type       `json:"type"`
+	Resolution vnstock.Resolution `json:"resolution"`
+	Timestamp  time.Time          `json:"timestamp"`
+	Value      interface{}        `json:"value"`
+	// Points are the most recent values, oldest first, when requested
+	Points []FormulaPoint `json:"points,omitempty"`
+}
+
+// FormulaPoint is the value of a formula at one bar
+type FormulaPoint struct {
+	Time  time.Time   `json:"time"`
+	Value interface{} `json:"value"`
+}
+
+// NewFormulaService creates a formula store evaluating with the history of
+// technical
+func NewFormulaService(db *gorm.DB, technical *TechnicalService) *FormulaService {
+	return &FormulaService{
+		db:        db,
+		technical: technical,
+		memory:    make(map[string]models.Formula),
+	}
+}
+
+// Save validates a formula and stores it under its name, replacing any
+// formula of the same name
+func (s *FormulaService) Save(ctx context.Context, f models.Formula) (*models.Formula, error) {
+	if !formulaName.MatchString(f.Name) {
+		return nil, fmt.Errorf("invalid formula name %q: use lowercase letters, digits and underscores", f.Name)
+	}
+	compiled, err := formula.Compile(f.Expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+	f.ID = 0
+	f.Type = string(compiled.Type())
+
+	if s.db == nil {
+		now := time.Now()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		f.CreatedAt, f.UpdatedAt = now, now
+		if old, ok := s.memory[f.Name]; ok {
+			f.CreatedAt = old.CreatedAt
+		}
+		s.memory[f.Name] = f
+		return &f, nil
+	}
+
+	err = s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"expression", "description", "type", "updated_at"}),
+	}).Create(&f).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to store formula %s: %w", f.Name, err)
+	}
+	return s.Get(ctx, f.Name)
+}
+
+// List returns the saved formulas, sorted by name
+func (s *FormulaService) List(ctx context.Context) ([]models.Formula, error) {
+	if s.db == nil {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		list := make([]models.Formula, 0, len(s.memory))
+		for _, f := range s.memory {
+			list = append(list, f)
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+		return list, nil
+	}
+
+	var list []models.Formula
+	if err := s.db.WithContext(ctx).Order("name").Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to load formulas: %w", err)
+	}
+	return list, nil
+}
+
+// Get returns a saved formula
+func (s *FormulaService) Get(ctx context.Context, name string) (*models.Formula, error) {
+	if s.db == nil {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		f, ok := s.memory[name]
+		if !ok {
+			return nil, ErrFormulaNotFound
+		}
+		return &f, nil
+	}
+
+	var f models.Formula
+	err := s.db.WithContext(ctx).Where("name = ?", name).First(&f).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrFormulaNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load formula %s: %w", name, err)
+	}
+	return &f, nil
+}
+
+// Delete removes a saved formula
+func (s *FormulaService) Delete(ctx context.Context, name string) error {
+	if s.db == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.memory[name]; !ok {
+			return ErrFormulaNotFound
+		}
+		delete(s.memory, name)
+		return nil
+	}
+
+	res := s.db.WithContext(ctx).Where("name = ?", name).Delete(&models.Formula{})
+	if res.Error != nil {
+		return fmt.Errorf("failed to delete formula %s: %w", name, res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrFormulaNotFound
+	}
+	return nil
+}
+
+// EvaluateSaved evaluates a saved formula for a symbol
+func (s *FormulaService) EvaluateSaved(ctx context.Context, name, symbol string, opts AnalyzeOptions, points int) (*FormulaResult, error) {
+	f, err := s.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.Evaluate(ctx, f.Expression, symbol, opts, points)
+	if err != nil {
+		return nil, err
+	}
+	result.Name = f.Name
+	return result, nil
+}
+
+// Evaluate evaluates an expression for a symbol, returning its latest value
+// and, if points is positive, that many recent values
+func (s *FormulaService) Evaluate(ctx context.Context, expression, symbol string, opts AnalyzeOptions, points int) (*FormulaResult, error) {
+	compiled, err := formula.Compile(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+	if points > maxFormulaPoints {
+		return nil, fmt.Errorf("at most %d points can be requested", maxFormulaPoints)
+	}
+
+	if opts.Resolution == "" {
+		opts.Resolution = vnstock.Resolution1D
+	}
+	if opts.PriceMode == "" {
+		opts.PriceMode = PriceModeAdjusted
+	}
+	if opts.Quality == "" {
+		opts.Quality = vnstock.QualityRepair
+	}
+	// Enough bars for the formula to warm up before the requested points
+	opts.Lookback = compiled.Lookback() + max(points, 1)
+
+	history, err := s.technical.loadHistory(ctx, symbol, opts)
+	if err != nil {
+		return nil, err
+	}
+	history, _, err = vnstock.CleanBars(history, opts.Resolution, opts.Quality)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, fmt.Errorf("no data for %s", symbol)
+	}
+
+	values := compiled.Eval(indicatorInputs(history, vnstock.IsIndex(symbol)))
+	last := len(history) - 1
+	result := &FormulaResult{
+		Symbol:     symbol,
+		Expression: compiled.String(),
+		Type:       compiled.Type(),
+		Resolution: opts.Resolution,
+		Timestamp:  history[last].Date,
+		Value:      formulaValue(compiled.Type(), values[last]),
+	}
+	for i := max(len(history)-points, 0); i < len(history) && points > 0; i++ {
+		result.Points = append(result.Points, FormulaPoint{
+			Time:  history[i].Date,
+			Value: formulaValue(compiled.Type(), values[i]),
+		})
+	}
+	return result, nil
+}
+
+// formulaValue converts a point of a formula series to its JSON value
+func formulaValue(t formula.Type, v float64) interface{} {
+	if !indicators.IsValid(v) {
+		return nil
+	}
+	if t == formula.Bool {
+		return v == 1
+	}
+	return v
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"vnstock-hybrid/internal/models"
+)
+
+func TestFormulaSaveAndEvaluate(t *testing.T) {
+	svc := NewFormulaService(nil, newSyntheticService())
+	ctx := context.Background()
+
+	if _, err := svc.Save(ctx, models.Formula{Name: "bad", Expression: "rsi(close) < 30"}); err == nil {
+		t.Error("saved a formula with a missing argument")
+	}
+	saved, err := svc.Save(ctx, models.Formula{Name: "long_sma_trend", Expression: "close > sma(close, 200)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Type != "bool" {
+		t.Errorf("type = %s, want bool", saved.Type)
+	}
+
+	// The 200 bar average needs more than the analysis loads by default
+	result, err := svc.EvaluateSaved(ctx, "long_sma_trend", "FPT", AnalyzeOptions{}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := result.Value.(bool); !ok {
+		t.Errorf("value = %v, want a boolean", result.Value)
+	}
+	if len(result.Points) != 5 || !result.Points[4].Time.Equal(result.Timestamp) {
+		t.Errorf("got %d points ending %v, want 5 ending %v", len(result.Points), result.Points, result.Timestamp)
+	}
+
+	if err := svc.Delete(ctx, "long_sma_trend"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Get(ctx, "long_sma_trend"); !errors.Is(err, ErrFormulaNotFound) {
+		t.Errorf("got %v after delete, want ErrFormulaNotFound", err)
+	}
+}
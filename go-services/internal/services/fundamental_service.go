@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"vnstock-hybrid/internal/models"
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+// financialsTTL is how long stored financial statements are used before they
+// are synced again. Reports are published quarterly, so a day is plenty.
+const financialsTTL = 24 * time.Hour
+
+// FundamentalService serves financial statements and valuation ratios,
+// keeping the local financial_statements store in sync with the provider
+type FundamentalService struct {
+	db         *gorm.DB
+	marketData vnstock.MarketDataProvider
+}
+
+// Fundamentals is the financial data of a stock valued at its latest price
+type Fundamentals struct {
+	Symbol    string                    `json:"symbol"`
+	Valuation *vnstock.Valuation        `json:"valuation"`
+	Quarterly []vnstock.FinancialReport `json:"quarterly"`
+	Annual    []vnstock.FinancialReport `json:"annual"`
+}
+
+// NewFundamentalService creates a new fundamental data service. Without a
+// database every request goes to the provider.
+func NewFundamentalService(db *gorm.DB, provider vnstock.MarketDataProvider) *FundamentalService {
+	return &FundamentalService{
+		db:         db,
+		marketData: provider,
+	}
+}
+
+// Get returns the reports of a stock and its valuation at the latest price
+func (s *FundamentalService) Get(ctx context.Context, symbol string) (*Fundamentals, error) {
+	quarterly, annual, err := s.allReports(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := s.marketData.GetLatestQuote(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s quote: %w", symbol, err)
+	}
+
+	valuation, err := vnstock.ComputeValuation(append(quarterly, annual...), quote.Price)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Fundamentals{
+		Symbol:    symbol,
+		Valuation: valuation,
+		Quarterly: quarterly,
+		Annual:    annual,
+	}, nil
+}
+
+// Valuation values a stock at price from its reports
+func (s *FundamentalService) Valuation(ctx context.Context, symbol string, price float64) (*vnstock.Valuation, error) {
+	quarterly, annual, err := s.allReports(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return vnstock.ComputeValuation(append(quarterly, annual...), price)
+}
+
+// allReports returns the quarterly and annual reports of a stock. A stock
+// missing one of the periods is fine; missing both is ErrNoFinancials.
+func (s *FundamentalService) allReports(ctx context.Context, symbol string) (quarterly, annual []vnstock.FinancialReport, err error) {
+	quarterly, err = s.Reports(ctx, symbol, vnstock.PeriodQuarter)
+	if err != nil && !errors.Is(err, vnstock.ErrNoFinancials) {
+		return nil, nil, err
+	}
+	annual, err = s.Reports(ctx, symbol, vnstock.PeriodYear)
+	if err != nil && !errors.Is(err, vnstock.ErrNoFinancials) {
+		return nil, nil, err
+	}
+	if len(quarterly) == 0 && len(annual) == 0 {
+		return nil, nil, vnstock.ErrNoFinancials
+	}
+	return quarterly, annual, nil
+}
+
+// Reports returns the reports of a stock for a period, oldest first. Stored
+// reports older than financialsTTL are synced first; if the provider is
+// unavailable the stored reports are used.
+func (s *FundamentalService) Reports(ctx context.Context, symbol string, period vnstock.Period) ([]vnstock.FinancialReport, error) {
+	fp, ok := s.marketData.(vnstock.FundamentalsProvider)
+	if vnstock.IsIndex(symbol) || (!ok && s.db == nil) {
+		return nil, vnstock.ErrNoFinancials
+	}
+	if s.db == nil {
+		return fp.GetFinancials(ctx, symbol, period)
+	}
+
+	var syncErr error
+	if ok && s.stale(ctx, symbol, period) {
+		if syncErr = s.sync(ctx, fp, symbol, period); syncErr != nil {
+			log.Printf("Warning: financials sync failed for %s: %v", symbol, syncErr)
+		}
+	}
+
+	var rows []models.FinancialStatement
+	err := s.db.WithContext(ctx).
+		Where("symbol = ? AND period = ?", symbol, string(period)).
+		Order("year ASC, quarter ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s financials: %w", symbol, err)
+	}
+	if len(rows) == 0 {
+		if syncErr != nil {
+			return nil, syncErr
+		}
+		return nil, vnstock.ErrNoFinancials
+	}
+
+	reports := make([]vnstock.FinancialReport, len(rows))
+	for i, r := range rows {
+		reports[i] = vnstock.FinancialReport{
+			Period:  vnstock.Period(r.Period),
+			Year:    r.Year,
+			Quarter: r.Quarter,
+			Income: vnstock.IncomeStatement{
+				Revenue:         r.Revenue,
+				GrossProfit:     r.GrossProfit,
+				OperatingProfit: r.OperatingProfit,
+				NetIncome:       r.NetIncome,
+			},
+			Balance: vnstock.BalanceSheet{
+				TotalAssets:      r.TotalAssets,
+				TotalLiabilities: r.TotalLiabilities,
+				TotalDebt:        r.TotalDebt,
+				Equity:           r.Equity,
+			},
+			CashFlow: vnstock.CashFlowStatement{
+				OperatingCashFlow:  r.OperatingCashFlow,
+				InvestingCashFlow:  r.InvestingCashFlow,
+				FinancingCashFlow:  r.FinancingCashFlow,
+				CapitalExpenditure: r.CapitalExpenditure,
+			},
+			SharesOutstanding: r.SharesOutstanding,
+		}
+	}
+	return reports, nil
+}
+
+// stale reports whether the stored reports of a period were last synced more
+// than financialsTTL ago
+func (s *FundamentalService) stale(ctx context.Context, symbol string, period vnstock.Period) bool {
+	var latest models.FinancialStatement
+	err := s.db.WithContext(ctx).
+		Select("updated_at").
+		Where("symbol = ? AND period = ?", symbol, string(period)).
+		Order("updated_at DESC").
+		First(&latest).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Warning: failed to read stored %s financials: %v", symbol, err)
+		}
+		return true
+	}
+	return time.Since(latest.UpdatedAt) > financialsTTL
+}
+
+// sync stores the provider's reports, replacing restated ones
+func (s *FundamentalService) sync(ctx context.Context, fp vnstock.FundamentalsProvider, symbol string, period vnstock.Period) error {
+	reports, err := fp.GetFinancials(ctx, symbol, period)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s financials: %w", symbol, err)
+	}
+	if len(reports) == 0 {
+		return nil
+	}
+
+	rows := make([]models.FinancialStatement, len(reports))
+	for i, r := range reports {
+		rows[i] = models.FinancialStatement{
+			Symbol:             symbol,
+			Period:             string(period),
+			Year:               r.Year,
+			Quarter:            r.Quarter,
+			Revenue:            r.Income.Revenue,
+			GrossProfit:        r.Income.GrossProfit,
+			OperatingProfit:    r.Income.OperatingProfit,
+			NetIncome:          r.Income.NetIncome,
+			TotalAssets:        r.Balance.TotalAssets,
+			TotalLiabilities:   r.Balance.TotalLiabilities,
+			TotalDebt:          r.Balance.TotalDebt,
+			Equity:             r.Balance.Equity,
+			OperatingCashFlow:  r.CashFlow.OperatingCashFlow,
+			InvestingCashFlow:  r.CashFlow.InvestingCashFlow,
+			FinancingCashFlow:  r.CashFlow.FinancingCashFlow,
+			CapitalExpenditure: r.CashFlow.CapitalExpenditure,
+			SharesOutstanding:  r.SharesOutstanding,
+		}
+	}
+
+	err = s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "symbol"}, {Name: "period"}, {Name: "year"}, {Name: "quarter"}},
+			UpdateAll: true,
+		}).
+		CreateInBatches(rows, 500).Error
+	if err != nil {
+		return fmt.Errorf("failed to store %s financials: %w", symbol, err)
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"vnstock-hybrid/internal/models"
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+// instrumentsTTL is how long the in-memory copy of the instrument master is
+// used before it is reloaded
+const instrumentsTTL = 10 * time.Minute
+
+// instrumentsRetry is how long after a failed reload the next one is tried,
+// so that a provider outage does not turn every lookup into a full listing
+const instrumentsRetry = 30 * time.Second
+
+// InstrumentService is the instrument master: every security and index the
+// services accept. It is stored in the instruments table, synced from the
+// provider's listing, and cached in memory for symbol lookups. Without a
+// database the provider's listing is used directly.
+type InstrumentService struct {
+	db         *gorm.DB
+	marketData vnstock.MarketDataProvider
+
+	mu       sync.RWMutex
+	bySymbol map[string]vnstock.SymbolInfo
+	loadedAt time.Time
+	// triedAt is the start of the latest reload, successful or not, and
+	// loadErr the error of the latest failed one
+	triedAt time.Time
+	loadErr error
+}
+
+// NewInstrumentService creates a new instrument master
+func NewInstrumentService(db *gorm.DB, provider vnstock.MarketDataProvider) *InstrumentService {
+	return &InstrumentService{
+		db:         db,
+		marketData: provider,
+	}
+}
+
+// Lookup returns the master entry of a symbol
+func (s *InstrumentService) Lookup(ctx context.Context, symbol string) (vnstock.SymbolInfo, bool) {
+	if s.due() {
+		if err := s.reload(ctx); err != nil {
+			log.Printf("Warning: failed to load instrument master: %v", err)
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.bySymbol == nil {
+		// The master has never loaded: accept well-formed symbols rather
+		// than rejecting everything
+		t, ok := vnstock.InferInstrumentType(symbol)
+		return vnstock.SymbolInfo{Symbol: symbol, Type: t}, ok
+	}
+	info, ok := s.bySymbol[symbol]
+	return info, ok
+}
+
+// List returns the instruments of a type, or all of them if t is empty,
+// optionally only those still listed
+func (s *InstrumentService) List(ctx context.Context, t vnstock.InstrumentType, activeOnly bool) ([]vnstock.SymbolInfo, error) {
+	if s.due() {
+		if err := s.reload(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.bySymbol == nil {
+		if s.loadErr != nil {
+			return nil, s.loadErr
+		}
+		return nil, fmt.Errorf("instrument master not loaded yet")
+	}
+	var infos []vnstock.SymbolInfo
+	for _, info := range s.bySymbol {
+		if (t == "" || info.Type == t) && (!activeOnly || info.Listed(now)) {
+			infos = append(infos, info)
+		}
+	}
+	return infos, nil
+}
+
+// Sync stores the provider's listing in the instruments table and returns how
+// many instruments it lists. Instruments the provider no longer lists are
+// marked delisted.
+func (s *InstrumentService) Sync(ctx context.Context) (int, error) {
+	if s.db == nil {
+		return 0, fmt.Errorf("instrument sync requires a database")
+	}
+
+	infos, err := s.fetch(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	rows := make([]models.Instrument, len(infos))
+	symbols := make([]string, len(infos))
+	for i, info := range infos {
+		rows[i] = models.Instrument{
+			Symbol:       info.Symbol,
+			Name:         info.Name,
+			Type:         string(info.Type),
+			Exchange:     info.Exchange,
+			Industry:     info.Industry,
+			Underlying:   info.Underlying,
+			ListedDate:   info.ListedDate,
+			DelistedDate: info.DelistedDate,
+			IsActive:     info.Listed(now),
+		}
+		symbols[i] = info.Symbol
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "symbol"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "type", "exchange", "industry", "underlying", "listed_date", "delisted_date", "is_active", "updated_at"}),
+		}).CreateInBatches(rows, 500).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Instrument{}).
+			Where("is_active = ? AND symbol NOT IN ?", true, symbols).
+			Updates(map[string]interface{}{
+				"is_active":     false,
+				"delisted_date": gorm.Expr("COALESCE(delisted_date, ?)", now),
+			}).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to store instruments: %w", err)
+	}
+
+	s.invalidate()
+	return len(infos), nil
+}
+
+// fetch returns the provider's listing plus the market indices, with missing
+// instrument types inferred from the symbol format
+func (s *InstrumentService) fetch(ctx context.Context) ([]vnstock.SymbolInfo, error) {
+	listed, err := s.marketData.ListSymbols(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list symbols: %w", err)
+	}
+	// An empty listing is a provider fault, not every instrument delisting at once
+	if len(listed) == 0 {
+		return nil, fmt.Errorf("provider listed no symbols")
+	}
+
+	infos := make([]vnstock.SymbolInfo, 0, len(listed)+len(vnstock.IndexSymbols))
+	for _, info := range listed {
+		if info.Type == "" {
+			info.Type = vnstock.InstrumentStock
+			if t, ok := vnstock.InferInstrumentType(info.Symbol); ok {
+				info.Type = t
+			}
+		}
+		if info.Name == "" {
+			info.Name = info.Symbol
+		}
+		infos = append(infos, info)
+	}
+	return append(infos, vnstock.IndexInstruments()...), nil
+}
+
+// due reports whether the master should be reloaded now: it is older than
+// instrumentsTTL and no reload was tried within instrumentsRetry. A true
+// result claims the reload, so concurrent callers do not repeat it.
+func (s *InstrumentService) due() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.loadedAt) < instrumentsTTL || now.Sub(s.triedAt) < instrumentsRetry {
+		return false
+	}
+	s.triedAt = now
+	return true
+}
+
+// reload replaces the in-memory master. A database without instruments is
+// synced first.
+func (s *InstrumentService) reload(ctx context.Context) error {
+	err := s.load(ctx)
+	s.mu.Lock()
+	s.loadErr = err
+	s.mu.Unlock()
+	return err
+}
+
+// load reads the master from the database or the provider
+func (s *InstrumentService) load(ctx context.Context) error {
+	var infos []vnstock.SymbolInfo
+	if s.db == nil {
+		var err error
+		if infos, err = s.fetch(ctx); err != nil {
+			return err
+		}
+	} else {
+		var rows []models.Instrument
+		if err := s.db.WithContext(ctx).Find(&rows).Error; err != nil {
+			return fmt.Errorf("failed to load instruments: %w", err)
+		}
+		if len(rows) == 0 {
+			if _, err := s.Sync(ctx); err != nil {
+				return err
+			}
+			if err := s.db.WithContext(ctx).Find(&rows).Error; err != nil {
+				return fmt.Errorf("failed to load instruments: %w", err)
+			}
+		}
+
+		infos = make([]vnstock.SymbolInfo, len(rows))
+		for i, r := range rows {
+			infos[i] = vnstock.SymbolInfo{
+				Symbol:       r.Symbol,
+				Name:         r.Name,
+				Exchange:     r.Exchange,
+				Industry:     r.Industry,
+				Type:         vnstock.InstrumentType(r.Type),
+				Underlying:   r.Underlying,
+				ListedDate:   r.ListedDate,
+				DelistedDate: r.DelistedDate,
+			}
+			// A manually deactivated instrument counts as delisted
+			if !r.IsActive && r.DelistedDate == nil {
+				infos[i].DelistedDate = &r.UpdatedAt
+			}
+		}
+	}
+
+	bySymbol := make(map[string]vnstock.SymbolInfo, len(infos))
+	for _, info := range infos {
+		bySymbol[info.Symbol] = info
+	}
+
+	s.mu.Lock()
+	s.bySymbol = bySymbol
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+// invalidate makes the next lookup reload the master
+func (s *InstrumentService) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.triedAt = time.Time{}
+	s.mu.Unlock()
+}
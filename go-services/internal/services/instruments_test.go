@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+func TestInstrumentLookup(t *testing.T) {
+	instruments := newSyntheticService().Instruments()
+	ctx := context.Background()
+
+	for symbol, want := range map[string]vnstock.InstrumentType{
+		"VNM":      vnstock.InstrumentStock,
+		"E1VFVN30": vnstock.InstrumentETF,
+		"CFPT2502": vnstock.InstrumentCW,
+		"VN30F1M":  vnstock.InstrumentFuture,
+		"VNINDEX":  vnstock.InstrumentIndex,
+	} {
+		if info, ok := instruments.Lookup(ctx, symbol); !ok || info.Type != want {
+			t.Errorf("Lookup(%s) = %+v, %v, want type %s", symbol, info, ok, want)
+		}
+	}
+
+	// Well-formed but not listed
+	if _, ok := instruments.Lookup(ctx, "XYZ"); ok {
+		t.Error("Lookup(XYZ) found an unlisted symbol")
+	}
+
+	// Expired warrants stay in the master but are no longer listed
+	cws, err := instruments.List(ctx, vnstock.InstrumentCW, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cws) != 1 || cws[0].Symbol != "CFPT2502" {
+		t.Errorf("got listed warrants %+v, want CFPT2502 only", cws)
+	}
+}
+
+// downProvider fails every listing, counting the attempts
+type downProvider struct {
+	*vnstock.MockProvider
+	calls int
+}
+
+func (p *downProvider) ListSymbols(ctx context.Context, exchange string) ([]vnstock.SymbolInfo, error) {
+	p.calls++
+	return nil, errors.New("provider down")
+}
+
+func TestInstrumentLookupBacksOffWhileProviderIsDown(t *testing.T) {
+	provider := &downProvider{MockProvider: vnstock.NewMockProvider()}
+	instruments := NewInstrumentService(nil, provider)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		// Well-formed symbols are still accepted
+		if _, ok := instruments.Lookup(ctx, "VNM"); !ok {
+			t.Fatal("Lookup(VNM) rejected while the master is unavailable")
+		}
+	}
+	if provider.calls != 1 {
+		t.Errorf("provider listed %d times, want 1 within the retry interval", provider.calls)
+	}
+	if _, err := instruments.List(ctx, "", false); err == nil {
+		t.Error("List succeeded without a master")
+	}
+}
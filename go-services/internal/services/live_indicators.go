@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"vnstock-hybrid/internal/indicators"
+	"vnstock-hybrid/pkg/realtime"
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+// liveStateTTL is how long a stored indicator state outlives its last bar
+const liveStateTTL = 7 * 24 * time.Hour
+
+// LiveIndicators keeps streaming indicator states for the bars built from the
+// realtime feed, so each live bar updates the indicators in O(1) instead of
+// recomputing them over the whole history. A closed bar advances the state of
+// its series, which is stored in Redis so a restart resumes where it stopped;
+// a forming bar is evaluated without advancing it. A series seen for the first
+// time is seeded from its history.
+type LiveIndicators struct {
+	redis     *redis.Client
+	technical *TechnicalService
+
+	// states is only used by the goroutine applying updates
+	states map[string]*liveState
+
+	mu     sync.RWMutex
+	latest map[string]indicators.Snapshot
+}
+
+// liveState is the stored state of one series
+type liveState struct {
+	Set *indicators.StreamSet `json:"set"`
+	// LastBar is the start of the last closed bar applied
+	LastBar time.Time `json:"last_bar"`
+}
+
+// NewLiveIndicators creates live indicators seeded from the history technical
+// analysis uses. Without Redis states are only kept in memory.
+func NewLiveIndicators(redis *redis.Client, technical *TechnicalService) *LiveIndicators {
+	return &LiveIndicators{
+		redis:     redis,
+		technical: technical,
+		states:    make(map[string]*liveState),
+		latest:    make(map[string]indicators.Snapshot),
+	}
+}
+
+// Run applies every update published on hub until ctx is done
+func (l *LiveIndicators) Run(ctx context.Context, hub *realtime.Hub) {
+	sub := hub.Subscribe(1024)
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case u, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			l.Apply(ctx, u)
+		}
+	}
+}
+
+// Apply updates the indicators of a series with a bar update and returns
+// their values. Updates of one series must be applied in order from a single
+// goroutine.
+func (l *LiveIndicators) Apply(ctx context.Context, u realtime.BarUpdate) indicators.Snapshot {
+	key := liveStateKey(u.Symbol, u.Resolution)
+	state := l.state(ctx, u.Symbol, u.Resolution, u.Bar.Date)
+
+	// Bars already applied, e.g. replayed after a restart, are ignored
+	if !u.Bar.Date.After(state.LastBar) {
+		snap, _ := l.Latest(u.Symbol, u.Resolution)
+		return snap
+	}
+
+	bar := streamBar(u.Bar, vnstock.IsIndex(u.Symbol))
+	var snap indicators.Snapshot
+	if u.Closed {
+		snap = state.Set.Update(bar)
+		state.LastBar = u.Bar.Date
+		l.save(ctx, key, state)
+	} else {
+		snap = state.Set.Peek(bar)
+	}
+
+	l.mu.Lock()
+	l.latest[key] = snap
+	l.mu.Unlock()
+	return snap
+}
+
+// Latest returns the indicator values after the last update of a series
+func (l *LiveIndicators) Latest(symbol string, resolution vnstock.Resolution) (indicators.Snapshot, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	snap, ok := l.latest[liveStateKey(symbol, resolution)]
+	return snap, ok
+}
+
+// state returns the state of a series, loading it from Redis or seeding it
+// from the bars before the first live bar
+func (l *LiveIndicators) state(ctx context.Context, symbol string, resolution vnstock.Resolution, first time.Time) *liveState {
+	key := liveStateKey(symbol, resolution)
+	if state, ok := l.states[key]; ok {
+		return state
+	}
+
+	state, err := l.load(ctx, key)
+	if err != nil {
+		log.Printf("Warning: failed to load indicator state %s: %v", key, err)
+	}
+	if state == nil {
+		state = l.seed(ctx, symbol, resolution, first)
+	}
+	l.states[key] = state
+	return state
+}
+
+// seed builds a state from the history of a series before first
+func (l *LiveIndicators) seed(ctx context.Context, symbol string, resolution vnstock.Resolution, first time.Time) *liveState {
+	state := &liveState{Set: indicators.NewStreamSet()}
+	if l.technical == nil {
+		return state
+	}
+
+	history, err := l.technical.loadHistory(ctx, symbol, AnalyzeOptions{Resolution: resolution, PriceMode: PriceModeRaw})
+	if err == nil {
+		history, _, err = vnstock.CleanBars(history, resolution, vnstock.QualityRepair)
+	}
+	if err != nil {
+		log.Printf("Warning: failed to seed live indicators for %s: %v", symbol, err)
+		return state
+	}
+
+	isIndex := vnstock.IsIndex(symbol)
+	for _, b := range history {
+		if !b.Date.Before(first) {
+			break
+		}
+		state.Set.Update(streamBar(b, isIndex))
+		state.LastBar = b.Date
+	}
+	return state
+}
+
+// load returns the stored state of a series, or nil if there is none
+func (l *LiveIndicators) load(ctx context.Context, key string) (*liveState, error) {
+	if l.redis == nil {
+		return nil, nil
+	}
+	data, err := l.redis.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var state liveState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("corrupt state: %w", err)
+	}
+	if state.Set == nil {
+		return nil, fmt.Errorf("corrupt state: no indicators")
+	}
+	return &state, nil
+}
+
+// save stores the state of a series
+func (l *LiveIndicators) save(ctx context.Context, key string, state *liveState) {
+	if l.redis == nil {
+		return
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		log.Printf("Warning: failed to encode indicator state %s: %v", key, err)
+		return
+	}
+	if err := l.redis.Set(ctx, key, data, liveStateTTL).Err(); err != nil {
+		log.Printf("Warning: failed to store indicator state %s: %v", key, err)
+	}
+}
+
+// liveStateKey is the Redis key of the indicator state of a series
+func liveStateKey(symbol string, resolution vnstock.Resolution) string {
+	return fmt.Sprintf("indicators:state:%s:%s", symbol, resolution)
+}
+
+// streamBar converts a bar to the input of the streaming indicators, with the
+// same activity measure as technical analysis
+func streamBar(b vnstock.OHLCV, isIndex bool) indicators.Bar {
+	return indicators.Bar{
+		High:   b.High,
+		Low:    b.Low,
+		Close:  b.Close,
+		Volume: activity(b, isIndex),
+	}
+}
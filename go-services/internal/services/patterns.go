@@ -0,0 +1,70 @@
+package services
+
+import (
+	"vnstock-hybrid/internal/patterns"
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+// maxCandleScore caps the score of the candlestick patterns of one bar,
+// which often overlap, e.g. a hammer that is also a bullish harami
+const maxCandleScore = 1.5
+
+// maxChartScore caps the score of the chart patterns, which may overlap,
+// e.g. a double top and a bear flag falling from its second top
+const maxChartScore = 2.0
+
+// candleLabels are the Vietnamese names of the candlestick patterns used in
+// reasons
+var candleLabels = map[string]string{
+	"doji":                 "Doji",
+	"dragonfly_doji":       "Doji chuồn chuồn",
+	"gravestone_doji":      "Doji bia mộ",
+	"spinning_top":         "Con xoay (Spinning Top)",
+	"hammer":               "Búa (Hammer)",
+	"hanging_man":          "Người treo cổ (Hanging Man)",
+	"inverted_hammer":      "Búa ngược (Inverted Hammer)",
+	"shooting_star":        "Sao băng (Shooting Star)",
+	"bullish_marubozu":     "Marubozu tăng",
+	"bearish_marubozu":     "Marubozu giảm",
+	"bullish_engulfing":    "Nhấn chìm tăng (Bullish Engulfing)",
+	"bearish_engulfing":    "Nhấn chìm giảm (Bearish Engulfing)",
+	"bullish_harami":       "Harami tăng",
+	"bearish_harami":       "Harami giảm",
+	"piercing_line":        "Xuyên thấu (Piercing Line)",
+	"dark_cloud_cover":     "Mây đen che phủ (Dark Cloud Cover)",
+	"tweezer_bottom":       "Đáy nhíp (Tweezer Bottom)",
+	"tweezer_top":          "Đỉnh nhíp (Tweezer Top)",
+	"morning_star":         "Sao mai (Morning Star)",
+	"evening_star":         "Sao hôm (Evening Star)",
+	"three_white_soldiers": "Ba chàng lính trắng (Three White Soldiers)",
+	"three_black_crows":    "Ba con quạ đen (Three Black Crows)",
+}
+
+// chartLabels are the Vietnamese names of the chart patterns used in reasons
+var chartLabels = map[string]string{
+	patterns.DoubleTop:               "Hai đỉnh (Double Top)",
+	patterns.DoubleBottom:            "Hai đáy (Double Bottom)",
+	patterns.HeadAndShoulders:        "Vai đầu vai (Head and Shoulders)",
+	patterns.InverseHeadAndShoulders: "Vai đầu vai ngược (Inverse Head and Shoulders)",
+	patterns.AscendingTriangle:       "Tam giác tăng (Ascending Triangle)",
+	patterns.DescendingTriangle:      "Tam giác giảm (Descending Triangle)",
+	patterns.SymmetricalTriangle:     "Tam giác cân (Symmetrical Triangle)",
+	patterns.BullFlag:                "Cờ tăng (Bull Flag)",
+	patterns.BearFlag:                "Cờ giảm (Bear Flag)",
+}
+
+// trendLabels describe the trend before a pattern in reasons
+var trendLabels = map[patterns.Trend]string{
+	patterns.Uptrend:   "sau xu hướng tăng",
+	patterns.Downtrend: "sau xu hướng giảm",
+	patterns.Sideways:  "khi giá đi ngang",
+}
+
+// candles converts bars to the input of pattern recognition
+func candles(bars []vnstock.OHLCV) []patterns.Candle {
+	result := make([]patterns.Candle, len(bars))
+	for i, b := range bars {
+		result[i] = patterns.Candle{Time: b.Date, Open: b.Open, High: b.High, Low: b.Low, Close: b.Close}
+	}
+	return result
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"vnstock-hybrid/internal/models"
+	"vnstock-hybrid/pkg/calendar"
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+// syncDelay is how long after the close the daily sync runs, leaving time for
+// the provider to publish final prices
+const syncDelay = 45 * time.Minute
+
+// SyncScheduler syncs the instrument master and the daily bars of all listed
+// instruments after each trading session
+type SyncScheduler struct {
+	db          *gorm.DB
+	bars        *BarSyncService
+	instruments *InstrumentService
+}
+
+// NewSyncScheduler creates a new sync scheduler
+func NewSyncScheduler(db *gorm.DB, provider vnstock.MarketDataProvider) *SyncScheduler {
+	return &SyncScheduler{
+		db:          db,
+		bars:        NewBarSyncService(db, provider),
+		instruments: NewInstrumentService(db, provider),
+	}
+}
+
+// Run syncs once after every trading day's close until ctx is cancelled
+func (s *SyncScheduler) Run(ctx context.Context) {
+	for {
+		next := nextSyncTime(time.Now())
+		log.Printf("Next daily bar sync at %s", next.Format(time.RFC3339))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if _, err := s.SyncAll(ctx); err != nil {
+			log.Printf("Warning: daily bar sync failed: %v", err)
+		}
+	}
+}
+
+// SyncAll syncs the instrument master, then the daily bars of every listed
+// instrument and the investor flows of stocks and ETFs, and returns how many
+// bars were stored
+func (s *SyncScheduler) SyncAll(ctx context.Context) (int, error) {
+	if listed, err := s.instruments.Sync(ctx); err != nil {
+		log.Printf("Warning: instrument sync failed: %v", err)
+	} else {
+		log.Printf("Instrument sync found %d listed instruments", listed)
+	}
+
+	var instruments []models.Instrument
+	err := s.db.WithContext(ctx).
+		Select("symbol", "type").
+		Where("is_active = ?", true).
+		Find(&instruments).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to list active instruments: %w", err)
+	}
+
+	total := 0
+	for _, inst := range instruments {
+		symbol := inst.Symbol
+		stored, err := s.bars.Sync(ctx, symbol, vnstock.Resolution1D, historyDays[vnstock.Resolution1D])
+		if err != nil {
+			log.Printf("Warning: bar sync failed for %s: %v", symbol, err)
+			continue
+		}
+		total += stored
+
+		if t := vnstock.InstrumentType(inst.Type); t != vnstock.InstrumentStock && t != vnstock.InstrumentETF {
+			continue
+		}
+		if _, err := s.bars.SyncFlows(ctx, symbol, flowDays); err != nil && !errors.Is(err, vnstock.ErrNoFlows) {
+			log.Printf("Warning: flow sync failed for %s: %v", symbol, err)
+		}
+	}
+
+	log.Printf("Daily bar sync stored %d bars for %d instruments", total, len(instruments))
+	return total, nil
+}
+
+// nextSyncTime returns the first post-close sync time after now
+func nextSyncTime(now time.Time) time.Time {
+	if calendar.IsTradingDay(now) {
+		if run := calendar.Default.Close(now).Add(syncDelay); run.After(now) {
+			return run
+		}
+	}
+	return calendar.Default.Close(calendar.NextTradingDay(now)).Add(syncDelay)
+}
@@ -3,7 +3,11 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"math"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,6 +16,9 @@ import (
 
 	"vnstock-hybrid/internal/indicators"
 	"vnstock-hybrid/internal/models"
+	"vnstock-hybrid/internal/patterns"
+	"vnstock-hybrid/pkg/calendar"
+	"vnstock-hybrid/pkg/rules"
 	"vnstock-hybrid/pkg/vnstock"
 )
 
@@ -19,54 +26,187 @@ import (
 type TechnicalService struct {
 	db           *gorm.DB
 	redis        *redis.Client
-	marketClient *vnstock.Client
+	marketData   vnstock.MarketDataProvider
+	bars         *BarSyncService
+	fundamentals *FundamentalService
+	instruments  *InstrumentService
+}
+
+// analysisBars is the number of bars loaded for each analysis
+const analysisBars = 100
+
+// historyDays is the number of trading days needed to cover analysisBars bars of each resolution
+var historyDays = map[vnstock.Resolution]int{
+	vnstock.Resolution1m:  5,
+	vnstock.Resolution5m:  7,
+	vnstock.Resolution15m: 10,
+	vnstock.Resolution1h:  30,
+	vnstock.Resolution1D:  250,
+	vnstock.Resolution1W:  600,
+	vnstock.Resolution1M:  2400,
+}
+
+const (
+	// openCacheTTL is how long results are cached while the market is trading
+	openCacheTTL = 5 * time.Minute
+	// maxClosedCacheTTL caps the cache lifetime outside trading hours
+	maxClosedCacheTTL = time.Hour
+)
+
+// baseBarsPerBar is the number of daily bars making up one weekly or monthly bar
+var baseBarsPerBar = map[vnstock.Resolution]int{
+	vnstock.Resolution1W: 5,
+	vnstock.Resolution1M: 23,
+}
+
+// Price modes for AnalyzeOptions
+const (
+	PriceModeRaw      = "raw"
+	PriceModeAdjusted = "adjusted"
+)
+
+// AnalyzeOptions controls how a symbol is analyzed
+type AnalyzeOptions struct {
+	Resolution vnstock.Resolution
+	// PriceMode selects raw prices or prices back-adjusted for corporate actions
+	PriceMode string
+	// Quality selects how data quality issues are handled: flag, repair or reject
+	Quality string
+	// Indicators are computed in addition to the standard set and returned
+	// keyed by spec
+	Indicators []indicators.Spec
+	// Lookback is the minimum number of bars to load, for callers such as
+	// formulas that look further back than the analysis
+	Lookback int
+}
+
+// bars returns how many bars the analysis needs: analysisBars, or more if a
+// requested indicator or the caller looks further back
+func (o AnalyzeOptions) bars() int {
+	bars := max(analysisBars, o.Lookback)
+	for _, spec := range o.Indicators {
+		if lookback := spec.Lookback(); lookback > bars {
+			bars = lookback
+		}
+	}
+	return bars
+}
+
+// specKey returns the requested indicators in canonical form
+func (o AnalyzeOptions) specKey() string {
+	keys := make([]string, len(o.Indicators))
+	for i, spec := range o.Indicators {
+		keys[i] = spec.String()
+	}
+	return strings.Join(keys, ",")
 }
 
 // TechnicalResult represents the result of technical analysis
 type TechnicalResult struct {
-	Symbol      string                      `json:"symbol"`
-	Timestamp   time.Time                   `json:"timestamp"`
-	Price       PriceData                   `json:"price"`
-	RSI         float64                     `json:"rsi"`
-	MACD        *indicators.MACD            `json:"macd"`
-	Bollinger   *indicators.BollingerBands  `json:"bollinger"`
-	Stochastic  *indicators.Stochastic      `json:"stochastic"`
-	ADX         *indicators.ADX             `json:"adx"`
-	SMA20       float64                     `json:"sma_20"`
-	SMA50       float64                     `json:"sma_50"`
-	EMA12       float64                     `json:"ema_12"`
-	EMA26       float64                     `json:"ema_26"`
-	ATR         float64                     `json:"atr"`
-	VWAP        float64                     `json:"vwap"`
-	Signal      string                      `json:"signal"`
-	Confidence  float64                     `json:"confidence"`
-	Score       float64                     `json:"score"`
-	Reasons     []string                    `json:"reasons"`
+	Symbol     string                       `json:"symbol"`
+	IsIndex    bool                         `json:"is_index"`
+	Resolution vnstock.Resolution           `json:"resolution"`
+	PriceMode  string                       `json:"price_mode"`
+	Timestamp  time.Time                    `json:"timestamp"`
+	Price      PriceData                    `json:"price"`
+	RSI        *float64                     `json:"rsi"`
+	MACD       *indicators.MACD             `json:"macd"`
+	Bollinger  *indicators.BollingerBands   `json:"bollinger"`
+	Stochastic *indicators.Stochastic       `json:"stochastic"`
+	ADX        *indicators.ADX              `json:"adx"`
+	Ichimoku   *indicators.Ichimoku         `json:"ichimoku"`
+	SAR        *indicators.ParabolicSAR     `json:"parabolic_sar"`
+	Supertrend *indicators.Supertrend       `json:"supertrend"`
+	Keltner    *indicators.KeltnerChannels  `json:"keltner"`
+	Donchian   *indicators.DonchianChannels `json:"donchian"`
+	Breakout   *Breakout                    `json:"breakout,omitempty"`
+	Candles    []patterns.CandlePattern     `json:"candlestick_patterns"`
+	Charts     []patterns.ChartPattern      `json:"chart_patterns"`
+	VolumeFlow *VolumeFlow                  `json:"volume_flow"`
+	SMA20      *float64                     `json:"sma_20"`
+	SMA50      *float64                     `json:"sma_50"`
+	EMA12      *float64                     `json:"ema_12"`
+	EMA26      *float64                     `json:"ema_26"`
+	ATR        *float64                     `json:"atr"`
+	VWAP       *float64                     `json:"vwap"`
+	Limits     *rules.Assessment            `json:"limits,omitempty"`
+	Targets    *PriceTargets                `json:"targets,omitempty"`
+	Quality    *vnstock.DataQuality         `json:"data_quality"`
+	Flows      *FlowSummary                 `json:"flows,omitempty"`
+	Valuation  *vnstock.Valuation           `json:"valuation,omitempty"`
+	Indicators map[string]indicators.Values `json:"indicators,omitempty"`
+	Signal     string                       `json:"signal"`
+	Confidence float64                      `json:"confidence"`
+	Score      float64                      `json:"score"`
+	Reasons    []string                     `json:"reasons"`
 }
 
 // PriceData represents current price information
 type PriceData struct {
-	Open         float64 `json:"open"`
-	High         float64 `json:"high"`
-	Low          float64 `json:"low"`
-	Close        float64 `json:"close"`
-	Volume       int64   `json:"volume"`
+	Open          float64 `json:"open"`
+	High          float64 `json:"high"`
+	Low           float64 `json:"low"`
+	Close         float64 `json:"close"`
+	Volume        int64   `json:"volume"`
+	Value         float64 `json:"value,omitempty"`
 	ChangePercent float64 `json:"change_percent"`
 }
 
+// PriceTargets are suggested exit prices, rounded to valid ticks
+type PriceTargets struct {
+	ShortTermTarget  float64 `json:"short_term_target"`
+	ShortTermStop    float64 `json:"short_term_stop"`
+	MediumTermTarget float64 `json:"medium_term_target"`
+	MediumTermStop   float64 `json:"medium_term_stop"`
+	// TrailingStop is the tightest trend-following stop below the close
+	// and TrailingStopBasis the overlay it comes from
+	TrailingStop      float64 `json:"trailing_stop,omitempty"`
+	TrailingStopBasis string  `json:"trailing_stop_basis,omitempty"`
+}
+
 // NewTechnicalService creates a new technical analysis service
-func NewTechnicalService(db *gorm.DB, redis *redis.Client, client *vnstock.Client) *TechnicalService {
-	return &TechnicalService{
+func NewTechnicalService(db *gorm.DB, redis *redis.Client, provider vnstock.MarketDataProvider) *TechnicalService {
+	svc := &TechnicalService{
 		db:           db,
 		redis:        redis,
-		marketClient: client,
+		marketData:   provider,
+		fundamentals: NewFundamentalService(db, provider),
+		instruments:  NewInstrumentService(db, provider),
+	}
+	if db != nil {
+		svc.bars = NewBarSyncService(db, provider)
 	}
+	return svc
 }
 
 // Analyze performs technical analysis for a single symbol
-func (s *TechnicalService) Analyze(ctx context.Context, symbol string) (*TechnicalResult, error) {
+func (s *TechnicalService) Analyze(ctx context.Context, symbol string, opts AnalyzeOptions) (*TechnicalResult, error) {
+	if opts.Resolution == "" {
+		opts.Resolution = vnstock.Resolution1D
+	}
+	if opts.PriceMode == "" {
+		opts.PriceMode = PriceModeAdjusted
+	}
+	// Prices are adjusted for the corporate actions stored with a database; a
+	// symbol without any is analyzed, and reported, as raw
+	if opts.PriceMode == PriceModeAdjusted {
+		stored, err := s.hasCorporateActions(ctx, symbol)
+		if err != nil {
+			return nil, err
+		}
+		if !stored {
+			opts.PriceMode = PriceModeRaw
+		}
+	}
+	if opts.Quality == "" {
+		opts.Quality = vnstock.QualityRepair
+	}
+
 	// Check cache first
-	cacheKey := fmt.Sprintf("technical:%s:latest", symbol)
+	cacheKey := fmt.Sprintf("technical:%s:%s:%s:%s:latest", symbol, opts.Resolution, opts.PriceMode, opts.Quality)
+	if len(opts.Indicators) > 0 {
+		cacheKey += ":" + opts.specKey()
+	}
 	if s.redis != nil {
 		cached, err := s.redis.Get(ctx, cacheKey).Result()
 		if err == nil {
@@ -77,13 +217,29 @@ func (s *TechnicalService) Analyze(ctx context.Context, symbol string) (*Technic
 		}
 	}
 
-	// Fetch historical data (use mock data for now)
-	history := s.marketClient.GetMockData(symbol, 100)
+	// Load historical data
+	history, err := s.loadHistory(ctx, symbol, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate before computing indicators
+	history, quality, err := vnstock.CleanBars(history, opts.Resolution, opts.Quality)
+	if err != nil {
+		return nil, err
+	}
 	if len(history) < 26 {
 		return nil, fmt.Errorf("insufficient data for analysis")
 	}
 
-	// Extract price arrays
+	// Requested indicators may look further back than the standard set,
+	// which is always computed over the same number of bars
+	extended := history
+	history = lastBars(history, analysisBars)
+
+	// Extract price arrays. Index volume is replaced by traded value, which
+	// is comparable across sessions as the constituents change.
+	isIndex := vnstock.IsIndex(symbol)
 	closes := make([]float64, len(history))
 	highs := make([]float64, len(history))
 	lows := make([]float64, len(history))
@@ -93,7 +249,7 @@ func (s *TechnicalService) Analyze(ctx context.Context, symbol string) (*Technic
 		closes[i] = h.Close
 		highs[i] = h.High
 		lows[i] = h.Low
-		volumes[i] = h.Volume
+		volumes[i] = activity(h, isIndex)
 	}
 
 	// Calculate indicators concurrently
@@ -103,9 +259,14 @@ func (s *TechnicalService) Analyze(ctx context.Context, symbol string) (*Technic
 	var bbVal *indicators.BollingerBands
 	var stochVal *indicators.Stochastic
 	var adxVal *indicators.ADX
+	var ichimokuVal *indicators.Ichimoku
+	var sarVal *indicators.ParabolicSAR
+	var supertrendVal *indicators.Supertrend
+	var keltnerVal *indicators.KeltnerChannels
+	var donchianSeries *indicators.DonchianSeries
 	var sma20Val, sma50Val, ema12Val, ema26Val, atrVal, vwapVal float64
 
-	wg.Add(8)
+	wg.Add(10)
 
 	go func() {
 		defer wg.Done()
@@ -132,6 +293,19 @@ func (s *TechnicalService) Analyze(ctx context.Context, symbol string) (*Technic
 		adxVal = indicators.CalculateADX(highs, lows, closes, 14)
 	}()
 
+	go func() {
+		defer wg.Done()
+		ichimokuVal = indicators.CalculateIchimoku(highs, lows, closes, 9, 26, 52, 26)
+	}()
+
+	go func() {
+		defer wg.Done()
+		sarVal = indicators.CalculateParabolicSAR(highs, lows, 0.02, 0.2)
+		supertrendVal = indicators.CalculateSupertrend(highs, lows, closes, 10, 3)
+		keltnerVal = indicators.CalculateKeltner(highs, lows, closes, 20, 10, 2)
+		donchianSeries = indicators.CalculateDonchianSeries(highs, lows, donchianBars)
+	}()
+
 	go func() {
 		defer wg.Done()
 		sma20Val = indicators.SMALatest(closes, 20)
@@ -152,41 +326,130 @@ func (s *TechnicalService) Analyze(ctx context.Context, symbol string) (*Technic
 
 	wg.Wait()
 
+	// Trailing levels and breakouts of the trend overlays
+	var donchianVal *indicators.DonchianChannels
+	if donchianSeries != nil {
+		last := len(closes) - 1
+		donchianVal = &indicators.DonchianChannels{
+			Upper:  donchianSeries.Upper[last],
+			Middle: donchianSeries.Middle[last],
+			Lower:  donchianSeries.Lower[last],
+		}
+	}
+	breakout := detectBreakout(closes, donchianSeries, keltnerVal)
+	stop, stopBasis := trailingStop(closes[len(closes)-1], supertrendVal, sarVal, donchianVal)
+
+	// Candlestick patterns completed by the latest bar
+	candlePatterns := patterns.DetectCandles(candles(history))
+	// Chart formations among the swings of the loaded bars
+	chartPatterns := patterns.DetectCharts(candles(history))
+
+	// Volume and money flow over the same bars
+	volumeFlow := summarizeVolumeFlow(indicatorInputs(history, isIndex))
+
+	// Requested indicators, keyed by spec
+	var requested map[string]indicators.Values
+	if len(opts.Indicators) > 0 {
+		in := indicatorInputs(extended, isIndex)
+		requested = make(map[string]indicators.Values, len(opts.Indicators))
+		for _, spec := range opts.Indicators {
+			requested[spec.String()] = spec.Latest(in)
+		}
+	}
+
 	// Current price data
 	latest := history[len(history)-1]
 	previous := history[len(history)-2]
 	changePercent := ((latest.Close - previous.Close) / previous.Close) * 100
 
+	// Price limits of the latest session. Indices, futures and bonds have no
+	// price band, and the band of covered warrants is not modelled.
+	instrument, exchange := s.instrumentFor(ctx, symbol)
+	var limits *rules.Assessment
+	if instrument.HasPriceBand() {
+		limits = sessionLimits(history, opts.Resolution, exchange, instrument)
+	}
+
+	// Investor flows of the latest sessions, for stocks and ETFs, and the
+	// valuation of stocks at the latest close
+	var flows *FlowSummary
+	if instrument == vnstock.InstrumentStock || instrument == vnstock.InstrumentETF {
+		flows = summarizeFlows(s.loadFlows(ctx, symbol))
+	}
+	var valuation *vnstock.Valuation
+	if instrument == vnstock.InstrumentStock {
+		valuation, err = s.fundamentals.Valuation(ctx, symbol, latest.Close)
+		if err != nil && !errors.Is(err, vnstock.ErrNoFinancials) {
+			log.Printf("Warning: valuation failed for %s: %v", symbol, err)
+		}
+	}
+
 	// Generate signals
-	signal, confidence, score, reasons := s.generateSignals(
-		closes[len(closes)-1],
-		rsiVal, macdVal, bbVal, stochVal, adxVal,
-		sma20Val, sma50Val,
-		float64(latest.Volume), float64(previous.Volume),
-	)
+	signal, confidence, score, reasons := s.generateSignals(signalInputs{
+		price:         closes[len(closes)-1],
+		rsi:           rsiVal,
+		macd:          macdVal,
+		bb:            bbVal,
+		stoch:         stochVal,
+		adx:           adxVal,
+		ichimoku:      ichimokuVal,
+		sar:           sarVal,
+		supertrend:    supertrendVal,
+		breakout:      breakout,
+		candles:       candlePatterns,
+		charts:        chartPatterns,
+		volumeFlow:    volumeFlow,
+		sma20:         sma20Val,
+		sma50:         sma50Val,
+		currentVolume: float64(activity(latest, isIndex)),
+		avgVolume:     float64(activity(previous, isIndex)),
+		limits:        limits,
+		flows:         flows,
+		isIndex:       isIndex,
+		flagged:       quality.Flagged,
+	})
 
 	result := &TechnicalResult{
-		Symbol:    symbol,
-		Timestamp: time.Now(),
+		Symbol:     symbol,
+		IsIndex:    isIndex,
+		Resolution: opts.Resolution,
+		PriceMode:  opts.PriceMode,
+		Timestamp:  time.Now(),
 		Price: PriceData{
 			Open:          latest.Open,
 			High:          latest.High,
 			Low:           latest.Low,
 			Close:         latest.Close,
 			Volume:        latest.Volume,
+			Value:         latest.Value,
 			ChangePercent: changePercent,
 		},
-		RSI:        rsiVal,
+		RSI:        indicators.Nullable(rsiVal),
 		MACD:       macdVal,
 		Bollinger:  bbVal,
 		Stochastic: stochVal,
 		ADX:        adxVal,
-		SMA20:      sma20Val,
-		SMA50:      sma50Val,
-		EMA12:      ema12Val,
-		EMA26:      ema26Val,
-		ATR:        atrVal,
-		VWAP:       vwapVal,
+		Ichimoku:   ichimokuVal,
+		SAR:        sarVal,
+		Supertrend: supertrendVal,
+		Keltner:    keltnerVal,
+		Donchian:   donchianVal,
+		Breakout:   breakout,
+		Candles:    candlePatterns,
+		Charts:     chartPatterns,
+		VolumeFlow: volumeFlow,
+		SMA20:      indicators.Nullable(sma20Val),
+		SMA50:      indicators.Nullable(sma50Val),
+		EMA12:      indicators.Nullable(ema12Val),
+		EMA26:      indicators.Nullable(ema26Val),
+		ATR:        indicators.Nullable(atrVal),
+		VWAP:       indicators.Nullable(vwapVal),
+		Limits:     limits,
+		Targets:    priceTargets(history, bbVal, sma20Val, stop, stopBasis, exchange, instrument),
+		Quality:    quality,
+		Flows:      flows,
+		Valuation:  valuation,
+		Indicators: requested,
 		Signal:     signal,
 		Confidence: confidence,
 		Score:      score,
@@ -196,7 +459,7 @@ func (s *TechnicalService) Analyze(ctx context.Context, symbol string) (*Technic
 	// Cache result
 	if s.redis != nil {
 		if data, err := json.Marshal(result); err == nil {
-			s.redis.Set(ctx, cacheKey, data, 5*time.Minute)
+			s.redis.Set(ctx, cacheKey, data, cacheTTL(time.Now()))
 		}
 	}
 
@@ -206,8 +469,238 @@ func (s *TechnicalService) Analyze(ctx context.Context, symbol string) (*Technic
 	return result, nil
 }
 
+// Instruments returns the instrument master used to resolve symbols
+func (s *TechnicalService) Instruments() *InstrumentService {
+	return s.instruments
+}
+
+// instrumentFor returns the type of a symbol and the exchange it is listed on
+// (or an index tracks), defaulting to a HOSE stock
+func (s *TechnicalService) instrumentFor(ctx context.Context, symbol string) (vnstock.InstrumentType, calendar.Exchange) {
+	if exchange, ok := vnstock.IndexExchange(symbol); ok {
+		return vnstock.InstrumentIndex, exchange
+	}
+
+	info, ok := s.instruments.Lookup(ctx, symbol)
+	if !ok || info.Type == "" {
+		info.Type = vnstock.InstrumentStock
+	}
+	if info.Exchange == "" {
+		return info.Type, calendar.HOSE
+	}
+
+	exchange, err := calendar.ParseExchange(info.Exchange)
+	if err != nil {
+		log.Printf("Warning: %s has unknown exchange %q", symbol, info.Exchange)
+		return info.Type, calendar.HOSE
+	}
+	return info.Type, exchange
+}
+
+// sessionLimits assesses the latest bar against the price limits of its
+// session. The reference price is the close of the previous trading day.
+// Weekly and monthly bars span several sessions and have no limits.
+func sessionLimits(history []vnstock.OHLCV, resolution vnstock.Resolution, exchange calendar.Exchange, instrument vnstock.InstrumentType) *rules.Assessment {
+	if resolution.CoarserThan(vnstock.Resolution1D) || len(history) < 2 {
+		return nil
+	}
+
+	latest := history[len(history)-1]
+	sessionStart := calendar.StartOfDay(latest.Date)
+	for i := len(history) - 2; i >= 0; i-- {
+		if history[i].Date.Before(sessionStart) {
+			limits := rules.Limits(exchange, instrument, history[i].Close)
+
+			// Intraday bars are judged on the session so far
+			session := latest
+			for j := i + 1; j < len(history)-1; j++ {
+				session.High = max(session.High, history[j].High)
+				session.Low = min(session.Low, history[j].Low)
+			}
+
+			a := limits.Assess(session)
+			return &a
+		}
+	}
+	return nil
+}
+
+// priceTargets suggests exits from the Bollinger Bands, the 20-bar high and
+// SMA20, like the Python agent's price targets, plus the trailing stop of the
+// trend overlays when there is one. Points-quoted instruments, such as indices
+// and futures, have no tick size.
+func priceTargets(history []vnstock.OHLCV, bb *indicators.BollingerBands, sma20, stop float64, stopBasis string, exchange calendar.Exchange, instrument vnstock.InstrumentType) *PriceTargets {
+	if bb == nil || !indicators.IsValid(sma20) {
+		return nil
+	}
+
+	floor := func(p float64) float64 { return rules.FloorToTick(exchange, instrument, p) }
+	ceil := func(p float64) float64 { return rules.CeilToTick(exchange, instrument, p) }
+	if !instrument.HasTickSize() {
+		floor, ceil = roundPoints, roundPoints
+	}
+
+	recentHigh := 0.0
+	for _, b := range lastBars(history, 20) {
+		recentHigh = max(recentHigh, b.High)
+	}
+
+	targets := &PriceTargets{
+		ShortTermTarget:  floor(bb.Upper),
+		ShortTermStop:    ceil(bb.Lower),
+		MediumTermTarget: floor(recentHigh),
+		MediumTermStop:   ceil(sma20),
+	}
+	if stop > 0 {
+		targets.TrailingStop, targets.TrailingStopBasis = ceil(stop), stopBasis
+	}
+	return targets
+}
+
+// roundPoints rounds an index level to two decimals
+func roundPoints(p float64) float64 {
+	return math.Round(p*100) / 100
+}
+
+// activity returns the volume of a stock bar, or the traded value of an index
+// bar when the provider reports it
+func activity(b vnstock.OHLCV, isIndex bool) int64 {
+	if isIndex && b.Value > 0 {
+		return int64(b.Value)
+	}
+	return b.Volume
+}
+
+// indicatorInputs returns the price arrays of bars, with the same activity
+// measure as the standard indicators
+func indicatorInputs(bars []vnstock.OHLCV, isIndex bool) indicators.Inputs {
+	in := indicators.Inputs{
+		Opens:   make([]float64, len(bars)),
+		Highs:   make([]float64, len(bars)),
+		Lows:    make([]float64, len(bars)),
+		Closes:  make([]float64, len(bars)),
+		Volumes: make([]int64, len(bars)),
+	}
+	for i, b := range bars {
+		in.Opens[i] = b.Open
+		in.Highs[i] = b.High
+		in.Lows[i] = b.Low
+		in.Closes[i] = b.Close
+		in.Volumes[i] = activity(b, isIndex)
+	}
+	return in
+}
+
+// cacheTTL returns how long a result computed at now stays fresh. Prices only
+// move while the market is open, so outside trading hours results are kept
+// until the next session starts.
+func cacheTTL(now time.Time) time.Duration {
+	if calendar.IsOpen(now) {
+		return openCacheTTL
+	}
+	ttl := calendar.NextOpen(now).Sub(now)
+	if ttl > maxClosedCacheTTL {
+		ttl = maxClosedCacheTTL
+	}
+	return ttl
+}
+
+// loadHistory returns the bars to analyze, preferring the local bar store.
+// The store is synced first; if the provider is unavailable the analysis
+// falls back to whatever is already stored. Weekly and monthly bars are
+// resampled from stored daily bars, after adjusting for corporate actions.
+func (s *TechnicalService) loadHistory(ctx context.Context, symbol string, opts AnalyzeOptions) ([]vnstock.OHLCV, error) {
+	resolution := opts.Resolution
+	bars := opts.bars()
+	// Longer lookbacks need proportionally more days
+	days := historyDays[resolution] * bars / analysisBars
+	if s.bars == nil {
+		history, err := s.marketData.GetHistoricalData(ctx, symbol, resolution, days)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch market data: %w", err)
+		}
+		return lastBars(history, bars), nil
+	}
+
+	base, limit := resolution, bars
+	if resolution.CoarserThan(vnstock.Resolution1D) {
+		base, limit = vnstock.Resolution1D, bars*baseBarsPerBar[resolution]
+	}
+
+	_, syncErr := s.bars.Sync(ctx, symbol, base, days)
+	if syncErr != nil {
+		log.Printf("Warning: bar sync failed for %s: %v", symbol, syncErr)
+	}
+
+	history, err := s.bars.LoadBars(ctx, symbol, base, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 && syncErr != nil {
+		return nil, syncErr
+	}
+
+	if opts.PriceMode == PriceModeAdjusted {
+		actions, err := s.loadCorporateActions(ctx, symbol)
+		if err != nil {
+			return nil, err
+		}
+		history = vnstock.AdjustPrices(history, actions)
+	}
+
+	if base != resolution {
+		if history, err = vnstock.Resample(history, resolution); err != nil {
+			return nil, err
+		}
+	}
+
+	return lastBars(history, bars), nil
+}
+
+// hasCorporateActions reports whether any corporate actions of a symbol are
+// stored
+func (s *TechnicalService) hasCorporateActions(ctx context.Context, symbol string) (bool, error) {
+	if s.db == nil {
+		return false, nil
+	}
+
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.CorporateAction{}).Where("symbol = ?", symbol).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to count %s corporate actions: %w", symbol, err)
+	}
+	return count > 0, nil
+}
+
+// loadCorporateActions returns the stored corporate actions of a symbol
+func (s *TechnicalService) loadCorporateActions(ctx context.Context, symbol string) ([]vnstock.CorporateAction, error) {
+	var rows []models.CorporateAction
+	if err := s.db.WithContext(ctx).Where("symbol = ?", symbol).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load %s corporate actions: %w", symbol, err)
+	}
+
+	actions := make([]vnstock.CorporateAction, len(rows))
+	for i, r := range rows {
+		actions[i] = vnstock.CorporateAction{
+			Type:       vnstock.CorporateActionType(r.Type),
+			ExDate:     r.ExDate,
+			Ratio:      r.Ratio,
+			CashAmount: r.CashAmount,
+			Price:      r.SubscriptionPrice,
+		}
+	}
+	return actions, nil
+}
+
+// lastBars returns at most n of the most recent bars
+func lastBars(bars []vnstock.OHLCV, n int) []vnstock.OHLCV {
+	if len(bars) > n {
+		return bars[len(bars)-n:]
+	}
+	return bars
+}
+
 // AnalyzeBatch performs analysis for multiple symbols concurrently
-func (s *TechnicalService) AnalyzeBatch(ctx context.Context, symbols []string) (map[string]*TechnicalResult, error) {
+func (s *TechnicalService) AnalyzeBatch(ctx context.Context, symbols []string, opts AnalyzeOptions) (map[string]*TechnicalResult, error) {
 	results := make(map[string]*TechnicalResult)
 	var mu sync.Mutex
 	var wg sync.WaitGroup
@@ -222,8 +715,9 @@ func (s *TechnicalService) AnalyzeBatch(ctx context.Context, symbols []string) (
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			result, err := s.Analyze(ctx, symbol)
+			result, err := s.Analyze(ctx, symbol, opts)
 			if err != nil {
+				log.Printf("Warning: analysis failed for %s: %v", symbol, err)
 				return // Skip failed symbols
 			}
 
@@ -237,34 +731,93 @@ func (s *TechnicalService) AnalyzeBatch(ctx context.Context, symbols []string) (
 	return results, nil
 }
 
+// signalInputs holds the values scored by generateSignals
+type signalInputs struct {
+	price, rsi float64
+	macd       *indicators.MACD
+	bb         *indicators.BollingerBands
+	stoch      *indicators.Stochastic
+	adx        *indicators.ADX
+	ichimoku   *indicators.Ichimoku
+	sar        *indicators.ParabolicSAR
+	supertrend *indicators.Supertrend
+	// breakout is a close through the recent channel, when there is one
+	breakout *Breakout
+	// candles are the candlestick patterns of the latest bar
+	candles []patterns.CandlePattern
+	// charts are the chart formations among the recent swings
+	charts                   []patterns.ChartPattern
+	sma20, sma50             float64
+	currentVolume, avgVolume float64
+	// volumeFlow holds the volume and money-flow indicators
+	volumeFlow *VolumeFlow
+	// limits compares the latest bar with its ceiling and floor, when known
+	limits *rules.Assessment
+	// flows summarizes recent foreign and proprietary trading, when known
+	flows *FlowSummary
+	// isIndex marks index series, whose volumes are traded values
+	isIndex bool
+	// flagged marks input with data quality issues, which caps the signal at BUY/SELL
+	flagged bool
+}
+
+// Factors measuring the same thing are scored as a group, capped so that each
+// further measure of it does not push the score across the signal thresholds
+const (
+	// maxTrendScore caps the trend followers: moving averages, the Ichimoku
+	// cloud, Supertrend, Parabolic SAR and VWMA
+	maxTrendScore = 2.0
+	// maxPatternScore caps the price action: breakouts, candlestick and
+	// chart patterns
+	maxPatternScore = 2.0
+	// maxFlowScore caps the volume: its ratio to the previous bar and the
+	// accumulation or distribution phase
+	maxFlowScore = 1.5
+)
+
 // generateSignals generates trading signals based on indicators
-func (s *TechnicalService) generateSignals(
-	price, rsi float64,
-	macd *indicators.MACD,
-	bb *indicators.BollingerBands,
-	stoch *indicators.Stochastic,
-	adx *indicators.ADX,
-	sma20, sma50 float64,
-	currentVolume, avgVolume float64,
-) (signal string, confidence, score float64, reasons []string) {
+func (s *TechnicalService) generateSignals(in signalInputs) (signal string, confidence, score float64, reasons []string) {
 	score = 0
 	reasons = []string{}
+	price, rsi := in.price, in.rsi
+	macd, bb, stoch, adx := in.macd, in.bb, in.stoch, in.adx
+	sma20, sma50 := in.sma20, in.sma50
+	// Correlated factors add up in groups, each capped at the end
+	var trend, pattern, flow float64
 
-	// RSI Analysis
-	if rsi > 0 {
-		if rsi < 30 {
-			score += 2
-			reasons = append(reasons, fmt.Sprintf("RSI quá bán (%.1f < 30) - Tín hiệu mua mạnh", rsi))
-		} else if rsi < 40 {
-			score += 1
-			reasons = append(reasons, fmt.Sprintf("RSI thấp (%.1f) - Xu hướng tăng có thể", rsi))
-		} else if rsi > 70 {
-			score -= 2
-			reasons = append(reasons, fmt.Sprintf("RSI quá mua (%.1f > 70) - Nguy cơ điều chỉnh", rsi))
-		} else if rsi > 60 {
-			score -= 1
-			reasons = append(reasons, fmt.Sprintf("RSI cao (%.1f) - Cần thận trọng", rsi))
+	// A close at the ceiling or floor caps the move, so oscillators read
+	// overbought or oversold because of the band rather than exhaustion
+	limitUp := in.limits != nil && in.limits.LimitUp
+	limitDown := in.limits != nil && in.limits.LimitDown
+	if limitUp {
+		score += 1.5
+		if in.limits.Locked {
+			reasons = append(reasons, fmt.Sprintf("Giá khóa trần (%.0f) - Trắng bên bán, lực cầu rất mạnh", in.limits.Ceiling))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("Giá đóng cửa tại trần (%.0f) - Lực cầu mạnh", in.limits.Ceiling))
 		}
+	} else if limitDown {
+		score -= 1.5
+		if in.limits.Locked {
+			reasons = append(reasons, fmt.Sprintf("Giá khóa sàn (%.0f) - Trắng bên mua, áp lực bán rất mạnh", in.limits.Floor))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("Giá đóng cửa tại sàn (%.0f) - Áp lực bán mạnh", in.limits.Floor))
+		}
+	}
+
+	// RSI Analysis. A warming-up RSI is NaN and fails every comparison.
+	if rsi < 30 && !limitDown {
+		score += 2
+		reasons = append(reasons, fmt.Sprintf("RSI quá bán (%.1f < 30) - Tín hiệu mua mạnh", rsi))
+	} else if rsi < 40 && !limitDown {
+		score += 1
+		reasons = append(reasons, fmt.Sprintf("RSI thấp (%.1f) - Xu hướng tăng có thể", rsi))
+	} else if rsi > 70 && !limitUp {
+		score -= 2
+		reasons = append(reasons, fmt.Sprintf("RSI quá mua (%.1f > 70) - Nguy cơ điều chỉnh", rsi))
+	} else if rsi > 60 && !limitUp {
+		score -= 1
+		reasons = append(reasons, fmt.Sprintf("RSI cao (%.1f) - Cần thận trọng", rsi))
 	}
 
 	// MACD Analysis
@@ -284,33 +837,30 @@ func (s *TechnicalService) generateSignals(
 		}
 	}
 
-	// Moving Average Analysis
-	if sma20 > 0 {
-		if price > sma20 {
-			score += 1
-			reasons = append(reasons, fmt.Sprintf("Giá trên SMA20 (%.0f) - Xu hướng tăng ngắn hạn", sma20))
-		} else {
-			score -= 1
-			reasons = append(reasons, fmt.Sprintf("Giá dưới SMA20 (%.0f) - Xu hướng giảm ngắn hạn", sma20))
-		}
+	// Moving Average Analysis. A warming-up average is NaN and fails both
+	// comparisons.
+	if price > sma20 {
+		trend += 1
+		reasons = append(reasons, fmt.Sprintf("Giá trên SMA20 (%.0f) - Xu hướng tăng ngắn hạn", sma20))
+	} else if price <= sma20 {
+		trend -= 1
+		reasons = append(reasons, fmt.Sprintf("Giá dưới SMA20 (%.0f) - Xu hướng giảm ngắn hạn", sma20))
 	}
 
-	if sma20 > 0 && sma50 > 0 {
-		if sma20 > sma50 {
-			score += 1
-			reasons = append(reasons, "SMA20 > SMA50 - Golden Cross, xu hướng tăng")
-		} else {
-			score -= 1
-			reasons = append(reasons, "SMA20 < SMA50 - Death Cross, xu hướng giảm")
-		}
+	if sma20 > sma50 {
+		trend += 1
+		reasons = append(reasons, "SMA20 > SMA50 - Golden Cross, xu hướng tăng")
+	} else if sma20 <= sma50 {
+		trend -= 1
+		reasons = append(reasons, "SMA20 < SMA50 - Death Cross, xu hướng giảm")
 	}
 
 	// Bollinger Bands Analysis
 	if bb != nil {
-		if price < bb.Lower {
+		if price < bb.Lower && !limitDown {
 			score += 1.5
 			reasons = append(reasons, fmt.Sprintf("Giá chạm dải BB dưới (%.0f) - Oversold", bb.Lower))
-		} else if price > bb.Upper {
+		} else if price > bb.Upper && !limitUp {
 			score -= 1.5
 			reasons = append(reasons, fmt.Sprintf("Giá chạm dải BB trên (%.0f) - Overbought", bb.Upper))
 		}
@@ -318,15 +868,142 @@ func (s *TechnicalService) generateSignals(
 
 	// Stochastic Analysis
 	if stoch != nil {
-		if stoch.K < 20 && stoch.D < 20 {
+		if stoch.K < 20 && stoch.D < 20 && !limitDown {
 			score += 1
 			reasons = append(reasons, fmt.Sprintf("Stochastic oversold (%.1f) - Tín hiệu mua", stoch.K))
-		} else if stoch.K > 80 && stoch.D > 80 {
+		} else if stoch.K > 80 && stoch.D > 80 && !limitUp {
 			score -= 1
 			reasons = append(reasons, fmt.Sprintf("Stochastic overbought (%.1f) - Tín hiệu bán", stoch.K))
 		}
 	}
 
+	// Ichimoku. The cloud under the price is the trend; a Tenkan/Kijun cross
+	// is the entry and a twist of the cloud ahead an early warning.
+	if ich := in.ichimoku; ich != nil {
+		if price > ich.CloudTop() {
+			trend += 1
+			reasons = append(reasons, fmt.Sprintf("Giá trên mây Ichimoku (%.0f) - Xu hướng tăng", ich.CloudTop()))
+		} else if price < ich.CloudBottom() {
+			trend -= 1
+			reasons = append(reasons, fmt.Sprintf("Giá dưới mây Ichimoku (%.0f) - Xu hướng giảm", ich.CloudBottom()))
+		} else {
+			reasons = append(reasons, "Giá trong mây Ichimoku - Xu hướng chưa rõ ràng")
+		}
+
+		if ich.TKCross > 0 {
+			trend += 1
+			reasons = append(reasons, "Tenkan cắt lên Kijun - Tín hiệu mua Ichimoku")
+		} else if ich.TKCross < 0 {
+			trend -= 1
+			reasons = append(reasons, "Tenkan cắt xuống Kijun - Tín hiệu bán Ichimoku")
+		}
+
+		if ich.CloudTwist > 0 {
+			trend += 0.5
+			reasons = append(reasons, "Mây Ichimoku đổi màu tăng - Senkou A cắt lên Senkou B")
+		} else if ich.CloudTwist < 0 {
+			trend -= 0.5
+			reasons = append(reasons, "Mây Ichimoku đổi màu giảm - Senkou A cắt xuống Senkou B")
+		}
+	}
+
+	// Trend overlays. Supertrend and the SAR give the direction ADX lacks;
+	// a flip at the latest bar is the signal, the trend itself a lean.
+	if st := in.supertrend; st != nil {
+		if st.Uptrend && st.Reversal {
+			trend += 1
+			reasons = append(reasons, fmt.Sprintf("Supertrend đảo chiều tăng (%.0f) - Tín hiệu mua", st.Value))
+		} else if !st.Uptrend && st.Reversal {
+			trend -= 1
+			reasons = append(reasons, fmt.Sprintf("Supertrend đảo chiều giảm (%.0f) - Tín hiệu bán", st.Value))
+		} else if st.Uptrend {
+			trend += 0.5
+			reasons = append(reasons, fmt.Sprintf("Supertrend tăng - Hỗ trợ tại %.0f", st.Value))
+		} else {
+			trend -= 0.5
+			reasons = append(reasons, fmt.Sprintf("Supertrend giảm - Kháng cự tại %.0f", st.Value))
+		}
+	}
+
+	if sar := in.sar; sar != nil && sar.Reversal {
+		if sar.Uptrend {
+			trend += 0.5
+			reasons = append(reasons, fmt.Sprintf("Parabolic SAR đảo chiều tăng (%.0f) - Xu hướng tăng mới", sar.SAR))
+		} else {
+			trend -= 0.5
+			reasons = append(reasons, fmt.Sprintf("Parabolic SAR đảo chiều giảm (%.0f) - Xu hướng giảm mới", sar.SAR))
+		}
+	}
+
+	if b := in.breakout; b != nil {
+		momentum := ""
+		if b.Keltner {
+			momentum = ", vượt kênh Keltner"
+		}
+		if b.Direction == BreakoutUp {
+			pattern += 1
+			if b.Keltner {
+				pattern += 0.5
+			}
+			reasons = append(reasons, fmt.Sprintf("Giá vượt đỉnh %d phiên (%.0f)%s - Breakout tăng", donchianBars, b.Level, momentum))
+		} else {
+			pattern -= 1
+			if b.Keltner {
+				pattern -= 0.5
+			}
+			reasons = append(reasons, fmt.Sprintf("Giá thủng đáy %d phiên (%.0f)%s - Breakdown", donchianBars, b.Level, momentum))
+		}
+	}
+
+	// Candlestick patterns, weighted by their strength in context. Patterns
+	// of one bar often overlap, so together they count at most
+	// maxCandleScore either way.
+	var candleScore float64
+	for _, p := range in.candles {
+		label := candleLabels[p.Name]
+		if label == "" {
+			label = p.Name
+		}
+		switch p.Bias {
+		case patterns.Bullish:
+			candleScore += p.Strength
+			reasons = append(reasons, fmt.Sprintf("Nến %s %s - Tín hiệu tăng (độ mạnh %.2f)", label, trendLabels[p.Trend], p.Strength))
+		case patterns.Bearish:
+			candleScore -= p.Strength
+			reasons = append(reasons, fmt.Sprintf("Nến %s %s - Tín hiệu giảm (độ mạnh %.2f)", label, trendLabels[p.Trend], p.Strength))
+		default:
+			reasons = append(reasons, fmt.Sprintf("Nến %s %s - Thị trường lưỡng lự", label, trendLabels[p.Trend]))
+		}
+	}
+	pattern += clamp(candleScore, maxCandleScore)
+
+	// Chart patterns: a completed formation counts more than one still
+	// forming, together at most maxChartScore either way
+	var chartScore float64
+	for _, p := range in.charts {
+		label := chartLabels[p.Type]
+		if label == "" {
+			label = p.Type
+		}
+		switch {
+		case p.Status == patterns.Completed && p.Bias == patterns.Bullish:
+			chartScore += 1.5
+			reasons = append(reasons, fmt.Sprintf("Mô hình %s hoàn thành, vượt %.0f - Mục tiêu %.0f", label, p.Breakout, p.Target))
+		case p.Status == patterns.Completed && p.Bias == patterns.Bearish:
+			chartScore -= 1.5
+			reasons = append(reasons, fmt.Sprintf("Mô hình %s hoàn thành, thủng %.0f - Mục tiêu %.0f", label, p.Breakout, p.Target))
+		case p.Bias == patterns.Bullish:
+			chartScore += 0.5
+			reasons = append(reasons, fmt.Sprintf("Mô hình %s đang hình thành - Điểm phá vỡ %.0f", label, p.Breakout))
+		case p.Bias == patterns.Bearish:
+			chartScore -= 0.5
+			reasons = append(reasons, fmt.Sprintf("Mô hình %s đang hình thành - Điểm phá vỡ %.0f", label, p.Breakout))
+		default:
+			reasons = append(reasons, fmt.Sprintf("Mô hình %s đang hình thành - Chờ phá vỡ", label))
+		}
+	}
+	pattern += clamp(chartScore, maxChartScore)
+
 	// ADX (Trend Strength)
 	if adx != nil {
 		if adx.ADX > 25 {
@@ -336,18 +1013,88 @@ func (s *TechnicalService) generateSignals(
 		}
 	}
 
-	// Volume Analysis
-	if avgVolume > 0 {
-		volumeRatio := currentVolume / avgVolume
+	// Volume Analysis. A locked bar trades thinly because one side of the
+	// book is empty, not because money is leaving the stock.
+	if in.avgVolume > 0 && (in.limits == nil || !in.limits.Locked) {
+		volumeRatio := in.currentVolume / in.avgVolume
+		label := "Khối lượng"
+		if in.isIndex {
+			label = "Giá trị giao dịch"
+		}
 		if volumeRatio > 1.5 {
-			reasons = append(reasons, fmt.Sprintf("Khối lượng tăng %.1fx - Dòng tiền mạnh", volumeRatio))
-			score += 0.5
+			reasons = append(reasons, fmt.Sprintf("%s tăng %.1fx - Dòng tiền mạnh", label, volumeRatio))
+			flow += 0.5
 		} else if volumeRatio < 0.5 {
-			reasons = append(reasons, fmt.Sprintf("Khối lượng thấp %.1fx - Dòng tiền yếu", volumeRatio))
+			reasons = append(reasons, fmt.Sprintf("%s thấp %.1fx - Dòng tiền yếu", label, volumeRatio))
+			flow -= 0.5
+		}
+	}
+
+	// Money flow. OBV and the A/D line rising while the price holds is
+	// accumulation (gom hàng); falling while it holds is distribution (xả
+	// hàng). Without a clear phase CMF shows the direction of the flow.
+	if vf := in.volumeFlow; vf != nil {
+		if vf.Phase == PhaseAccumulation {
+			flow += 1.5
+			reasons = append(reasons, fmt.Sprintf("OBV và A/D tăng %d phiên, CMF = %.2f trong khi giá chỉ %+.1f%% - Dấu hiệu gom hàng", volumeFlowBars, *vf.CMF, *vf.PriceChange*100))
+		} else if vf.Phase == PhaseDistribution {
+			flow -= 1.5
+			reasons = append(reasons, fmt.Sprintf("OBV và A/D giảm %d phiên, CMF = %.2f trong khi giá %+.1f%% - Dấu hiệu xả hàng", volumeFlowBars, *vf.CMF, *vf.PriceChange*100))
+		} else if vf.CMF != nil && *vf.CMF > 0.1 {
+			flow += 0.5
+			reasons = append(reasons, fmt.Sprintf("CMF = %.2f - Dòng tiền vào", *vf.CMF))
+		} else if vf.CMF != nil && *vf.CMF < -0.1 {
+			flow -= 0.5
+			reasons = append(reasons, fmt.Sprintf("CMF = %.2f - Dòng tiền ra", *vf.CMF))
+		}
+
+		if vf.MFI != nil && *vf.MFI < 20 && !limitDown {
+			score += 1
+			reasons = append(reasons, fmt.Sprintf("MFI quá bán (%.1f < 20) - Áp lực bán cạn kiệt", *vf.MFI))
+		} else if vf.MFI != nil && *vf.MFI > 80 && !limitUp {
+			score -= 1
+			reasons = append(reasons, fmt.Sprintf("MFI quá mua (%.1f > 80) - Nguy cơ chốt lời", *vf.MFI))
+		}
+
+		// A volume weighted average above the simple one means the volume
+		// traded at the higher prices
+		if vwma := vf.VWMA20; vwma != nil && price > *vwma && *vwma > sma20 {
+			trend += 0.5
+			reasons = append(reasons, fmt.Sprintf("Giá trên VWMA20 (%.0f) > SMA20 - Đà tăng được khối lượng ủng hộ", *vwma))
+		} else if vwma != nil && price < *vwma && *vwma < sma20 {
+			trend -= 0.5
+			reasons = append(reasons, fmt.Sprintf("Giá dưới VWMA20 (%.0f) < SMA20 - Đà giảm kèm khối lượng lớn", *vwma))
+		}
+	}
+
+	// Investor flows. Foreign funds move on research and hold for months, so
+	// sustained net buying or selling by them outweighs that of the desks.
+	if f := in.flows; f != nil {
+		if f.ForeignStreak >= flowStreak {
+			score += 1
+			reasons = append(reasons, fmt.Sprintf("Khối ngoại mua ròng %d phiên liên tiếp - Dòng tiền ngoại tích cực", f.ForeignStreak))
+		} else if f.ForeignStreak <= -flowStreak {
+			score -= 1
+			reasons = append(reasons, fmt.Sprintf("Khối ngoại bán ròng %d phiên liên tiếp - Áp lực rút vốn ngoại", -f.ForeignStreak))
+		}
+
+		if f.ProprietaryStreak >= flowStreak {
+			score += 0.5
+			reasons = append(reasons, fmt.Sprintf("Tự doanh mua ròng %d phiên liên tiếp", f.ProprietaryStreak))
+		} else if f.ProprietaryStreak <= -flowStreak {
 			score -= 0.5
+			reasons = append(reasons, fmt.Sprintf("Tự doanh bán ròng %d phiên liên tiếp", -f.ProprietaryStreak))
+		}
+
+		if f.RoomLimited {
+			reasons = append(reasons, fmt.Sprintf("Room ngoại sắp cạn (%d cp) - Lực mua khối ngoại bị hạn chế", f.ForeignRoom))
 		}
 	}
 
+	score += clamp(trend, maxTrendScore) + clamp(pattern, maxPatternScore) + clamp(flow, maxFlowScore)
+	// Candlestick strengths are fractional; keep the score to cents
+	score = math.Round(score*100) / 100
+
 	// Generate final recommendation
 	if score >= 4 {
 		signal = "STRONG_BUY"
@@ -366,6 +1113,17 @@ func (s *TechnicalService) generateSignals(
 		confidence = min(95, 70+abs(score+4)*5)
 	}
 
+	// Strong signals need clean input
+	if in.flagged && (signal == "STRONG_BUY" || signal == "STRONG_SELL") {
+		if signal == "STRONG_BUY" {
+			signal = "BUY"
+		} else {
+			signal = "SELL"
+		}
+		confidence = min(confidence, 85)
+		reasons = append(reasons, "Dữ liệu giá có vấn đề chất lượng - Không đưa ra tín hiệu mạnh")
+	}
+
 	return signal, confidence, score, reasons
 }
 
@@ -376,18 +1134,20 @@ func (s *TechnicalService) storeResult(ctx context.Context, result *TechnicalRes
 
 	analysis := &models.TechnicalAnalysis{
 		Symbol:     result.Symbol,
+		Resolution: string(result.Resolution),
+		PriceMode:  result.PriceMode,
 		Timestamp:  result.Timestamp,
 		OpenPrice:  result.Price.Open,
 		HighPrice:  result.Price.High,
 		LowPrice:   result.Price.Low,
 		ClosePrice: result.Price.Close,
 		Volume:     result.Price.Volume,
-		RSI14:      &result.RSI,
-		SMA20:      &result.SMA20,
-		SMA50:      &result.SMA50,
-		EMA12:      &result.EMA12,
-		EMA26:      &result.EMA26,
-		ATR:        &result.ATR,
+		RSI14:      result.RSI,
+		SMA20:      result.SMA20,
+		SMA50:      result.SMA50,
+		EMA12:      result.EMA12,
+		EMA26:      result.EMA26,
+		ATR:        result.ATR,
 		Signal:     result.Signal,
 		Confidence: &result.Confidence,
 		Score:      &result.Score,
@@ -417,6 +1177,11 @@ func (s *TechnicalService) storeResult(ctx context.Context, result *TechnicalRes
 	s.db.Create(analysis)
 }
 
+// clamp limits x to [-limit, limit]
+func clamp(x, limit float64) float64 {
+	return math.Max(-limit, math.Min(limit, x))
+}
+
 func abs(x float64) float64 {
 	if x < 0 {
 		return -x
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"vnstock-hybrid/internal/indicators"
+	"vnstock-hybrid/internal/patterns"
+	"vnstock-hybrid/pkg/calendar"
+	"vnstock-hybrid/pkg/synthetic"
+)
+
+// Integration tests run the full analysis over seeded synthetic data, so the
+// expected signals are exact
+var syntheticEnd = time.Date(2025, 6, 30, 0, 0, 0, 0, calendar.Location)
+
+func newSyntheticService() *TechnicalService {
+	return NewTechnicalService(nil, nil, synthetic.NewProvider(synthetic.Options{Seed: 42, End: syntheticEnd}))
+}
+
+func TestAnalyzeSyntheticSignals(t *testing.T) {
+	svc := newSyntheticService()
+
+	tests := []struct {
+		symbol     string
+		signal     string
+		score      float64
+		confidence float64
+		close      float64
+	}{
+		// Capped as groups, the trend, pattern and volume factors leave the
+		// signals as they were without them, except where noted
+		{"VIC", "STRONG_BUY", 4, 70, 5170},
+		// STRONG_BUY rather than BUY from the foreign buying streak on
+		{"VNM", "STRONG_BUY", 5.5, 77.5, 36350},
+		{"HPG", "HOLD", 0.5, 52.5, 113600},
+		{"FPT", "HOLD", -1.49, 57.45, 22300},
+		// A completed double top and distribution on top of the MACD sell,
+		// while the trend factors cancel out
+		{"SSI", "STRONG_SELL", -5.5, 77.5, 16600},
+		{"VNINDEX", "HOLD", 1, 55, 915.61},
+	}
+
+	for _, tt := range tests {
+		result, err := svc.Analyze(context.Background(), tt.symbol, AnalyzeOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.symbol, err)
+		}
+		if result.Signal != tt.signal || result.Score != tt.score || result.Confidence != tt.confidence {
+			t.Errorf("%s: got %s score %v confidence %v, want %s score %v confidence %v (reasons %v)",
+				tt.symbol, result.Signal, result.Score, result.Confidence, tt.signal, tt.score, tt.confidence, result.Reasons)
+		}
+		if result.Price.Close != tt.close {
+			t.Errorf("%s: got close %v, want %v", tt.symbol, result.Price.Close, tt.close)
+		}
+		if (result.Flows == nil) != result.IsIndex || (result.Valuation == nil) != result.IsIndex {
+			t.Errorf("%s: got flows %+v and valuation %+v", tt.symbol, result.Flows, result.Valuation)
+		}
+		if result.Quality.Flagged {
+			t.Errorf("%s: synthetic data flagged: %+v", tt.symbol, result.Quality.Issues)
+		}
+	}
+}
+
+func TestAnalyzeWithoutDatabaseReportsRawPrices(t *testing.T) {
+	svc := newSyntheticService()
+
+	result, err := svc.Analyze(context.Background(), "VNM", AnalyzeOptions{PriceMode: PriceModeAdjusted})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PriceMode != PriceModeRaw {
+		t.Errorf("got price mode %s without a database, want %s", result.PriceMode, PriceModeRaw)
+	}
+}
+
+func TestAnalyzeRequestedIndicators(t *testing.T) {
+	svc := newSyntheticService()
+
+	specs, err := indicators.ParseSpecs("rsi(7),sma(200),bb(20,2.5)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := svc.Analyze(context.Background(), "FPT", AnalyzeOptions{Indicators: specs})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The standard set is unaffected by the longer history
+	if result.Signal != "HOLD" || result.Score != -1.49 {
+		t.Errorf("got %s score %v, want HOLD score -1.49", result.Signal, result.Score)
+	}
+	for _, key := range []string{"rsi(7)", "sma(200)", "bb(20,2.5)"} {
+		values, ok := result.Indicators[key]
+		if !ok {
+			t.Fatalf("missing %s in %v", key, result.Indicators)
+		}
+		for output, v := range values {
+			if v == nil {
+				t.Errorf("%s %s is null", key, output)
+			}
+		}
+	}
+}
+
+func TestCandleLabels(t *testing.T) {
+	for _, name := range patterns.CandleNames() {
+		if candleLabels[name] == "" {
+			t.Errorf("candlestick pattern %s has no label", name)
+		}
+	}
+	for _, typ := range patterns.ChartTypes() {
+		if chartLabels[typ] == "" {
+			t.Errorf("chart pattern %s has no label", typ)
+		}
+	}
+}
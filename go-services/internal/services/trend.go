@@ -0,0 +1,74 @@
+package services
+
+import (
+	"vnstock-hybrid/internal/indicators"
+)
+
+// donchianBars is the channel a close must break out of
+const donchianBars = 20
+
+// Breakout directions
+const (
+	BreakoutUp   = "up"
+	BreakoutDown = "down"
+)
+
+// Breakout is a close through the Donchian channel of the bars before it
+type Breakout struct {
+	Direction string `json:"direction"`
+	// Level is the channel edge broken: the highest high or lowest low of
+	// the previous donchianBars bars
+	Level float64 `json:"level"`
+	// Keltner marks a close also beyond the Keltner channel, a breakout
+	// with momentum rather than a drift to a new extreme
+	Keltner bool `json:"keltner"`
+}
+
+// detectBreakout returns the breakout of the latest close, if any.
+// donchian must be computed over the same bars as closes.
+func detectBreakout(closes []float64, donchian *indicators.DonchianSeries, keltner *indicators.KeltnerChannels) *Breakout {
+	n := len(closes)
+	if donchian == nil || !donchian.Upper.Valid(n-2) {
+		return nil
+	}
+
+	price := closes[n-1]
+	var b *Breakout
+	if level := donchian.Upper[n-2]; price > level {
+		b = &Breakout{Direction: BreakoutUp, Level: level, Keltner: keltner != nil && price > keltner.Upper}
+	} else if level := donchian.Lower[n-2]; price < level {
+		b = &Breakout{Direction: BreakoutDown, Level: level, Keltner: keltner != nil && price < keltner.Lower}
+	}
+	return b
+}
+
+// Trailing stop bases
+const (
+	StopSupertrend   = "supertrend"
+	StopParabolicSAR = "parabolic_sar"
+	StopDonchian     = "donchian"
+)
+
+// trailingStop returns the tightest stop below price for a long position
+// among the Supertrend and Parabolic SAR of an uptrend and the lower
+// Donchian channel, with its basis. It returns 0 when none is below price.
+func trailingStop(price float64, st *indicators.Supertrend, sar *indicators.ParabolicSAR, donchian *indicators.DonchianChannels) (float64, string) {
+	var stop float64
+	var basis string
+	consider := func(level float64, name string) {
+		if indicators.IsValid(level) && level < price && level > stop {
+			stop, basis = level, name
+		}
+	}
+
+	if st != nil && st.Uptrend {
+		consider(st.Value, StopSupertrend)
+	}
+	if sar != nil && sar.Uptrend {
+		consider(sar.SAR, StopParabolicSAR)
+	}
+	if donchian != nil {
+		consider(donchian.Lower, StopDonchian)
+	}
+	return stop, basis
+}
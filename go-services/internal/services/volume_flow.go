@@ -0,0 +1,84 @@
+package services
+
+import (
+	"vnstock-hybrid/internal/indicators"
+)
+
+// volumeFlowBars is the window over which accumulation and distribution are
+// detected
+const volumeFlowBars = 20
+
+// maxPhaseMove bounds the price move of an accumulation or distribution
+// phase: money flowing in while the price breaks out is a rally, not quiet
+// buying
+const maxPhaseMove = 0.03
+
+// minPhaseCMF is the Chaikin Money Flow that confirms a phase
+const minPhaseCMF = 0.05
+
+// Volume flow phases
+const (
+	// PhaseAccumulation is money flowing in while the price holds or falls,
+	// "gom hàng"
+	PhaseAccumulation = "accumulation"
+	// PhaseDistribution is money flowing out while the price holds or rises,
+	// "xả hàng"
+	PhaseDistribution = "distribution"
+)
+
+// VolumeFlow condenses the volume and money-flow indicators of the latest bar
+type VolumeFlow struct {
+	OBV    *float64 `json:"obv"`
+	MFI    *float64 `json:"mfi"`
+	AD     *float64 `json:"ad"`
+	CMF    *float64 `json:"cmf"`
+	VWMA20 *float64 `json:"vwma_20"`
+	// OBVChange and ADChange are the changes of OBV and the A/D line over
+	// the last volumeFlowBars bars, and PriceChange that of the close as a
+	// fraction
+	OBVChange   *float64 `json:"obv_change"`
+	ADChange    *float64 `json:"ad_change"`
+	PriceChange *float64 `json:"price_change"`
+	// Phase is accumulation, distribution or empty when neither is clear
+	Phase string `json:"phase,omitempty"`
+}
+
+// summarizeVolumeFlow computes the volume and money-flow indicators of bars
+// and detects accumulation and distribution: OBV and the A/D line moving
+// together, confirmed by CMF, against a price that does not follow
+func summarizeVolumeFlow(in indicators.Inputs) *VolumeFlow {
+	n := len(in.Closes)
+	if n == 0 {
+		return nil
+	}
+
+	obv := indicators.OBV(in.Closes, in.Volumes)
+	ad := indicators.AccumulationDistribution(in.Highs, in.Lows, in.Closes, in.Volumes)
+	cmf := indicators.CMFLatest(in.Highs, in.Lows, in.Closes, in.Volumes, 20)
+	flow := &VolumeFlow{
+		OBV:    indicators.Nullable(obv.Last()),
+		MFI:    indicators.Nullable(indicators.MFILatest(in.Highs, in.Lows, in.Closes, in.Volumes, 14)),
+		AD:     indicators.Nullable(ad.Last()),
+		CMF:    indicators.Nullable(cmf),
+		VWMA20: indicators.Nullable(indicators.VWMALatest(in.Closes, in.Volumes, 20)),
+	}
+	if n <= volumeFlowBars || in.Closes[n-1-volumeFlowBars] == 0 {
+		return flow
+	}
+
+	from := n - 1 - volumeFlowBars
+	obvChange := obv[n-1] - obv[from]
+	adChange := ad[n-1] - ad[from]
+	priceChange := in.Closes[n-1]/in.Closes[from] - 1
+	flow.OBVChange = &obvChange
+	flow.ADChange = &adChange
+	flow.PriceChange = &priceChange
+
+	switch {
+	case obvChange > 0 && adChange > 0 && cmf >= minPhaseCMF && priceChange <= maxPhaseMove:
+		flow.Phase = PhaseAccumulation
+	case obvChange < 0 && adChange < 0 && cmf <= -minPhaseCMF && priceChange >= -maxPhaseMove:
+		flow.Phase = PhaseDistribution
+	}
+	return flow
+}
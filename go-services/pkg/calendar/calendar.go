@@ -0,0 +1,372 @@
+// Package calendar knows the trading days and sessions of the Vietnamese
+// exchanges (HOSE, HNX and UPCOM).
+package calendar
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Location is the Asia/Ho_Chi_Minh time zone used by all VN exchanges
+var Location = loadLocation()
+
+func loadLocation() *time.Location {
+	if loc, err := time.LoadLocation("Asia/Ho_Chi_Minh"); err == nil {
+		return loc
+	}
+	// Vietnam has no daylight saving time, so a fixed zone is equivalent
+	return time.FixedZone("ICT", 7*60*60)
+}
+
+// Exchange identifies a Vietnamese exchange
+type Exchange string
+
+// Supported exchanges
+const (
+	HOSE  Exchange = "HOSE"
+	HNX   Exchange = "HNX"
+	UPCOM Exchange = "UPCOM"
+)
+
+// ParseExchange parses an exchange code, accepting HSX as an alias for HOSE
+func ParseExchange(s string) (Exchange, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "HOSE", "HSX":
+		return HOSE, nil
+	case "HNX":
+		return HNX, nil
+	case "UPCOM":
+		return UPCOM, nil
+	default:
+		return "", fmt.Errorf("unknown exchange %q", s)
+	}
+}
+
+// Session is a trading phase, with Start and End in minutes after midnight
+type Session struct {
+	Name  string `json:"name"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// exchangeSessions lists the trading phases of each exchange in order
+var exchangeSessions = map[Exchange][]Session{
+	HOSE: {
+		{Name: "ATO", Start: 9 * 60, End: 9*60 + 15},
+		{Name: "continuous_morning", Start: 9*60 + 15, End: 11*60 + 30},
+		{Name: "continuous_afternoon", Start: 13 * 60, End: 14*60 + 30},
+		{Name: "ATC", Start: 14*60 + 30, End: 14*60 + 45},
+	},
+	HNX: {
+		{Name: "continuous_morning", Start: 9 * 60, End: 11*60 + 30},
+		{Name: "continuous_afternoon", Start: 13 * 60, End: 14*60 + 30},
+		{Name: "ATC", Start: 14*60 + 30, End: 14*60 + 45},
+		{Name: "PLO", Start: 14*60 + 45, End: 15 * 60},
+	},
+	UPCOM: {
+		{Name: "continuous_morning", Start: 9 * 60, End: 11*60 + 30},
+		{Name: "continuous_afternoon", Start: 13 * 60, End: 15 * 60},
+	},
+}
+
+// barSessions are the spans intraday bars are aligned to, from BarSessions
+var barSessions = mergeBarSessions()
+
+// BarSessions returns the spans intraday bars of every exchange are aligned to
+// and never cross: the morning and the afternoon, split by the lunch break,
+// and the close, from the HOSE closing auction to the end of the last phase
+// of any exchange. The opening auction belongs to the morning.
+func BarSessions() []Session {
+	return barSessions
+}
+
+func mergeBarSessions() []Session {
+	end := 0
+	for _, sessions := range exchangeSessions {
+		end = max(end, sessions[len(sessions)-1].End)
+	}
+
+	var spans []Session
+	for _, s := range exchangeSessions[HOSE] {
+		n := len(spans)
+		switch {
+		case s.Name == "ATC":
+			spans = append(spans, Session{Name: s.Name, Start: s.Start, End: end})
+		case n > 0 && spans[n-1].End == s.Start:
+			spans[n-1].Name, spans[n-1].End = s.Name, s.End
+		default:
+			spans = append(spans, s)
+		}
+	}
+	return spans
+}
+
+// Holiday is a weekday on which the exchanges are closed
+type Holiday struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+//go:embed holidays.json
+var defaultHolidays []byte
+
+var (
+	holidaysMu sync.RWMutex
+	holidays   map[string]string
+	// lastYear is the last year the holiday list covers
+	lastYear int
+	// warnedYears are the years past lastYear already warned about
+	warnedYears = map[int]bool{}
+)
+
+func init() {
+	parsed, err := parseHolidays(defaultHolidays)
+	if err != nil {
+		panic(fmt.Sprintf("calendar: invalid embedded holidays.json: %v", err))
+	}
+	holidays, lastYear = parsed, holidaysEnd(parsed)
+}
+
+// LoadHolidays replaces the holiday list with the one in the JSON file at path.
+// The file uses the same format as the embedded holidays.json.
+func LoadHolidays(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read holidays file: %w", err)
+	}
+
+	parsed, err := parseHolidays(raw)
+	if err != nil {
+		return err
+	}
+
+	holidaysMu.Lock()
+	holidays, lastYear = parsed, holidaysEnd(parsed)
+	warnedYears = map[int]bool{}
+	holidaysMu.Unlock()
+	return nil
+}
+
+func parseHolidays(raw []byte) (map[string]string, error) {
+	var file struct {
+		Holidays []Holiday `json:"holidays"`
+	}
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode holidays: %w", err)
+	}
+
+	parsed := make(map[string]string, len(file.Holidays))
+	for _, h := range file.Holidays {
+		if _, err := time.Parse("2006-01-02", h.Date); err != nil {
+			return nil, fmt.Errorf("invalid holiday date %q", h.Date)
+		}
+		parsed[h.Date] = h.Name
+	}
+	return parsed, nil
+}
+
+// holidaysEnd returns the last year with a holiday in the list
+func holidaysEnd(parsed map[string]string) int {
+	end := 0
+	for date := range parsed {
+		d, _ := time.Parse("2006-01-02", date)
+		end = max(end, d.Year())
+	}
+	return end
+}
+
+// Calendar answers trading day and session questions for one exchange
+type Calendar struct {
+	exchange Exchange
+	sessions []Session
+}
+
+// For returns the calendar of an exchange
+func For(exchange Exchange) *Calendar {
+	sessions, ok := exchangeSessions[exchange]
+	if !ok {
+		exchange, sessions = HOSE, exchangeSessions[HOSE]
+	}
+	return &Calendar{exchange: exchange, sessions: sessions}
+}
+
+// Default is the HOSE calendar, used by the package-level helpers
+var Default = For(HOSE)
+
+// Exchange returns the exchange of the calendar
+func (c *Calendar) Exchange() Exchange {
+	return c.exchange
+}
+
+// Sessions returns the trading phases of a trading day
+func (c *Calendar) Sessions() []Session {
+	return c.sessions
+}
+
+// Holiday returns the name of the holiday falling on t's date, if any. Dates
+// after the last year of the holiday list log a warning, once a year, since
+// their holidays are unknown and treated as trading days.
+func (c *Calendar) Holiday(t time.Time) (string, bool) {
+	local := t.In(Location)
+	holidaysMu.RLock()
+	name, ok := holidays[local.Format("2006-01-02")]
+	covered := local.Year() <= lastYear
+	holidaysMu.RUnlock()
+
+	if !covered {
+		warnUncovered(local.Year())
+	}
+	return name, ok
+}
+
+// warnUncovered logs that the holidays of year are missing from the list
+func warnUncovered(year int) {
+	holidaysMu.Lock()
+	defer holidaysMu.Unlock()
+
+	if warnedYears[year] {
+		return
+	}
+	warnedYears[year] = true
+	log.Printf("Warning: the holiday list ends in %d, so %d is treated as having no holidays; update holidays.json", lastYear, year)
+}
+
+// IsTradingDay reports whether the exchange trades on t's date
+func (c *Calendar) IsTradingDay(t time.Time) bool {
+	switch t.In(Location).Weekday() {
+	case time.Saturday, time.Sunday:
+		return false
+	}
+	_, holiday := c.Holiday(t)
+	return !holiday
+}
+
+// SessionAt returns the trading phase in progress at t
+func (c *Calendar) SessionAt(t time.Time) (Session, bool) {
+	if !c.IsTradingDay(t) {
+		return Session{}, false
+	}
+
+	local := t.In(Location)
+	minute := local.Hour()*60 + local.Minute()
+	for _, s := range c.sessions {
+		if minute >= s.Start && minute < s.End {
+			return s, true
+		}
+	}
+	return Session{}, false
+}
+
+// IsOpen reports whether the exchange is trading at t
+func (c *Calendar) IsOpen(t time.Time) bool {
+	_, open := c.SessionAt(t)
+	return open
+}
+
+// NextOpen returns t if the exchange is open, otherwise the start of the next trading phase
+func (c *Calendar) NextOpen(t time.Time) time.Time {
+	if c.IsOpen(t) {
+		return t
+	}
+
+	day := StartOfDay(t)
+	for i := 0; i < 30; i++ {
+		if c.IsTradingDay(day) {
+			for _, s := range c.sessions {
+				start := day.Add(time.Duration(s.Start) * time.Minute)
+				if start.After(t) {
+					return start
+				}
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// Close returns the end of the last trading phase on t's date
+func (c *Calendar) Close(t time.Time) time.Time {
+	return StartOfDay(t).Add(time.Duration(c.sessions[len(c.sessions)-1].End) * time.Minute)
+}
+
+// PreviousTradingDay returns the start of the last trading day before t's date
+func (c *Calendar) PreviousTradingDay(t time.Time) time.Time {
+	return c.AddTradingDays(t, -1)
+}
+
+// NextTradingDay returns the start of the first trading day after t's date
+func (c *Calendar) NextTradingDay(t time.Time) time.Time {
+	return c.AddTradingDays(t, 1)
+}
+
+// AddTradingDays returns the start of the trading day n trading days after
+// (or, for negative n, before) t's date. For n == 0 it returns the start of
+// t's date if that is a trading day, otherwise the previous trading day.
+func (c *Calendar) AddTradingDays(t time.Time, n int) time.Time {
+	day := StartOfDay(t)
+	if n == 0 {
+		for !c.IsTradingDay(day) {
+			day = day.AddDate(0, 0, -1)
+		}
+		return day
+	}
+
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		day = day.AddDate(0, 0, step)
+		if c.IsTradingDay(day) {
+			n--
+		}
+	}
+	return day
+}
+
+// TradingDaysBetween counts the trading days after from's date up to and
+// including to's date. Consecutive trading days are 1 apart.
+func (c *Calendar) TradingDaysBetween(from, to time.Time) int {
+	start, end := StartOfDay(from), StartOfDay(to)
+
+	count := 0
+	for day := start.AddDate(0, 0, 1); !day.After(end); day = day.AddDate(0, 0, 1) {
+		if c.IsTradingDay(day) {
+			count++
+		}
+	}
+	return count
+}
+
+// StartOfDay returns midnight of t's date in market time
+func StartOfDay(t time.Time) time.Time {
+	local := t.In(Location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, Location)
+}
+
+// IsTradingDay reports whether HOSE trades on t's date
+func IsTradingDay(t time.Time) bool { return Default.IsTradingDay(t) }
+
+// IsOpen reports whether HOSE is trading at t
+func IsOpen(t time.Time) bool { return Default.IsOpen(t) }
+
+// NextOpen returns t if HOSE is open, otherwise the start of its next trading phase
+func NextOpen(t time.Time) time.Time { return Default.NextOpen(t) }
+
+// PreviousTradingDay returns the start of the last HOSE trading day before t's date
+func PreviousTradingDay(t time.Time) time.Time { return Default.PreviousTradingDay(t) }
+
+// NextTradingDay returns the start of the first HOSE trading day after t's date
+func NextTradingDay(t time.Time) time.Time { return Default.NextTradingDay(t) }
+
+// AddTradingDays moves t's date by n HOSE trading days
+func AddTradingDays(t time.Time, n int) time.Time { return Default.AddTradingDays(t, n) }
+
+// TradingDaysBetween counts the HOSE trading days in (from, to]
+func TradingDaysBetween(from, to time.Time) int { return Default.TradingDaysBetween(from, to) }
@@ -0,0 +1,86 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func at(date string, hour, minute int) time.Time {
+	d, _ := time.ParseInLocation("2006-01-02", date, Location)
+	return d.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func TestIsTradingDay(t *testing.T) {
+	cases := map[string]bool{
+		"2025-01-24": true,  // Friday before Tet
+		"2025-01-25": false, // Saturday
+		"2025-01-29": false, // Tet
+		"2025-02-03": true,  // first session after Tet
+		"2025-09-02": false, // National Day
+		"2027-02-09": false, // Tet 2027
+		"2027-02-11": true,
+	}
+	for date, want := range cases {
+		if got := IsTradingDay(at(date, 10, 0)); got != want {
+			t.Errorf("IsTradingDay(%s) = %v, want %v", date, got, want)
+		}
+	}
+}
+
+func TestIsOpen(t *testing.T) {
+	cases := []struct {
+		t    time.Time
+		want bool
+	}{
+		{at("2025-02-03", 8, 59), false},
+		{at("2025-02-03", 9, 0), true},
+		{at("2025-02-03", 12, 0), false}, // lunch break
+		{at("2025-02-03", 14, 40), true}, // ATC
+		{at("2025-02-03", 14, 45), false},
+		{at("2025-01-29", 10, 0), false}, // Tet
+	}
+	for _, c := range cases {
+		if got := IsOpen(c.t); got != c.want {
+			t.Errorf("IsOpen(%s) = %v, want %v", c.t, got, c.want)
+		}
+	}
+
+	if !For(HNX).IsOpen(at("2025-02-03", 14, 50)) {
+		t.Error("HNX should be open during the post-close session")
+	}
+}
+
+func TestTradingDayArithmetic(t *testing.T) {
+	if got := PreviousTradingDay(at("2025-02-03", 10, 0)); !got.Equal(at("2025-01-24", 0, 0)) {
+		t.Errorf("PreviousTradingDay = %s, want 2025-01-24", got)
+	}
+	if got := NextTradingDay(at("2025-01-24", 10, 0)); !got.Equal(at("2025-02-03", 0, 0)) {
+		t.Errorf("NextTradingDay = %s, want 2025-02-03", got)
+	}
+	if got := TradingDaysBetween(at("2025-01-24", 0, 0), at("2025-02-03", 0, 0)); got != 1 {
+		t.Errorf("TradingDaysBetween across Tet = %d, want 1", got)
+	}
+	if got := AddTradingDays(at("2025-02-03", 0, 0), -5); !got.Equal(at("2025-01-20", 0, 0)) {
+		t.Errorf("AddTradingDays(-5) = %s, want 2025-01-20", got)
+	}
+	if got := NextOpen(at("2025-01-24", 15, 0)); !got.Equal(at("2025-02-03", 9, 0)) {
+		t.Errorf("NextOpen = %s, want 2025-02-03 09:00", got)
+	}
+}
+
+func TestBarSessions(t *testing.T) {
+	want := []Session{
+		{Name: "continuous_morning", Start: 9 * 60, End: 11*60 + 30},
+		{Name: "continuous_afternoon", Start: 13 * 60, End: 14*60 + 30},
+		{Name: "ATC", Start: 14*60 + 30, End: 15 * 60},
+	}
+	got := BarSessions()
+	if len(got) != len(want) {
+		t.Fatalf("BarSessions() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("span %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package realtime
+
+import (
+	"sync"
+	"time"
+
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+// BarUpdate is published whenever a trade changes a live bar
+type BarUpdate struct {
+	Symbol     string             `json:"symbol"`
+	Resolution vnstock.Resolution `json:"resolution"`
+	Bar        vnstock.OHLCV      `json:"bar"`
+	// Closed reports that the bar is final
+	Closed bool `json:"closed"`
+}
+
+// seriesKey identifies the bars of one symbol at one resolution
+type seriesKey struct {
+	symbol     string
+	resolution vnstock.Resolution
+}
+
+// liveSeries is the open bar and the most recent closed bars of a series
+type liveSeries struct {
+	current *vnstock.OHLCV
+	closed  []vnstock.OHLCV
+}
+
+// BarBuilder aggregates trades into bars of several resolutions. A bar
+// closes when a trade falls into a later bucket or when CloseBefore passes
+// its bucket.
+type BarBuilder struct {
+	resolutions []vnstock.Resolution
+	history     int
+
+	mu     sync.Mutex
+	series map[seriesKey]*liveSeries
+}
+
+// NewBarBuilder builds bars of the given resolutions, keeping up to history
+// closed bars per symbol and resolution
+func NewBarBuilder(resolutions []vnstock.Resolution, history int) *BarBuilder {
+	return &BarBuilder{
+		resolutions: resolutions,
+		history:     history,
+		series:      make(map[seriesKey]*liveSeries),
+	}
+}
+
+// Resolutions returns the resolutions built
+func (b *BarBuilder) Resolutions() []vnstock.Resolution {
+	return b.resolutions
+}
+
+// Add applies a trade to the bars of its symbol and returns the resulting
+// updates. Trades older than the open bar of a resolution are ignored there.
+func (b *BarBuilder) Add(t Trade) []BarUpdate {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var updates []BarUpdate
+	for _, res := range b.resolutions {
+		key := seriesKey{t.Symbol, res}
+		s, ok := b.series[key]
+		if !ok {
+			s = &liveSeries{}
+			b.series[key] = s
+		}
+
+		start := vnstock.BucketStart(t.Time, res)
+		if s.current != nil && start.After(s.current.Date) {
+			updates = append(updates, b.close(key, s))
+		}
+		if s.current == nil {
+			if n := len(s.closed); n > 0 && !start.After(s.closed[n-1].Date) {
+				continue
+			}
+			s.current = &vnstock.OHLCV{Date: start, Open: t.Price, High: t.Price, Low: t.Price}
+		}
+		if start.Before(s.current.Date) {
+			continue
+		}
+
+		bar := s.current
+		bar.High = max(bar.High, t.Price)
+		bar.Low = min(bar.Low, t.Price)
+		bar.Close = t.Price
+		bar.Volume += t.Volume
+		bar.Value += t.Price * float64(t.Volume)
+		updates = append(updates, BarUpdate{Symbol: t.Symbol, Resolution: res, Bar: *bar})
+	}
+	return updates
+}
+
+// CloseBefore closes every open bar whose bucket is over at now, so quiet
+// symbols still get their final bars
+func (b *BarBuilder) CloseBefore(now time.Time) []BarUpdate {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var updates []BarUpdate
+	for key, s := range b.series {
+		if s.current != nil && vnstock.BucketStart(now, key.resolution).After(s.current.Date) {
+			updates = append(updates, b.close(key, s))
+		}
+	}
+	return updates
+}
+
+// close moves the open bar of s to its closed bars
+func (b *BarBuilder) close(key seriesKey, s *liveSeries) BarUpdate {
+	bar := *s.current
+	s.current = nil
+	s.closed = append(s.closed, bar)
+	if b.history > 0 && len(s.closed) > b.history {
+		s.closed = s.closed[len(s.closed)-b.history:]
+	}
+	return BarUpdate{Symbol: key.symbol, Resolution: key.resolution, Bar: bar, Closed: true}
+}
+
+// Bars returns the closed bars of a symbol followed by its open bar, oldest first
+func (b *BarBuilder) Bars(symbol string, resolution vnstock.Resolution) []vnstock.OHLCV {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.series[seriesKey{symbol, resolution}]
+	if !ok {
+		return nil
+	}
+	bars := make([]vnstock.OHLCV, len(s.closed), len(s.closed)+1)
+	copy(bars, s.closed)
+	if s.current != nil {
+		bars = append(bars, *s.current)
+	}
+	return bars
+}
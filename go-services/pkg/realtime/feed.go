@@ -0,0 +1,136 @@
+package realtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// subscribeRequest is sent to the feed after connecting
+type subscribeRequest struct {
+	Action  string   `json:"action"`
+	Symbols []string `json:"symbols"`
+}
+
+// FeedClient reads a price-board WebSocket feed
+type FeedClient struct {
+	URL string
+	// Symbols to subscribe to; empty subscribes to the whole board
+	Symbols    []string
+	Normalizer Normalizer
+	// MinBackoff and MaxBackoff bound the wait between reconnection
+	// attempts, which doubles after each failed connection
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	dialer *websocket.Dialer
+}
+
+// NewFeedClient creates a client for the feed at url
+func NewFeedClient(url string, symbols []string, priceScale float64) *FeedClient {
+	return &FeedClient{
+		URL:        url,
+		Symbols:    symbols,
+		Normalizer: Normalizer{PriceScale: priceScale},
+		MinBackoff: time.Second,
+		MaxBackoff: time.Minute,
+		dialer:     &websocket.Dialer{HandshakeTimeout: 10 * time.Second},
+	}
+}
+
+// Run delivers the feed's events to handle until ctx is done, reconnecting
+// whenever the connection drops. Messages that fail normalization are logged
+// and skipped.
+func (f *FeedClient) Run(ctx context.Context, handle func(Event)) error {
+	backoff := f.MinBackoff
+	for {
+		start := time.Now()
+		err := f.stream(ctx, handle)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// A connection that stayed up a while resets the backoff
+		if time.Since(start) > f.MaxBackoff {
+			backoff = f.MinBackoff
+		}
+		log.Printf("Warning: realtime feed %s disconnected: %v, reconnecting in %s", f.URL, err, backoff)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, f.MaxBackoff)
+	}
+}
+
+// stream runs a single connection until it fails or ctx is done
+func (f *FeedClient) stream(ctx context.Context, handle func(Event)) error {
+	conn, _, err := f.dialer.DialContext(ctx, f.URL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	// Unblock the read loop on shutdown
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	if err := conn.WriteJSON(subscribeRequest{Action: "subscribe", Symbols: f.Symbols}); err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		messages, err := decodeMessages(data)
+		if err != nil {
+			log.Printf("Warning: undecodable realtime message: %v", err)
+			continue
+		}
+		for _, m := range messages {
+			event, err := f.Normalizer.Normalize(m)
+			if err != nil {
+				log.Printf("Warning: dropped realtime message: %v", err)
+				continue
+			}
+			handle(event)
+		}
+	}
+}
+
+// decodeMessages decodes a frame holding one message or an array of them
+func decodeMessages(data []byte) ([]Message, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var messages []Message
+		err := json.Unmarshal(data, &messages)
+		return messages, err
+	}
+
+	var m Message
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return []Message{m}, nil
+}
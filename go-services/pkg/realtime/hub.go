@@ -0,0 +1,79 @@
+package realtime
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Hub fans bar updates out to in-process subscribers. Publishing never
+// blocks: a subscriber whose buffer is full misses the update.
+type Hub struct {
+	mu   sync.RWMutex
+	subs map[*Subscription]struct{}
+}
+
+// NewHub creates an empty hub
+func NewHub() *Hub {
+	return &Hub{subs: make(map[*Subscription]struct{})}
+}
+
+// Subscription receives the updates of its symbols on C
+type Subscription struct {
+	C <-chan BarUpdate
+
+	ch      chan BarUpdate
+	symbols map[string]bool
+	hub     *Hub
+	dropped atomic.Int64
+}
+
+// Subscribe returns a subscription to the updates of symbols, or of all
+// symbols if none are given, buffering up to buffer updates
+func (h *Hub) Subscribe(buffer int, symbols ...string) *Subscription {
+	ch := make(chan BarUpdate, buffer)
+	sub := &Subscription{C: ch, ch: ch, hub: h}
+	if len(symbols) > 0 {
+		sub.symbols = make(map[string]bool, len(symbols))
+		for _, s := range symbols {
+			sub.symbols[s] = true
+		}
+	}
+
+	h.mu.Lock()
+	h.subs[sub] = struct{}{}
+	h.mu.Unlock()
+	return sub
+}
+
+// Publish delivers an update to every interested subscriber
+func (h *Hub) Publish(u BarUpdate) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subs {
+		if sub.symbols != nil && !sub.symbols[u.Symbol] {
+			continue
+		}
+		select {
+		case sub.ch <- u:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+// Close unsubscribes and closes C
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	if _, ok := s.hub.subs[s]; ok {
+		delete(s.hub.subs, s)
+		close(s.ch)
+	}
+}
+
+// Dropped returns how many updates were missed because the buffer was full
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Load()
+}
@@ -0,0 +1,98 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// closeInterval is how often bars of quiet symbols are checked for closing
+const closeInterval = time.Second
+
+// Ingestor builds bars from the trades of a feed and publishes every bar
+// update on a hub. It also keeps the latest quote of each symbol.
+type Ingestor struct {
+	feed    *FeedClient
+	builder *BarBuilder
+	hub     *Hub
+
+	mu     sync.RWMutex
+	quotes map[string]Quote
+	trades map[string]Trade
+}
+
+// NewIngestor creates an ingestor reading feed
+func NewIngestor(feed *FeedClient, builder *BarBuilder, hub *Hub) *Ingestor {
+	return &Ingestor{
+		feed:    feed,
+		builder: builder,
+		hub:     hub,
+		quotes:  make(map[string]Quote),
+		trades:  make(map[string]Trade),
+	}
+}
+
+// Run ingests the feed until ctx is done
+func (i *Ingestor) Run(ctx context.Context) error {
+	go func() {
+		ticker := time.NewTicker(closeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				i.publish(i.builder.CloseBefore(now))
+			}
+		}
+	}()
+
+	return i.feed.Run(ctx, i.Handle)
+}
+
+// Handle applies one normalized event
+func (i *Ingestor) Handle(e Event) {
+	switch {
+	case e.Trade != nil:
+		i.mu.Lock()
+		i.trades[e.Trade.Symbol] = *e.Trade
+		i.mu.Unlock()
+		i.publish(i.builder.Add(*e.Trade))
+	case e.Quote != nil:
+		i.mu.Lock()
+		i.quotes[e.Quote.Symbol] = *e.Quote
+		i.mu.Unlock()
+	}
+}
+
+func (i *Ingestor) publish(updates []BarUpdate) {
+	for _, u := range updates {
+		i.hub.Publish(u)
+	}
+}
+
+// Builder returns the bar builder fed by the ingestor
+func (i *Ingestor) Builder() *BarBuilder {
+	return i.builder
+}
+
+// Hub returns the hub bar updates are published on
+func (i *Ingestor) Hub() *Hub {
+	return i.hub
+}
+
+// LastTrade returns the most recent trade of a symbol
+func (i *Ingestor) LastTrade(symbol string) (Trade, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	t, ok := i.trades[symbol]
+	return t, ok
+}
+
+// LatestQuote returns the most recent best bid and offer of a symbol
+func (i *Ingestor) LatestQuote(symbol string) (Quote, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	q, ok := i.quotes[symbol]
+	return q, ok
+}
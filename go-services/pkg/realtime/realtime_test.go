@@ -0,0 +1,178 @@
+package realtime
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+func TestNormalize(t *testing.T) {
+	n := Normalizer{PriceScale: 1000}
+
+	e, err := n.Normalize(Message{Type: "match", Symbol: " vnm ", Time: 1751249705000, Price: 61.5, Volume: 1000, Side: "B"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Trade == nil || e.Trade.Symbol != "VNM" || e.Trade.Price != 61500 || e.Trade.Side != SideBuy {
+		t.Errorf("got trade %+v, want VNM at 61500 bought", e.Trade)
+	}
+	if want := time.Date(2025, 6, 30, 9, 15, 5, 0, vnstock.MarketLocation); !e.Trade.Time.Equal(want) {
+		t.Errorf("got time %v, want %v", e.Trade.Time, want)
+	}
+
+	// Index levels are not scaled
+	e, err = n.Normalize(Message{Type: "t", Symbol: "VNINDEX", Time: 1751249705000, Price: 1371.2, Volume: 100})
+	if err != nil || e.Trade.Price != 1371.2 {
+		t.Errorf("got %+v, %v, want index level 1371.2", e.Trade, err)
+	}
+
+	e, err = n.Normalize(Message{Type: "bidask", Symbol: "FPT", Time: 1751249705000, BidPrice: 117.9, BidVolume: 100, AskPrice: 118, AskVolume: 200})
+	if err != nil || e.Quote == nil || e.Quote.BidPrice != 117900 || e.Quote.AskPrice != 118000 {
+		t.Errorf("got %+v, %v, want FPT quote 117900/118000", e.Quote, err)
+	}
+
+	invalid := []Message{
+		{Type: "trade", Time: 1751249705000, Price: 61.5, Volume: 100},
+		{Type: "trade", Symbol: "VNM", Price: 61.5, Volume: 100},
+		{Type: "trade", Symbol: "VNM", Time: 1751249705000, Price: 0, Volume: 100},
+		{Type: "quote", Symbol: "VNM", Time: 1751249705000, BidPrice: 62, AskPrice: 61.5},
+		{Type: "order", Symbol: "VNM", Time: 1751249705000},
+	}
+	for _, m := range invalid {
+		if _, err := n.Normalize(m); err == nil {
+			t.Errorf("Normalize(%+v) succeeded, want error", m)
+		}
+	}
+}
+
+func TestBarBuilder(t *testing.T) {
+	messages, err := LoadRecording("testdata/ticks.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	builder := NewBarBuilder([]vnstock.Resolution{vnstock.Resolution1m, vnstock.Resolution5m}, 10)
+	n := Normalizer{PriceScale: 1000}
+	closed := 0
+	for _, m := range messages {
+		e, err := n.Normalize(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Trade == nil {
+			continue
+		}
+		for _, u := range builder.Add(*e.Trade) {
+			if u.Closed {
+				closed++
+			}
+		}
+	}
+
+	minute := builder.Bars("VNM", vnstock.Resolution1m)
+	if len(minute) != 3 {
+		t.Fatalf("got %d 1m bars, want 3", len(minute))
+	}
+	first := minute[0]
+	if first.Open != 61500 || first.High != 61800 || first.Low != 61300 || first.Close != 61300 || first.Volume != 2000 {
+		t.Errorf("first 1m bar = %+v", first)
+	}
+	if first.Value != 61500*1000+61800*400+61300*600 {
+		t.Errorf("first 1m bar value = %v", first.Value)
+	}
+
+	five := builder.Bars("VNM", vnstock.Resolution5m)
+	if len(five) != 2 || five[0].Close != 61600 || five[0].Volume != 2200 || five[1].Close != 61900 {
+		t.Errorf("5m bars = %+v", five)
+	}
+
+	// The VNM 09:15 and 09:16 1m bars, its 09:15 5m bar and the FPT 09:15 1m bar
+	if closed != 4 {
+		t.Errorf("got %d closed updates, want 4", closed)
+	}
+
+	// A late trade does not reopen a closed bar
+	late := Trade{Symbol: "VNM", Time: first.Date.Add(30 * time.Second), Price: 70000, Volume: 100}
+	builder.Add(late)
+	if bars := builder.Bars("VNM", vnstock.Resolution1m); bars[0].High != 61800 || len(bars) != 3 {
+		t.Errorf("late trade changed closed bars: %+v", bars)
+	}
+
+	// Quiet symbols close by the clock
+	updates := builder.CloseBefore(first.Date.Add(10 * time.Minute))
+	if len(updates) != 4 {
+		t.Errorf("CloseBefore closed %d bars, want 4", len(updates))
+	}
+}
+
+func TestHub(t *testing.T) {
+	hub := NewHub()
+	all := hub.Subscribe(10)
+	vnm := hub.Subscribe(1, "VNM")
+
+	hub.Publish(BarUpdate{Symbol: "VNM"})
+	hub.Publish(BarUpdate{Symbol: "FPT"})
+	hub.Publish(BarUpdate{Symbol: "VNM"})
+
+	if len(all.C) != 3 {
+		t.Errorf("got %d updates for all symbols, want 3", len(all.C))
+	}
+	if u := <-vnm.C; u.Symbol != "VNM" {
+		t.Errorf("got update for %s, want VNM", u.Symbol)
+	}
+	if vnm.Dropped() != 1 {
+		t.Errorf("got %d dropped, want 1", vnm.Dropped())
+	}
+
+	vnm.Close()
+	vnm.Close()
+	if _, ok := <-vnm.C; ok {
+		t.Error("closed subscription still receives updates")
+	}
+	hub.Publish(BarUpdate{Symbol: "VNM"})
+}
+
+func TestReplayThroughFeed(t *testing.T) {
+	messages, err := LoadRecording("testdata/ticks.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(NewReplayServer(messages, ReplayOptions{}))
+	defer server.Close()
+
+	feed := NewFeedClient("ws"+strings.TrimPrefix(server.URL, "http"), []string{"VNM"}, 1000)
+	ingestor := NewIngestor(feed, NewBarBuilder([]vnstock.Resolution{vnstock.Resolution1m}, 10), NewHub())
+	sub := ingestor.Hub().Subscribe(100, "VNM")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Six VNM messages: five trades and a quote
+	events := 0
+	err = feed.Run(ctx, func(e Event) {
+		ingestor.Handle(e)
+		if events++; events == 6 {
+			cancel()
+		}
+	})
+	if err != context.Canceled {
+		t.Fatalf("Run returned %v, want context.Canceled", err)
+	}
+
+	if bars := ingestor.Builder().Bars("VNM", vnstock.Resolution1m); len(bars) != 3 || bars[2].Close != 61900 {
+		t.Errorf("got bars %+v", bars)
+	}
+	if bars := ingestor.Builder().Bars("FPT", vnstock.Resolution1m); len(bars) != 0 {
+		t.Errorf("got %d FPT bars, want none: not subscribed", len(bars))
+	}
+	if q, ok := ingestor.LatestQuote("VNM"); !ok || q.BidPrice != 61400 {
+		t.Errorf("got quote %+v", q)
+	}
+	if len(sub.C) != 7 {
+		t.Errorf("got %d published updates, want 7", len(sub.C))
+	}
+}
@@ -0,0 +1,183 @@
+package realtime
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// LoadRecording reads a tick file, see ReadRecording
+func LoadRecording(path string) ([]Message, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer f.Close()
+
+	messages, err := ReadRecording(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return messages, nil
+}
+
+// ReadRecording reads a recording of one JSON Message per line and sorts it
+// by time. Blank lines and lines starting with # are skipped.
+func ReadRecording(r io.Reader) ([]Message, error) {
+	var messages []Message
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		var m Message
+		if err := json.Unmarshal([]byte(text), &m); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		messages = append(messages, m)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+
+	sort.SliceStable(messages, func(i, j int) bool { return messages[i].Time < messages[j].Time })
+	return messages, nil
+}
+
+// ReplayOptions configures a ReplayServer
+type ReplayOptions struct {
+	// Speed multiplies the recorded pace: 1 is real time, 10 ten times
+	// faster. Zero sends messages without delay.
+	Speed float64
+	// Loop restarts the recording when it ends instead of closing the connection
+	Loop bool
+	// Retime shifts timestamps so the recording starts when it is replayed,
+	// which lets live bar building treat it as today's trading
+	Retime bool
+}
+
+// ReplayServer streams a recording over WebSocket in the price-board feed
+// format. Each connection replays from the start, filtered by the symbols of
+// its subscribe request.
+type ReplayServer struct {
+	messages []Message
+	opts     ReplayOptions
+	upgrader websocket.Upgrader
+}
+
+// NewReplayServer creates a server replaying messages
+func NewReplayServer(messages []Message, opts ReplayOptions) *ReplayServer {
+	return &ReplayServer{
+		messages: messages,
+		opts:     opts,
+		upgrader: websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }},
+	}
+}
+
+// ServeHTTP upgrades the request and replays the recording
+func (s *ReplayServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	// The client subscribes first, then may resubscribe at any time
+	var mu sync.Mutex
+	var symbols map[string]bool
+	subscribed := make(chan struct{})
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		first := true
+		for {
+			var req subscribeRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			mu.Lock()
+			symbols = nil
+			if len(req.Symbols) > 0 {
+				symbols = make(map[string]bool, len(req.Symbols))
+				for _, sym := range req.Symbols {
+					symbols[strings.ToUpper(sym)] = true
+				}
+			}
+			mu.Unlock()
+			if first {
+				close(subscribed)
+				first = false
+			}
+		}
+	}()
+
+	select {
+	case <-subscribed:
+	case <-closed:
+		return
+	}
+
+	for {
+		if err := s.replay(conn, closed, func(symbol string) bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return symbols == nil || symbols[strings.ToUpper(symbol)]
+		}); err != nil {
+			return
+		}
+		if !s.opts.Loop {
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, "end of recording"), time.Now().Add(time.Second))
+			return
+		}
+	}
+}
+
+// replay sends the recording once
+func (s *ReplayServer) replay(conn *websocket.Conn, closed <-chan struct{}, wanted func(string) bool) error {
+	if len(s.messages) == 0 {
+		return fmt.Errorf("empty recording")
+	}
+
+	start := time.Now()
+	first := s.messages[0].Time
+	for _, m := range s.messages {
+		offset := time.Duration(m.Time-first) * time.Millisecond
+		if s.opts.Speed > 0 {
+			offset = time.Duration(float64(offset) / s.opts.Speed)
+			timer := time.NewTimer(time.Until(start.Add(offset)))
+			select {
+			case <-closed:
+				timer.Stop()
+				return fmt.Errorf("client disconnected")
+			case <-timer.C:
+			}
+		}
+		if !wanted(m.Symbol) {
+			continue
+		}
+
+		if s.opts.Retime {
+			m.Time = start.Add(offset).UnixMilli()
+		}
+		if err := conn.WriteJSON(m); err != nil {
+			log.Printf("Warning: replay write failed: %v", err)
+			return err
+		}
+	}
+	return nil
+}
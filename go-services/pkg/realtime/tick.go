@@ -0,0 +1,128 @@
+// Package realtime ingests trades and quotes from a price-board WebSocket
+// feed, builds live intraday bars from the trades and publishes every bar
+// change to subscribers in the process. A replay server streams recorded
+// ticks in the feed format for offline development and tests.
+package realtime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+// Message is one event of the price-board feed, and one line of a recorded
+// tick file. Boards usually quote prices in thousands of VND; see
+// Normalizer.PriceScale.
+type Message struct {
+	// Type is "trade" or "quote"; the board abbreviations "t", "match", "q"
+	// and "bidask" are accepted too
+	Type   string `json:"t"`
+	Symbol string `json:"s"`
+	// Time is the exchange timestamp in Unix milliseconds
+	Time int64 `json:"ts"`
+
+	Price  float64 `json:"p,omitempty"`
+	Volume int64   `json:"v,omitempty"`
+	// Side is the aggressor of a trade: "B" for buy, "S" for sell, empty in auctions
+	Side string `json:"side,omitempty"`
+
+	BidPrice  float64 `json:"bp,omitempty"`
+	BidVolume int64   `json:"bv,omitempty"`
+	AskPrice  float64 `json:"ap,omitempty"`
+	AskVolume int64   `json:"av,omitempty"`
+}
+
+// Trade is a matched order
+type Trade struct {
+	Symbol string    `json:"symbol"`
+	Time   time.Time `json:"time"`
+	Price  float64   `json:"price"`
+	Volume int64     `json:"volume"`
+	Side   string    `json:"side,omitempty"`
+}
+
+// Quote is the best bid and offer of a symbol
+type Quote struct {
+	Symbol    string    `json:"symbol"`
+	Time      time.Time `json:"time"`
+	BidPrice  float64   `json:"bid_price"`
+	BidVolume int64     `json:"bid_volume"`
+	AskPrice  float64   `json:"ask_price"`
+	AskVolume int64     `json:"ask_volume"`
+}
+
+// Event is a normalized feed message: either a trade or a quote
+type Event struct {
+	Trade *Trade
+	Quote *Quote
+}
+
+// Trade sides
+const (
+	SideBuy  = "buy"
+	SideSell = "sell"
+)
+
+// Normalizer converts feed messages into trades and quotes
+type Normalizer struct {
+	// PriceScale multiplies feed prices into VND, e.g. 1000 for a board
+	// quoting 65.4 for 65,400 VND. Zero means 1. Index levels are never scaled.
+	PriceScale float64
+}
+
+// Normalize validates m and converts it to an Event
+func (n Normalizer) Normalize(m Message) (Event, error) {
+	symbol := strings.ToUpper(strings.TrimSpace(m.Symbol))
+	if symbol == "" {
+		return Event{}, fmt.Errorf("message without symbol")
+	}
+	if m.Time <= 0 {
+		return Event{}, fmt.Errorf("%s: message without timestamp", symbol)
+	}
+	t := time.UnixMilli(m.Time).In(vnstock.MarketLocation)
+
+	scale := n.PriceScale
+	if scale == 0 || vnstock.IsIndex(symbol) {
+		scale = 1
+	}
+
+	switch strings.ToLower(m.Type) {
+	case "trade", "t", "match":
+		if m.Price <= 0 || m.Volume <= 0 {
+			return Event{}, fmt.Errorf("%s: invalid trade %v x %d", symbol, m.Price, m.Volume)
+		}
+		side := ""
+		switch strings.ToUpper(m.Side) {
+		case "B", "BUY":
+			side = SideBuy
+		case "S", "SELL":
+			side = SideSell
+		}
+		return Event{Trade: &Trade{
+			Symbol: symbol,
+			Time:   t,
+			Price:  m.Price * scale,
+			Volume: m.Volume,
+			Side:   side,
+		}}, nil
+	case "quote", "q", "bidask":
+		if m.BidPrice < 0 || m.AskPrice < 0 || m.BidVolume < 0 || m.AskVolume < 0 {
+			return Event{}, fmt.Errorf("%s: invalid quote", symbol)
+		}
+		if m.BidPrice > 0 && m.AskPrice > 0 && m.BidPrice > m.AskPrice {
+			return Event{}, fmt.Errorf("%s: crossed quote %v/%v", symbol, m.BidPrice, m.AskPrice)
+		}
+		return Event{Quote: &Quote{
+			Symbol:    symbol,
+			Time:      t,
+			BidPrice:  m.BidPrice * scale,
+			BidVolume: m.BidVolume,
+			AskPrice:  m.AskPrice * scale,
+			AskVolume: m.AskVolume,
+		}}, nil
+	default:
+		return Event{}, fmt.Errorf("%s: unknown message type %q", symbol, m.Type)
+	}
+}
@@ -0,0 +1,128 @@
+// Package rules implements the trading rules of the Vietnamese exchanges:
+// daily price bands, ceiling and floor prices and tick sizes.
+package rules
+
+import (
+	"math"
+
+	"vnstock-hybrid/pkg/calendar"
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+// bandPercent is the daily price limit of each exchange, in percent of the reference price
+var bandPercent = map[calendar.Exchange]float64{
+	calendar.HOSE:  7,
+	calendar.HNX:   10,
+	calendar.UPCOM: 15,
+}
+
+// fundTick is the flat price step, in VND, of ETFs and covered warrants
+const fundTick = 10
+
+// priceEpsilon absorbs float noise when comparing prices against ticks
+const priceEpsilon = 1e-6
+
+// BandPercent returns the daily price limit of an exchange, in percent of the reference price
+func BandPercent(exchange calendar.Exchange) float64 {
+	if band, ok := bandPercent[exchange]; ok {
+		return band
+	}
+	return bandPercent[calendar.HOSE]
+}
+
+// TickSize returns the minimum price step, in VND, of an instrument trading at
+// price. ETFs and covered warrants step by 10 VND. For stocks HOSE uses a
+// stepped schedule; HNX and UPCOM use a flat 100 VND tick.
+func TickSize(exchange calendar.Exchange, instrument vnstock.InstrumentType, price float64) float64 {
+	if instrument == vnstock.InstrumentETF || instrument == vnstock.InstrumentCW {
+		return fundTick
+	}
+	if exchange == calendar.HNX || exchange == calendar.UPCOM {
+		return 100
+	}
+	switch {
+	case price < 10000:
+		return 10
+	case price < 50000:
+		return 50
+	default:
+		return 100
+	}
+}
+
+// RoundToTick rounds price to the nearest valid tick
+func RoundToTick(exchange calendar.Exchange, instrument vnstock.InstrumentType, price float64) float64 {
+	tick := TickSize(exchange, instrument, price)
+	return math.Round(price/tick) * tick
+}
+
+// FloorToTick rounds price down to a valid tick
+func FloorToTick(exchange calendar.Exchange, instrument vnstock.InstrumentType, price float64) float64 {
+	tick := TickSize(exchange, instrument, price)
+	return math.Floor(price/tick+priceEpsilon) * tick
+}
+
+// CeilToTick rounds price up to a valid tick
+func CeilToTick(exchange calendar.Exchange, instrument vnstock.InstrumentType, price float64) float64 {
+	tick := TickSize(exchange, instrument, price)
+	return math.Ceil(price/tick-priceEpsilon) * tick
+}
+
+// PriceLimits are the prices a stock or ETF may trade at during one session
+type PriceLimits struct {
+	Exchange    calendar.Exchange      `json:"exchange"`
+	Instrument  vnstock.InstrumentType `json:"instrument"`
+	Reference   float64                `json:"reference"`
+	Ceiling     float64                `json:"ceiling"`
+	Floor       float64                `json:"floor"`
+	BandPercent float64                `json:"band_percent"`
+}
+
+// Limits computes the ceiling and floor prices for a session from its
+// reference price (the previous close). The ceiling is rounded down and the
+// floor rounded up so both stay within the band. Covered warrants are not
+// covered: their band follows the underlying through the conversion ratio.
+func Limits(exchange calendar.Exchange, instrument vnstock.InstrumentType, reference float64) PriceLimits {
+	band := BandPercent(exchange)
+	return PriceLimits{
+		Exchange:    exchange,
+		Instrument:  instrument,
+		Reference:   reference,
+		Ceiling:     FloorToTick(exchange, instrument, reference*(1+band/100)),
+		Floor:       CeilToTick(exchange, instrument, reference*(1-band/100)),
+		BandPercent: band,
+	}
+}
+
+// Assessment describes how a bar traded relative to its price limits
+type Assessment struct {
+	PriceLimits
+	// LimitUp and LimitDown report a close at the ceiling or floor
+	LimitUp   bool `json:"limit_up"`
+	LimitDown bool `json:"limit_down"`
+	// Locked reports a bar that traded only at the limit price, so it carries
+	// no information about where supply and demand would have met
+	Locked bool `json:"locked"`
+}
+
+// Assess compares a bar with the limits of its session
+func (l PriceLimits) Assess(bar vnstock.OHLCV) Assessment {
+	a := Assessment{PriceLimits: l}
+	tolerance := TickSize(l.Exchange, l.Instrument, bar.Close) / 2
+
+	if l.Ceiling > 0 && bar.Close >= l.Ceiling-tolerance {
+		a.LimitUp = true
+		a.Locked = bar.Low >= l.Ceiling-tolerance
+	}
+	if l.Floor > 0 && bar.Close <= l.Floor+tolerance {
+		a.LimitDown = true
+		a.Locked = bar.High <= l.Floor+tolerance
+	}
+
+	return a
+}
+
+// Clamp restricts price to the session's trading range
+func (l PriceLimits) Clamp(price float64) float64 {
+	return math.Max(l.Floor, math.Min(l.Ceiling, price))
+}
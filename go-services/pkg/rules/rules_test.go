@@ -0,0 +1,81 @@
+package rules
+
+import (
+	"testing"
+
+	"vnstock-hybrid/pkg/calendar"
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+func TestLimits(t *testing.T) {
+	cases := []struct {
+		exchange       calendar.Exchange
+		instrument     vnstock.InstrumentType
+		reference      float64
+		ceiling, floor float64
+	}{
+		{calendar.HOSE, vnstock.InstrumentStock, 50000, 53500, 46500},
+		{calendar.HOSE, vnstock.InstrumentStock, 23450, 25050, 21850},
+		{calendar.HOSE, vnstock.InstrumentStock, 9870, 10550, 9180},
+		{calendar.HNX, vnstock.InstrumentStock, 23400, 25700, 21100},
+		{calendar.UPCOM, vnstock.InstrumentStock, 10000, 11500, 8500},
+		// ETFs step by 10 VND at any price
+		{calendar.HOSE, vnstock.InstrumentETF, 23450, 25090, 21810},
+	}
+
+	for _, c := range cases {
+		l := Limits(c.exchange, c.instrument, c.reference)
+		if l.Ceiling != c.ceiling || l.Floor != c.floor {
+			t.Errorf("Limits(%s, %s, %.0f) = %.0f/%.0f, want %.0f/%.0f",
+				c.exchange, c.instrument, c.reference, l.Ceiling, l.Floor, c.ceiling, c.floor)
+		}
+	}
+}
+
+func TestRoundToTick(t *testing.T) {
+	cases := []struct {
+		exchange   calendar.Exchange
+		instrument vnstock.InstrumentType
+		price      float64
+		want       float64
+	}{
+		{calendar.HOSE, vnstock.InstrumentStock, 9876, 9880},
+		{calendar.HOSE, vnstock.InstrumentStock, 23474, 23450},
+		{calendar.HOSE, vnstock.InstrumentStock, 23476, 23500},
+		{calendar.HOSE, vnstock.InstrumentStock, 61249, 61200},
+		{calendar.HNX, vnstock.InstrumentStock, 12349, 12300},
+		{calendar.HOSE, vnstock.InstrumentETF, 61249, 61250},
+		{calendar.HNX, vnstock.InstrumentETF, 12349, 12350},
+		{calendar.HOSE, vnstock.InstrumentCW, 1234, 1230},
+	}
+
+	for _, c := range cases {
+		if got := RoundToTick(c.exchange, c.instrument, c.price); got != c.want {
+			t.Errorf("RoundToTick(%s, %s, %.0f) = %.0f, want %.0f", c.exchange, c.instrument, c.price, got, c.want)
+		}
+	}
+}
+
+func TestAssess(t *testing.T) {
+	l := Limits(calendar.HOSE, vnstock.InstrumentStock, 50000)
+
+	locked := l.Assess(vnstock.OHLCV{Open: 53500, High: 53500, Low: 53500, Close: 53500})
+	if !locked.LimitUp || !locked.Locked || locked.LimitDown {
+		t.Errorf("bar traded only at the ceiling: got %+v", locked)
+	}
+
+	closedAtCeiling := l.Assess(vnstock.OHLCV{Open: 51000, High: 53500, Low: 50500, Close: 53500})
+	if !closedAtCeiling.LimitUp || closedAtCeiling.Locked {
+		t.Errorf("bar closing at the ceiling: got %+v", closedAtCeiling)
+	}
+
+	floor := l.Assess(vnstock.OHLCV{Open: 48000, High: 48500, Low: 46500, Close: 46500})
+	if !floor.LimitDown || floor.Locked {
+		t.Errorf("bar closing at the floor: got %+v", floor)
+	}
+
+	normal := l.Assess(vnstock.OHLCV{Open: 50000, High: 51000, Low: 49500, Close: 50800})
+	if normal.LimitUp || normal.LimitDown || normal.Locked {
+		t.Errorf("bar inside the band: got %+v", normal)
+	}
+}
@@ -0,0 +1,147 @@
+package synthetic
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"vnstock-hybrid/pkg/calendar"
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+const (
+	// quarterlyLag and annualLag are how long after the period end reports
+	// are published: 30 days for quarterly and 90 days for audited annual ones
+	quarterlyLag = 30 * 24 * time.Hour
+	annualLag    = 90 * 24 * time.Hour
+	// turnover is the typical share of outstanding shares traded in a day
+	turnover = 0.003
+	taxRate  = 0.2
+)
+
+// Financials returns the reports of a stock published by end, oldest first.
+// Earnings track the average price of each quarter at a symbol-specific P/E,
+// so valuation ratios stay in a realistic range as the series moves, and
+// equity grows with retained earnings.
+func (g *Generator) Financials(symbol string, exchange calendar.Exchange, end time.Time, period vnstock.Period) []vnstock.FinancialReport {
+	bars := g.Daily(symbol, exchange, end, math.MaxInt)
+	rng := rand.New(rand.NewSource(g.symbolSeed(symbol) ^ 0x46756e64))
+
+	var volume float64
+	for _, b := range bars[:min(len(bars), 20)] {
+		volume += float64(b.Volume)
+	}
+	shares := int64(volume/float64(min(len(bars), 20))/turnover) / 1000 * 1000
+
+	pe := 8 + 12*rng.Float64()
+	pb := 1 + 2*rng.Float64()
+	netMargin := 0.05 + 0.15*rng.Float64()
+	grossMargin := netMargin + 0.1 + 0.15*rng.Float64()
+	leverage := 0.2 + 1.3*rng.Float64()
+	payout := 0.3 * rng.Float64()
+
+	// Average close of every quarter, keyed by year*4 + quarter-1
+	sums := map[int]float64{}
+	counts := map[int]int{}
+	for _, b := range bars {
+		key := b.Date.Year()*4 + int(b.Date.Month()-1)/3
+		sums[key] += b.Close
+		counts[key]++
+	}
+
+	first := bars[0].Date.Year()*4 + int(bars[0].Date.Month()-1)/3
+	equity := bars[0].Close * float64(shares) / pb
+	debt := equity * leverage
+
+	var quarters []vnstock.FinancialReport
+	for key := first; counts[key] > 0; key++ {
+		year, quarter := key/4, key%4+1
+		if quarterEnd(year, quarter).Add(quarterlyLag).After(end) {
+			break
+		}
+
+		price := sums[key] / float64(counts[key])
+		netIncome := price * float64(shares) / pe / 4 * (1 + 0.25*rng.NormFloat64())
+		if quarter == 4 {
+			// Year-end bookings lift the fourth quarter
+			netIncome *= 1.2
+		}
+		revenue := math.Abs(netIncome) / netMargin
+		dividends := math.Max(0, netIncome) * payout
+		equity += netIncome - dividends
+		newDebt := equity * leverage * (1 + 0.1*rng.NormFloat64())
+		capex := revenue * (0.03 + 0.05*rng.Float64())
+		operating := netIncome*(1+0.3*rng.NormFloat64()) + capex*0.5
+
+		liabilities := newDebt + revenue*0.4
+		quarters = append(quarters, vnstock.FinancialReport{
+			Period:  vnstock.PeriodQuarter,
+			Year:    year,
+			Quarter: quarter,
+			Income: vnstock.IncomeStatement{
+				Revenue:         math.Round(revenue),
+				GrossProfit:     math.Round(revenue * grossMargin),
+				OperatingProfit: math.Round(netIncome / (1 - taxRate)),
+				NetIncome:       math.Round(netIncome),
+			},
+			Balance: vnstock.BalanceSheet{
+				TotalAssets:      math.Round(equity + liabilities),
+				TotalLiabilities: math.Round(liabilities),
+				TotalDebt:        math.Round(newDebt),
+				Equity:           math.Round(equity),
+			},
+			CashFlow: vnstock.CashFlowStatement{
+				OperatingCashFlow:  math.Round(operating),
+				InvestingCashFlow:  math.Round(-capex * 1.1),
+				FinancingCashFlow:  math.Round(newDebt - debt - dividends),
+				CapitalExpenditure: math.Round(capex),
+			},
+			SharesOutstanding: shares,
+		})
+		debt = newDebt
+	}
+
+	if period == vnstock.PeriodQuarter {
+		return quarters
+	}
+	return annualReports(quarters, end)
+}
+
+// annualReports sums complete years of quarterly reports, keeping only those
+// published by end
+func annualReports(quarters []vnstock.FinancialReport, end time.Time) []vnstock.FinancialReport {
+	var years []vnstock.FinancialReport
+	for i := 0; i+4 <= len(quarters); i++ {
+		if quarters[i].Quarter != 1 {
+			continue
+		}
+		q4 := quarters[i+3]
+		if quarterEnd(q4.Year, 4).Add(annualLag).After(end) {
+			break
+		}
+
+		year := vnstock.FinancialReport{
+			Period:            vnstock.PeriodYear,
+			Year:              q4.Year,
+			Balance:           q4.Balance,
+			SharesOutstanding: q4.SharesOutstanding,
+		}
+		for _, q := range quarters[i : i+4] {
+			year.Income.Revenue += q.Income.Revenue
+			year.Income.GrossProfit += q.Income.GrossProfit
+			year.Income.OperatingProfit += q.Income.OperatingProfit
+			year.Income.NetIncome += q.Income.NetIncome
+			year.CashFlow.OperatingCashFlow += q.CashFlow.OperatingCashFlow
+			year.CashFlow.InvestingCashFlow += q.CashFlow.InvestingCashFlow
+			year.CashFlow.FinancingCashFlow += q.CashFlow.FinancingCashFlow
+			year.CashFlow.CapitalExpenditure += q.CashFlow.CapitalExpenditure
+		}
+		years = append(years, year)
+	}
+	return years
+}
+
+// quarterEnd returns the last day of a fiscal quarter
+func quarterEnd(year, quarter int) time.Time {
+	return time.Date(year, time.Month(quarter*3)+1, 0, 0, 0, 0, 0, calendar.Location)
+}
@@ -0,0 +1,143 @@
+package synthetic
+
+import (
+	"context"
+	"time"
+
+	"vnstock-hybrid/pkg/calendar"
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+// ProviderType selects the synthetic provider in a vnstock.ProviderConfig
+const ProviderType = "synthetic"
+
+func init() {
+	vnstock.RegisterProvider(ProviderType, func(cfg vnstock.ProviderConfig) (vnstock.MarketDataProvider, error) {
+		return NewProvider(Options{Seed: cfg.Seed}), nil
+	})
+}
+
+// Options configures a synthetic Provider
+type Options struct {
+	Seed int64
+	// End is the last generated day; the zero value means today. Fixing it
+	// makes every response reproducible.
+	End time.Time
+}
+
+// Provider serves generated market data for the mock symbol list and the
+// market indices
+type Provider struct {
+	gen     *Generator
+	end     time.Time
+	mock    *vnstock.MockProvider
+	symbols []vnstock.SymbolInfo
+}
+
+// NewProvider creates a new synthetic market data provider
+func NewProvider(opts Options) *Provider {
+	mock := vnstock.NewMockProvider()
+	symbols, _ := mock.ListSymbols(context.Background(), "")
+	return &Provider{
+		gen:     NewGenerator(opts.Seed),
+		end:     opts.End,
+		mock:    mock,
+		symbols: symbols,
+	}
+}
+
+// GetHistoricalData returns generated bars for the last days trading days
+func (p *Provider) GetHistoricalData(ctx context.Context, symbol string, resolution vnstock.Resolution, days int) ([]vnstock.OHLCV, error) {
+	exchange := p.exchangeFor(symbol)
+	daily := p.gen.Daily(symbol, exchange, p.endTime(), days)
+
+	switch {
+	case resolution == vnstock.Resolution1D:
+		return daily, nil
+	case resolution.CoarserThan(vnstock.Resolution1D):
+		return vnstock.Resample(daily, resolution)
+	}
+
+	var minutes []vnstock.OHLCV
+	for _, day := range daily {
+		minutes = append(minutes, p.gen.Intraday(symbol, exchange, day)...)
+	}
+	if resolution == vnstock.Resolution1m {
+		return minutes, nil
+	}
+	return vnstock.Resample(minutes, resolution)
+}
+
+// GetLatestQuote returns a quote built from the last generated daily bar
+func (p *Provider) GetLatestQuote(ctx context.Context, symbol string) (*vnstock.Quote, error) {
+	bars := p.gen.Daily(symbol, p.exchangeFor(symbol), p.endTime(), 2)
+	latest := bars[len(bars)-1]
+
+	return &vnstock.Quote{
+		Symbol:         symbol,
+		Time:           latest.Date,
+		Price:          latest.Close,
+		Open:           latest.Open,
+		High:           latest.High,
+		Low:            latest.Low,
+		Volume:         latest.Volume,
+		Value:          latest.Value,
+		ReferencePrice: bars[0].Close,
+	}, nil
+}
+
+// GetFlows returns generated foreign and proprietary flows of a stock or ETF
+func (p *Provider) GetFlows(ctx context.Context, symbol string, days int) ([]vnstock.Flow, error) {
+	if t := p.typeFor(symbol); t != vnstock.InstrumentStock && t != vnstock.InstrumentETF {
+		return nil, vnstock.ErrNoFlows
+	}
+	return p.gen.Flows(symbol, p.exchangeFor(symbol), p.endTime(), days), nil
+}
+
+// GetFinancials returns generated financial statements of a stock
+func (p *Provider) GetFinancials(ctx context.Context, symbol string, period vnstock.Period) ([]vnstock.FinancialReport, error) {
+	if p.typeFor(symbol) != vnstock.InstrumentStock {
+		return nil, vnstock.ErrNoFinancials
+	}
+	return p.gen.Financials(symbol, p.exchangeFor(symbol), p.endTime(), period), nil
+}
+
+// ListSymbols returns the symbols of the mock provider
+func (p *Provider) ListSymbols(ctx context.Context, exchange string) ([]vnstock.SymbolInfo, error) {
+	return p.mock.ListSymbols(ctx, exchange)
+}
+
+// exchangeFor returns the exchange whose band and ticks apply to symbol,
+// defaulting to HOSE
+func (p *Provider) exchangeFor(symbol string) calendar.Exchange {
+	if exchange, ok := vnstock.IndexExchange(symbol); ok {
+		return exchange
+	}
+	for _, s := range p.symbols {
+		if s.Symbol == symbol {
+			if exchange, err := calendar.ParseExchange(s.Exchange); err == nil {
+				return exchange
+			}
+		}
+	}
+	return calendar.HOSE
+}
+
+// typeFor returns the instrument type of symbol, inferred from its format for
+// symbols missing from the list
+func (p *Provider) typeFor(symbol string) vnstock.InstrumentType {
+	for _, s := range p.symbols {
+		if s.Symbol == symbol && s.Type != "" {
+			return s.Type
+		}
+	}
+	t, _ := vnstock.InferInstrumentType(symbol)
+	return t
+}
+
+func (p *Provider) endTime() time.Time {
+	if p.end.IsZero() {
+		return time.Now()
+	}
+	return p.end
+}
@@ -0,0 +1,363 @@
+// Package synthetic generates reproducible market data for demos and tests.
+// Every symbol gets its own series, seeded from the generator seed and the
+// symbol: geometric Brownian motion whose drift and volatility switch between
+// bull, bear and sideways regimes, GARCH volatility clustering, exchange price
+// bands with occasional limit-bound sessions, and volume that rises with the
+// size of the move. Foreign and proprietary flows lean with the moves of
+// the series.
+package synthetic
+
+import (
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"time"
+
+	"vnstock-hybrid/pkg/calendar"
+	"vnstock-hybrid/pkg/rules"
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+// origin is the first generated trading day. Every series starts here, so
+// the bars of a day do not depend on how much history is requested.
+var origin = time.Date(2012, 1, 3, 0, 0, 0, 0, calendar.Location)
+
+// regime is a market phase with its own drift and long-run volatility, both
+// as daily log returns
+type regime struct {
+	drift float64
+	vol   float64
+}
+
+// regimes are bull, bear and sideways markets
+var regimes = []regime{
+	{drift: 0.0010, vol: 0.015},
+	{drift: -0.0012, vol: 0.020},
+	{drift: 0, vol: 0.011},
+}
+
+const (
+	// regimeSwitchProb is the daily chance of leaving the current regime,
+	// giving regimes of about three months
+	regimeSwitchProb = 0.015
+	// garchAlpha and garchBeta weigh the last shock and the last variance
+	garchAlpha = 0.08
+	garchBeta  = 0.90
+	// limitDayProb is the daily chance of a stock closing at its ceiling or floor
+	limitDayProb = 0.01
+	// lockedProb is the chance that a limit day trades only at the limit price
+	lockedProb = 0.4
+	// indexVolScale damps index volatility relative to single stocks
+	indexVolScale = 0.7
+	// avgSharePrice converts index volume into traded value, in VND
+	avgSharePrice = 25000
+	lotSize       = 100
+)
+
+// indexProfile is the starting level and typical daily volume of an index
+type indexProfile struct {
+	level  float64
+	volume float64
+}
+
+var indexProfiles = map[string]indexProfile{
+	vnstock.IndexVNIndex:    {level: 950, volume: 600e6},
+	vnstock.IndexVN30:       {level: 950, volume: 250e6},
+	vnstock.IndexHNXIndex:   {level: 220, volume: 80e6},
+	vnstock.IndexHNX30:      {level: 400, volume: 40e6},
+	vnstock.IndexUPCOMIndex: {level: 85, volume: 50e6},
+}
+
+// Generator produces the synthetic series of any symbol
+type Generator struct {
+	seed int64
+}
+
+// NewGenerator creates a generator. Generators with the same seed produce
+// identical series.
+func NewGenerator(seed int64) *Generator {
+	return &Generator{seed: seed}
+}
+
+// symbolSeed derives the seed of one symbol's series
+func (g *Generator) symbolSeed(symbol string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(symbol))
+	return g.seed ^ int64(h.Sum64())
+}
+
+// Daily returns the daily bars of symbol for the last days trading days up
+// to end, oldest first. Stocks trade within the price band of exchange and at
+// its tick sizes; indices have neither and report traded value.
+func (g *Generator) Daily(symbol string, exchange calendar.Exchange, end time.Time, days int) []vnstock.OHLCV {
+	rng := rand.New(rand.NewSource(g.symbolSeed(symbol)))
+	isIndex := vnstock.IsIndex(symbol)
+
+	// Starting price and typical volume: stocks spread log-uniformly over
+	// 8,000-150,000 VND and 300k-10M shares a day
+	price := math.Exp(logUniform(rng, 8000, 150000))
+	baseVolume := math.Exp(logUniform(rng, 3e5, 1e7))
+	volScale := 1.0
+	if profile, ok := indexProfiles[symbol]; ok {
+		price, baseVolume, volScale = profile.level, profile.volume, indexVolScale
+	} else {
+		price = rules.RoundToTick(exchange, vnstock.InstrumentStock, price)
+	}
+	startPrice := price
+
+	current := rng.Intn(len(regimes))
+	variance := regimes[current].vol * regimes[current].vol * volScale * volScale
+	shock := 0.0
+
+	end = calendar.StartOfDay(end)
+	var bars []vnstock.OHLCV
+	for day := origin; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if !calendar.IsTradingDay(day) {
+			continue
+		}
+
+		if rng.Float64() < regimeSwitchProb {
+			current = (current + 1 + rng.Intn(len(regimes)-1)) % len(regimes)
+		}
+		r := regimes[current]
+		vol := r.vol * volScale
+
+		// GARCH(1,1) reverting to the regime's volatility
+		variance = (1-garchAlpha-garchBeta)*vol*vol + garchAlpha*shock*shock + garchBeta*variance
+		sigma := math.Sqrt(variance)
+		z := rng.NormFloat64()
+		ret := r.drift - variance/2 + sigma*z
+
+		limitDay := !isIndex && rng.Float64() < limitDayProb
+		locked := limitDay && rng.Float64() < lockedProb
+		if limitDay {
+			// Far enough to be clamped to the ceiling or floor below
+			ret = math.Copysign(1, z)
+		}
+
+		prev := price
+		bar := vnstock.OHLCV{
+			Date:  day,
+			Open:  prev * math.Exp(0.3*sigma*rng.NormFloat64()),
+			Close: prev * math.Exp(ret),
+		}
+		bar.High = math.Max(bar.Open, bar.Close) * math.Exp(0.5*sigma*math.Abs(rng.NormFloat64()))
+		bar.Low = math.Min(bar.Open, bar.Close) * math.Exp(-0.5*sigma*math.Abs(rng.NormFloat64()))
+
+		if isIndex {
+			bar.Open, bar.High, bar.Low, bar.Close = round2(bar.Open), round2(bar.High), round2(bar.Low), round2(bar.Close)
+		} else {
+			limits := rules.Limits(exchange, vnstock.InstrumentStock, prev)
+			bar.Open = limits.Clamp(rules.RoundToTick(exchange, vnstock.InstrumentStock, bar.Open))
+			bar.High = limits.Clamp(rules.RoundToTick(exchange, vnstock.InstrumentStock, bar.High))
+			bar.Low = limits.Clamp(rules.RoundToTick(exchange, vnstock.InstrumentStock, bar.Low))
+			bar.Close = limits.Clamp(rules.RoundToTick(exchange, vnstock.InstrumentStock, bar.Close))
+			if locked {
+				bar.Open, bar.High, bar.Low = bar.Close, bar.Close, bar.Close
+			}
+		}
+		bar.High = math.Max(bar.High, math.Max(bar.Open, bar.Close))
+		bar.Low = math.Min(bar.Low, math.Min(bar.Open, bar.Close))
+
+		// The realized move feeds the volatility, so the band also caps it
+		shock = math.Log(bar.Close/prev) - r.drift
+
+		// Volume grows with the size of the move relative to current
+		// volatility; locked sessions match few orders
+		move := math.Min(math.Abs(shock)/sigma, 4)
+		volume := baseVolume * (0.6 + 0.4*move) * math.Exp(0.25*rng.NormFloat64())
+		if locked {
+			volume *= 0.2
+		}
+		bar.Volume = max(int64(volume/lotSize)*lotSize, lotSize)
+
+		typical := (bar.High + bar.Low + bar.Close) / 3
+		if isIndex {
+			bar.Value = math.Round(float64(bar.Volume) * avgSharePrice * typical / startPrice)
+		} else {
+			bar.Value = math.Round(float64(bar.Volume) * typical)
+		}
+
+		bars = append(bars, bar)
+		price = bar.Close
+	}
+
+	if len(bars) > days {
+		bars = bars[len(bars)-days:]
+	}
+	return bars
+}
+
+// Intraday splits a daily bar into 1-minute bars over the continuous trading
+// sessions of exchange, plus a single closing auction print. Prices follow a
+// Brownian bridge from the open to the close that touches the day's high and
+// low; volume is heavier near the open and close.
+func (g *Generator) Intraday(symbol string, exchange calendar.Exchange, day vnstock.OHLCV) []vnstock.OHLCV {
+	rng := rand.New(rand.NewSource(g.symbolSeed(symbol) ^ day.Date.Unix()))
+	isIndex := vnstock.IsIndex(symbol)
+
+	var minutes []int
+	atc := -1
+	for _, s := range calendar.For(exchange).Sessions() {
+		switch s.Name {
+		case "continuous_morning", "continuous_afternoon":
+			for m := s.Start; m < s.End; m++ {
+				minutes = append(minutes, m)
+			}
+		case "ATC":
+			atc = s.End
+		}
+	}
+	n := len(minutes)
+
+	// Brownian bridge in log price from open to close
+	walk := make([]float64, n+1)
+	for k := 1; k <= n; k++ {
+		walk[k] = walk[k-1] + rng.NormFloat64()
+	}
+	spread := math.Log(day.High/day.Low) / 2 / math.Sqrt(float64(n))
+	logOpen, logClose := math.Log(day.Open), math.Log(day.Close)
+	path := make([]float64, n+1)
+	for k := range path {
+		t := float64(k) / float64(n)
+		path[k] = math.Exp(logOpen + t*(logClose-logOpen) + spread*(walk[k]-t*walk[n]))
+	}
+
+	round := func(p float64) float64 {
+		p = math.Max(day.Low, math.Min(day.High, p))
+		if isIndex {
+			return round2(p)
+		}
+		return math.Max(day.Low, math.Min(day.High, rules.RoundToTick(exchange, vnstock.InstrumentStock, p)))
+	}
+
+	bars := make([]vnstock.OHLCV, n)
+	weights := make([]float64, n)
+	totalWeight := 0.0
+	hi, lo := 0, 0
+	for k := 0; k < n; k++ {
+		open, close := round(path[k]), round(path[k+1])
+		bars[k] = vnstock.OHLCV{
+			Date:  day.Date.Add(time.Duration(minutes[k]) * time.Minute),
+			Open:  open,
+			High:  math.Max(open, close),
+			Low:   math.Min(open, close),
+			Close: close,
+		}
+		if bars[k].High > bars[hi].High {
+			hi = k
+		}
+		if bars[k].Low < bars[lo].Low {
+			lo = k
+		}
+
+		t := float64(k)/float64(n) - 0.5
+		weights[k] = (1 + 8*t*t) * (1 + math.Abs(path[k+1]/path[k]-1)*100)
+		totalWeight += weights[k]
+	}
+	bars[0].Open = day.Open
+	bars[hi].High = day.High
+	bars[lo].Low = day.Low
+
+	// The closing auction takes a tenth of the volume at the close
+	continuousVolume := day.Volume
+	if atc >= 0 {
+		continuousVolume = day.Volume * 9 / 10 / lotSize * lotSize
+		bars = append(bars, vnstock.OHLCV{
+			Date:   day.Date.Add(time.Duration(atc) * time.Minute),
+			Open:   day.Close,
+			High:   day.Close,
+			Low:    day.Close,
+			Close:  day.Close,
+			Volume: day.Volume - continuousVolume,
+		})
+	} else {
+		bars[n-1].Close = day.Close
+		bars[n-1].High = math.Max(bars[n-1].High, day.Close)
+		bars[n-1].Low = math.Min(bars[n-1].Low, day.Close)
+	}
+
+	allocated := int64(0)
+	for k := 0; k < n; k++ {
+		bars[k].Volume = int64(float64(continuousVolume)*weights[k]/totalWeight) / lotSize * lotSize
+		allocated += bars[k].Volume
+	}
+	bars[n-1].Volume += continuousVolume - allocated
+
+	for k := range bars {
+		if isIndex {
+			bars[k].Value = math.Round(day.Value * float64(bars[k].Volume) / float64(day.Volume))
+		} else {
+			bars[k].Value = math.Round(float64(bars[k].Volume) * bars[k].Close)
+		}
+	}
+
+	return bars
+}
+
+// logUniform draws the log of a value spread log-uniformly over [lo, hi)
+func logUniform(rng *rand.Rand, lo, hi float64) float64 {
+	return math.Log(lo) + rng.Float64()*(math.Log(hi)-math.Log(lo))
+}
+
+// round2 rounds index points to two decimals
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// Flows returns the foreign and proprietary flows of a stock over the last
+// days trading days up to end. Foreign net buying is persistent and leans
+// with the day's move; foreigners stop buying once the room is used up.
+// Proprietary desks trade smaller amounts, mostly against the move.
+func (g *Generator) Flows(symbol string, exchange calendar.Exchange, end time.Time, days int) []vnstock.Flow {
+	bars := g.Daily(symbol, exchange, end, math.MaxInt)
+	rng := rand.New(rand.NewSource(g.symbolSeed(symbol) ^ 0x466c6f77))
+
+	// Foreigners take 10-30% of the volume; the initial room is a few
+	// months of typical foreign buying
+	share := 0.1 + 0.2*rng.Float64()
+	room := int64(float64(bars[0].Volume) * share * (20 + 100*rng.Float64()))
+	lean := 0.0
+
+	flows := make([]vnstock.Flow, len(bars))
+	for i, b := range bars {
+		move := 0.0
+		if i > 0 {
+			move = math.Log(b.Close / bars[i-1].Close)
+		}
+		lean = 0.7*lean + 0.15*rng.NormFloat64() + 8*move
+		lean = math.Max(-0.9, math.Min(0.9, lean))
+
+		foreign := float64(b.Volume) * share
+		buy := int64(foreign*(1+lean)/2) / lotSize * lotSize
+		sell := int64(foreign*(1-lean)/2) / lotSize * lotSize
+		if buy-sell > room {
+			buy = sell + room
+		}
+		room -= buy - sell
+
+		prop := float64(b.Volume) * 0.05
+		propLean := math.Max(-0.9, math.Min(0.9, 0.3*rng.NormFloat64()-5*move))
+		propBuy := int64(prop*(1+propLean)/2) / lotSize * lotSize
+		propSell := int64(prop*(1-propLean)/2) / lotSize * lotSize
+
+		typical := (b.High + b.Low + b.Close) / 3
+		flows[i] = vnstock.Flow{
+			Date:                  b.Date,
+			ForeignBuyVolume:      buy,
+			ForeignSellVolume:     sell,
+			ForeignBuyValue:       math.Round(float64(buy) * typical),
+			ForeignSellValue:      math.Round(float64(sell) * typical),
+			ForeignRoom:           room,
+			ProprietaryBuyVolume:  propBuy,
+			ProprietarySellVolume: propSell,
+			ProprietaryBuyValue:   math.Round(float64(propBuy) * typical),
+			ProprietarySellValue:  math.Round(float64(propSell) * typical),
+		}
+	}
+
+	if len(flows) > days {
+		flows = flows[len(flows)-days:]
+	}
+	return flows
+}
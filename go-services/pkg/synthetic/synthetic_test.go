@@ -0,0 +1,231 @@
+package synthetic
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"vnstock-hybrid/pkg/calendar"
+	"vnstock-hybrid/pkg/rules"
+	"vnstock-hybrid/pkg/vnstock"
+)
+
+var testEnd = time.Date(2025, 6, 30, 0, 0, 0, 0, calendar.Location)
+
+func TestDailyDeterministic(t *testing.T) {
+	a := NewGenerator(7).Daily("VNM", calendar.HOSE, testEnd, 250)
+	b := NewGenerator(7).Daily("VNM", calendar.HOSE, testEnd, 250)
+	if !reflect.DeepEqual(a, b) {
+		t.Fatal("same seed and symbol produced different series")
+	}
+
+	// A shorter request is the tail of a longer one
+	tail := NewGenerator(7).Daily("VNM", calendar.HOSE, testEnd, 30)
+	if !reflect.DeepEqual(tail, a[len(a)-30:]) {
+		t.Error("series depends on the number of days requested")
+	}
+
+	if other := NewGenerator(7).Daily("FPT", calendar.HOSE, testEnd, 250); other[0].Close == a[0].Close {
+		t.Error("different symbols start at the same price")
+	}
+	if other := NewGenerator(8).Daily("VNM", calendar.HOSE, testEnd, 250); reflect.DeepEqual(other, a) {
+		t.Error("different seeds produced the same series")
+	}
+}
+
+func TestDailyRespectsExchangeRules(t *testing.T) {
+	for _, exchange := range []calendar.Exchange{calendar.HOSE, calendar.HNX, calendar.UPCOM} {
+		bars := NewGenerator(1).Daily("HPG", exchange, testEnd, 2000)
+		if len(bars) != 2000 {
+			t.Fatalf("%s: got %d bars, want 2000", exchange, len(bars))
+		}
+
+		limitDays, locked := 0, 0
+		for i, b := range bars {
+			if err := b.Validate(); err != nil {
+				t.Fatalf("%s %s: %v", exchange, b.Date.Format("2006-01-02"), err)
+			}
+			if !calendar.IsTradingDay(b.Date) {
+				t.Fatalf("%s: bar on non-trading day %s", exchange, b.Date.Format("2006-01-02"))
+			}
+			for _, p := range []float64{b.Open, b.High, b.Low, b.Close} {
+				if p != rules.RoundToTick(exchange, vnstock.InstrumentStock, p) {
+					t.Fatalf("%s %s: price %v is not on a tick", exchange, b.Date.Format("2006-01-02"), p)
+				}
+			}
+			if i == 0 {
+				continue
+			}
+
+			limits := rules.Limits(exchange, vnstock.InstrumentStock, bars[i-1].Close)
+			if b.High > limits.Ceiling || b.Low < limits.Floor {
+				t.Fatalf("%s %s: range %v-%v outside band %v-%v", exchange, b.Date.Format("2006-01-02"), b.Low, b.High, limits.Floor, limits.Ceiling)
+			}
+			a := limits.Assess(b)
+			if a.LimitUp || a.LimitDown {
+				limitDays++
+			}
+			if a.Locked {
+				locked++
+			}
+		}
+		if limitDays == 0 || locked == 0 {
+			t.Errorf("%s: got %d limit days, %d locked, want some of each", exchange, limitDays, locked)
+		}
+	}
+}
+
+func TestDailyVolumeFollowsMoves(t *testing.T) {
+	bars := NewGenerator(3).Daily("SSI", calendar.HOSE, testEnd, 2000)
+
+	// Correlation of volume with the absolute daily return
+	var moves, volumes []float64
+	for i := 1; i < len(bars); i++ {
+		moves = append(moves, math.Abs(math.Log(bars[i].Close/bars[i-1].Close)))
+		volumes = append(volumes, float64(bars[i].Volume))
+	}
+	if c := correlation(moves, volumes); c < 0.3 {
+		t.Errorf("volume/move correlation = %.2f, want at least 0.3", c)
+	}
+
+	// Volatility clustering: absolute returns are autocorrelated
+	if c := correlation(moves[1:], moves[:len(moves)-1]); c < 0.1 {
+		t.Errorf("absolute return autocorrelation = %.2f, want at least 0.1", c)
+	}
+}
+
+func TestIntradayAggregatesToDaily(t *testing.T) {
+	p := NewProvider(Options{Seed: 5, End: testEnd})
+	ctx := context.Background()
+
+	for _, symbol := range []string{"FPT", vnstock.IndexVNIndex} {
+		daily, err := p.GetHistoricalData(ctx, symbol, vnstock.Resolution1D, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		minutes, err := p.GetHistoricalData(ctx, symbol, vnstock.Resolution1m, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resampled, err := vnstock.Resample(minutes, vnstock.Resolution1D)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(resampled) != len(daily) {
+			t.Fatalf("%s: got %d resampled days, want %d", symbol, len(resampled), len(daily))
+		}
+		for i, d := range daily {
+			r := resampled[i]
+			if r.Open != d.Open || r.High != d.High || r.Low != d.Low || r.Close != d.Close || r.Volume != d.Volume {
+				t.Errorf("%s %s: resampled %+v, want %+v", symbol, d.Date.Format("2006-01-02"), r, d)
+			}
+		}
+	}
+}
+
+func TestFlowsFollowDaily(t *testing.T) {
+	gen := NewGenerator(3)
+	bars := gen.Daily("VNM", calendar.HOSE, testEnd, 500)
+	flows := gen.Flows("VNM", calendar.HOSE, testEnd, 500)
+	if len(flows) != len(bars) {
+		t.Fatalf("got %d flows for %d bars", len(flows), len(bars))
+	}
+
+	var upNet, downNet float64
+	for i, f := range flows {
+		b := bars[i]
+		if !f.Date.Equal(b.Date) {
+			t.Fatalf("flow %d dated %v, bar dated %v", i, f.Date, b.Date)
+		}
+		if f.ForeignBuyVolume+f.ForeignSellVolume > b.Volume || f.ProprietaryBuyVolume+f.ProprietarySellVolume > b.Volume {
+			t.Errorf("%v: flows %+v exceed volume %d", f.Date, f, b.Volume)
+		}
+		if f.ForeignRoom < 0 {
+			t.Errorf("%v: negative foreign room %d", f.Date, f.ForeignRoom)
+		}
+		if i > 0 {
+			if room := flows[i-1].ForeignRoom - f.ForeignNetVolume(); room != f.ForeignRoom {
+				t.Errorf("%v: room %d, want %d after net buying %d", f.Date, f.ForeignRoom, room, f.ForeignNetVolume())
+			}
+			if b.Close > bars[i-1].Close {
+				upNet += f.ForeignNetValue()
+			} else if b.Close < bars[i-1].Close {
+				downNet += f.ForeignNetValue()
+			}
+		}
+	}
+
+	// Foreigners lean with the trend
+	if upNet <= 0 || downNet >= 0 {
+		t.Errorf("foreign net value %v on up days, %v on down days", upNet, downNet)
+	}
+}
+
+func TestFinancialsValueRealistically(t *testing.T) {
+	gen := NewGenerator(5)
+	for _, symbol := range []string{"VNM", "FPT", "HPG", "SSI"} {
+		quarters := gen.Financials(symbol, calendar.HOSE, testEnd, vnstock.PeriodQuarter)
+		years := gen.Financials(symbol, calendar.HOSE, testEnd, vnstock.PeriodYear)
+
+		// Q1/2025 is out by the end of June, the 2024 audited report by the end of March
+		if last := quarters[len(quarters)-1]; last.Label() != "Q1/2025" {
+			t.Errorf("%s: latest quarter %s, want Q1/2025", symbol, last.Label())
+		}
+		last := years[len(years)-1]
+		if last.Label() != "2024" {
+			t.Fatalf("%s: latest year %s, want 2024", symbol, last.Label())
+		}
+		var netIncome float64
+		for _, q := range quarters {
+			if q.Year == 2024 {
+				netIncome += q.Income.NetIncome
+			}
+		}
+		if last.Income.NetIncome != netIncome {
+			t.Errorf("%s: 2024 net income %v, quarters sum to %v", symbol, last.Income.NetIncome, netIncome)
+		}
+
+		bars := gen.Daily(symbol, calendar.HOSE, testEnd, 1)
+		v, err := vnstock.ComputeValuation(append(quarters, years...), bars[0].Close)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.PE < 3 || v.PE > 60 || v.PB < 0.2 || v.PB > 15 || v.DebtToEquity <= 0 {
+			t.Errorf("%s: unrealistic valuation %+v", symbol, v)
+		}
+	}
+}
+
+func TestProviderRegistered(t *testing.T) {
+	provider, err := vnstock.NewProvider(vnstock.ProviderConfig{Type: ProviderType, Seed: 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := provider.(*Provider); !ok {
+		t.Fatalf("got %T, want *Provider", provider)
+	}
+
+	sources, err := vnstock.ParseSources("synthetic=synthetic:42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sources[0].Seed != 42 {
+		t.Errorf("got seed %d, want 42", sources[0].Seed)
+	}
+}
+
+func correlation(x, y []float64) float64 {
+	n := float64(len(x))
+	var sx, sy, sxx, syy, sxy float64
+	for i := range x {
+		sx += x[i]
+		sy += y[i]
+		sxx += x[i] * x[i]
+		syy += y[i] * y[i]
+		sxy += x[i] * y[i]
+	}
+	return (sxy - sx*sy/n) / math.Sqrt((sxx-sx*sx/n)*(syy-sy*sy/n))
+}
@@ -0,0 +1,100 @@
+package vnstock
+
+import (
+	"sort"
+	"time"
+)
+
+// CorporateActionType identifies the kind of corporate action
+type CorporateActionType string
+
+// Supported corporate action types
+const (
+	ActionCashDividend  CorporateActionType = "cash_dividend"
+	ActionStockDividend CorporateActionType = "stock_dividend"
+	ActionRightsIssue   CorporateActionType = "rights_issue"
+	ActionSplit         CorporateActionType = "split"
+)
+
+// CorporateAction is an event that changes the price of a share on its ex-date.
+//
+// Ratio is the number of new shares per existing share: 0.15 for a 100:15
+// stock dividend or bonus issue, 0.3 for a 10:3 rights issue and 2 for a
+// 2-for-1 split. CashAmount is the dividend in VND per share and Price the
+// subscription price of a rights issue.
+type CorporateAction struct {
+	Type       CorporateActionType `json:"type"`
+	ExDate     time.Time           `json:"ex_date"`
+	Ratio      float64             `json:"ratio"`
+	CashAmount float64             `json:"cash_amount"`
+	Price      float64             `json:"price"`
+}
+
+// PriceFactor returns the multiplier applied to prices before the ex-date,
+// given the last close before it
+func (a CorporateAction) PriceFactor(prevClose float64) float64 {
+	switch a.Type {
+	case ActionCashDividend:
+		if prevClose > a.CashAmount && a.CashAmount > 0 {
+			return (prevClose - a.CashAmount) / prevClose
+		}
+	case ActionStockDividend:
+		if a.Ratio > 0 {
+			return 1 / (1 + a.Ratio)
+		}
+	case ActionSplit:
+		if a.Ratio > 0 {
+			return 1 / a.Ratio
+		}
+	case ActionRightsIssue:
+		// Rights priced at or above the market are worthless and leave no gap
+		if a.Ratio > 0 && a.Price < prevClose {
+			exPrice := (prevClose + a.Ratio*a.Price) / (1 + a.Ratio)
+			return exPrice / prevClose
+		}
+	}
+	return 1
+}
+
+// volumeFactor returns the multiplier applied to volumes before the ex-date
+func (a CorporateAction) volumeFactor() float64 {
+	switch a.Type {
+	case ActionStockDividend:
+		return 1 + a.Ratio
+	case ActionSplit:
+		return a.Ratio
+	}
+	return 1
+}
+
+// AdjustPrices returns a back-adjusted copy of bars: prices before each
+// ex-date are scaled so that the action leaves no artificial gap, and volumes
+// are scaled by the change in share count. The latest bars keep their raw
+// prices. Bars must be sorted oldest first.
+func AdjustPrices(bars []OHLCV, actions []CorporateAction) []OHLCV {
+	adjusted := make([]OHLCV, len(bars))
+	copy(adjusted, bars)
+
+	// Factors are computed from raw closes, then applied cumulatively
+	for _, a := range actions {
+		exDay := BucketStart(a.ExDate, Resolution1D)
+		idx := sort.Search(len(bars), func(i int) bool {
+			return !BucketStart(bars[i].Date, Resolution1D).Before(exDay)
+		})
+		if idx == 0 || idx == len(bars) {
+			continue
+		}
+
+		priceFactor := a.PriceFactor(bars[idx-1].Close)
+		volumeFactor := a.volumeFactor()
+		for i := 0; i < idx; i++ {
+			adjusted[i].Open *= priceFactor
+			adjusted[i].High *= priceFactor
+			adjusted[i].Low *= priceFactor
+			adjusted[i].Close *= priceFactor
+			adjusted[i].Volume = int64(float64(adjusted[i].Volume) * volumeFactor)
+		}
+	}
+
+	return adjusted
+}
@@ -0,0 +1,61 @@
+package vnstock
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func dailyBar(day int, close float64, volume int64) OHLCV {
+	return OHLCV{
+		Date:   time.Date(2024, 6, day, 0, 0, 0, 0, MarketLocation),
+		Open:   close,
+		High:   close,
+		Low:    close,
+		Close:  close,
+		Volume: volume,
+	}
+}
+
+func TestAdjustPrices(t *testing.T) {
+	bars := []OHLCV{
+		dailyBar(3, 60000, 1000),
+		dailyBar(4, 60000, 1000),
+		dailyBar(5, 50000, 1200), // ex-date of a 100:20 stock dividend
+		dailyBar(6, 51000, 1200),
+	}
+	actions := []CorporateAction{
+		{Type: ActionStockDividend, ExDate: time.Date(2024, 6, 5, 0, 0, 0, 0, MarketLocation), Ratio: 0.2},
+		{Type: ActionCashDividend, ExDate: time.Date(2024, 6, 6, 0, 0, 0, 0, MarketLocation), CashAmount: 1000},
+	}
+
+	adjusted := AdjustPrices(bars, actions)
+
+	// Stock dividend: 60000 / 1.2 = 50000, then cash dividend: * (50000-1000)/50000
+	expected := []float64{49000, 49000, 49000, 51000}
+	for i, e := range expected {
+		if math.Abs(adjusted[i].Close-e) > 1e-6 {
+			t.Errorf("adjusted[%d].Close = %v, expected %v", i, adjusted[i].Close, e)
+		}
+	}
+	if adjusted[0].Volume != 1200 {
+		t.Errorf("adjusted[0].Volume = %d, expected 1200", adjusted[0].Volume)
+	}
+	if bars[0].Close != 60000 {
+		t.Error("AdjustPrices modified its input")
+	}
+}
+
+func TestRightsIssueFactor(t *testing.T) {
+	// 10:3 rights at 10,000 with a 23,000 close: ex-price (23000 + 0.3*10000) / 1.3 = 20000
+	action := CorporateAction{Type: ActionRightsIssue, Ratio: 0.3, Price: 10000}
+	if f := action.PriceFactor(23000); math.Abs(f-20000.0/23000.0) > 1e-9 {
+		t.Errorf("PriceFactor = %v, expected %v", f, 20000.0/23000.0)
+	}
+
+	// Rights above the market price leave no gap
+	action.Price = 30000
+	if f := action.PriceFactor(23000); f != 1 {
+		t.Errorf("PriceFactor = %v, expected 1", f)
+	}
+}
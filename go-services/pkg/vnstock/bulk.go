@@ -0,0 +1,171 @@
+package vnstock
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BarRecord is an OHLCV bar tagged with its symbol, as found in bulk price files
+type BarRecord struct {
+	Symbol string `json:"symbol"`
+	OHLCV
+}
+
+// Bulk file fields
+const (
+	FieldSymbol = "symbol"
+	FieldDate   = "date"
+	FieldOpen   = "open"
+	FieldHigh   = "high"
+	FieldLow    = "low"
+	FieldClose  = "close"
+	FieldVolume = "volume"
+	FieldValue  = "value"
+)
+
+// ColumnMapping maps bulk file fields to the column names used in a file
+type ColumnMapping map[string]string
+
+// columnAliases lists the column names recognized for each field when no
+// explicit mapping is given. Names are compared after normalizeColumn.
+var columnAliases = map[string][]string{
+	FieldSymbol: {"symbol", "ticker", "code", "machungkhoan", "mack"},
+	FieldDate:   {"date", "time", "tradingdate", "ngay"},
+	FieldOpen:   {"open", "giamocua"},
+	FieldHigh:   {"high", "giacaonhat"},
+	FieldLow:    {"low", "giathapnhat"},
+	FieldClose:  {"close", "giadongcua"},
+	FieldVolume: {"volume", "khoiluong", "klkhoplenh"},
+	FieldValue:  {"value", "giatri", "gtkhoplenh", "tradingvalue"},
+}
+
+var requiredFields = []string{FieldDate, FieldOpen, FieldHigh, FieldLow, FieldClose}
+
+// ParseColumnMapping parses a mapping of the form "date=Ngay,close=GiaDongCua"
+func ParseColumnMapping(s string) (ColumnMapping, error) {
+	mapping := ColumnMapping{}
+	if strings.TrimSpace(s) == "" {
+		return mapping, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		field, column, ok := strings.Cut(pair, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		column = strings.TrimSpace(column)
+		if !ok || column == "" {
+			return nil, fmt.Errorf("invalid column mapping %q, expected field=column", pair)
+		}
+		if _, known := columnAliases[field]; !known {
+			return nil, fmt.Errorf("unknown field %q in column mapping", field)
+		}
+		mapping[field] = column
+	}
+
+	return mapping, nil
+}
+
+// resolveColumns returns the index of each field within columns
+func resolveColumns(columns []string, mapping ColumnMapping) (map[string]int, error) {
+	index := make(map[string]int)
+
+	for field, aliases := range columnAliases {
+		if column, ok := mapping[field]; ok {
+			aliases = []string{column}
+		}
+		for i, c := range columns {
+			if matchesAny(c, aliases) {
+				index[field] = i
+				break
+			}
+		}
+		if _, ok := mapping[field]; ok {
+			if _, found := index[field]; !found {
+				return nil, fmt.Errorf("column %q mapped to %s not found", mapping[field], field)
+			}
+		}
+	}
+
+	for _, field := range requiredFields {
+		if _, ok := index[field]; !ok {
+			return nil, fmt.Errorf("missing %s column", field)
+		}
+	}
+
+	return index, nil
+}
+
+func matchesAny(column string, aliases []string) bool {
+	c := normalizeColumn(column)
+	for _, a := range aliases {
+		if c == normalizeColumn(a) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeColumn lowercases a column name and drops separators
+func normalizeColumn(s string) string {
+	return strings.NewReplacer(" ", "", "_", "", "-", "", ".", "").Replace(strings.ToLower(strings.TrimSpace(s)))
+}
+
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+	"02/01/2006",
+	"20060102",
+}
+
+// parseDate parses the date formats commonly found in VN price files.
+// Times without a zone are taken to be in market time.
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, MarketLocation); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", s)
+}
+
+// formatDate formats daily bars as a plain date and intraday bars as RFC 3339
+func formatDate(t time.Time) string {
+	t = t.In(MarketLocation)
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format(time.RFC3339)
+}
+
+// parseNumber parses a number that may use a comma as thousands separator
+func parseNumber(s string) (float64, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// Validate checks the internal consistency of a bar
+func (b OHLCV) Validate() error {
+	switch {
+	case b.Date.IsZero():
+		return fmt.Errorf("missing date")
+	case b.Open <= 0 || b.High <= 0 || b.Low <= 0 || b.Close <= 0:
+		return fmt.Errorf("non-positive price")
+	case b.High < b.Low:
+		return fmt.Errorf("high %.2f below low %.2f", b.High, b.Low)
+	case b.High < b.Open || b.High < b.Close:
+		return fmt.Errorf("high %.2f below open or close", b.High)
+	case b.Low > b.Open || b.Low > b.Close:
+		return fmt.Errorf("low %.2f above open or close", b.Low)
+	case b.Volume < 0:
+		return fmt.Errorf("negative volume")
+	case b.Value < 0:
+		return fmt.Errorf("negative value")
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package vnstock
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadCSVColumnMapping(t *testing.T) {
+	input := "Ngay,GiaMoCua,GiaCaoNhat,GiaThapNhat,GiaDongCua,KhoiLuong\n" +
+		"02/01/2024,\"85,000\",86500,84800,86000,\"1,250,000\"\n" +
+		"03/01/2024,86000,87000,85500,86800,980000\n"
+
+	records, err := ReadCSV(strings.NewReader(input), nil)
+	if err != nil {
+		t.Fatalf("ReadCSV failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, expected 2", len(records))
+	}
+
+	first := records[0]
+	if !first.Date.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, MarketLocation)) {
+		t.Errorf("Date = %v, expected 2024-01-02", first.Date)
+	}
+	if first.Open != 85000 || first.Volume != 1250000 {
+		t.Errorf("Open = %v, Volume = %v, expected 85000 and 1250000", first.Open, first.Volume)
+	}
+
+	// An explicit mapping overrides the aliases
+	input = "d,o,h,l,c\n2024-01-02,10,12,9,11\n"
+	mapping, err := ParseColumnMapping("date=d,open=o,high=h,low=l,close=c")
+	if err != nil {
+		t.Fatalf("ParseColumnMapping failed: %v", err)
+	}
+	records, err = ReadCSV(strings.NewReader(input), mapping)
+	if err != nil {
+		t.Fatalf("ReadCSV with mapping failed: %v", err)
+	}
+	if records[0].Close != 11 {
+		t.Errorf("Close = %v, expected 11", records[0].Close)
+	}
+}
+
+func TestValidateOHLC(t *testing.T) {
+	date := time.Date(2024, 1, 2, 0, 0, 0, 0, MarketLocation)
+
+	valid := OHLCV{Date: date, Open: 10, High: 12, Low: 9, Close: 11, Volume: 100}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("valid bar rejected: %v", err)
+	}
+
+	invalid := []OHLCV{
+		{Date: date, Open: 10, High: 9, Low: 12, Close: 11},
+		{Date: date, Open: 13, High: 12, Low: 9, Close: 11},
+		{Date: date, Open: 10, High: 12, Low: 9, Close: 0},
+		{Open: 10, High: 12, Low: 9, Close: 11},
+	}
+	for _, b := range invalid {
+		if err := b.Validate(); err == nil {
+			t.Errorf("invalid bar %+v accepted", b)
+		}
+	}
+}
+
+func TestBulkRoundTrip(t *testing.T) {
+	records := []BarRecord{
+		{Symbol: "FPT", OHLCV: OHLCV{Date: time.Date(2024, 1, 2, 0, 0, 0, 0, MarketLocation), Open: 95000, High: 96500, Low: 94800, Close: 96000, Volume: 1250000}},
+		{Symbol: "FPT", OHLCV: OHLCV{Date: time.Date(2024, 1, 3, 0, 0, 0, 0, MarketLocation), Open: 96000, High: 97000, Low: 95500, Close: 96800, Volume: 980000}},
+	}
+
+	var csvBuf bytes.Buffer
+	if err := WriteCSV(&csvBuf, records); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	fromCSV, err := ReadCSV(&csvBuf, nil)
+	if err != nil {
+		t.Fatalf("ReadCSV failed: %v", err)
+	}
+
+	var parquetBuf bytes.Buffer
+	if err := WriteParquet(&parquetBuf, records); err != nil {
+		t.Fatalf("WriteParquet failed: %v", err)
+	}
+	fromParquet, err := ReadParquet(bytes.NewReader(parquetBuf.Bytes()), int64(parquetBuf.Len()), nil)
+	if err != nil {
+		t.Fatalf("ReadParquet failed: %v", err)
+	}
+
+	for name, got := range map[string][]BarRecord{"csv": fromCSV, "parquet": fromParquet} {
+		if len(got) != len(records) {
+			t.Fatalf("%s: got %d records, expected %d", name, len(got), len(records))
+		}
+		for i := range records {
+			if got[i].Symbol != records[i].Symbol || !got[i].Date.Equal(records[i].Date) ||
+				got[i].Close != records[i].Close || got[i].Volume != records[i].Volume {
+				t.Errorf("%s: record %d = %+v, expected %+v", name, i, got[i], records[i])
+			}
+		}
+	}
+}
@@ -3,9 +3,14 @@ package vnstock
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sync"
 	"time"
+
+	"vnstock-hybrid/pkg/calendar"
 )
 
 // OHLCV represents a single candlestick data point
@@ -16,48 +21,222 @@ type OHLCV struct {
 	Low    float64   `json:"low"`
 	Close  float64   `json:"close"`
 	Volume int64     `json:"volume"`
+	// Value is the traded value in VND. Index series report it in place of a
+	// meaningful volume.
+	Value float64 `json:"value,omitempty"`
+	// Source names the provider the bar came from, when known
+	Source string `json:"source,omitempty"`
 }
 
 // Client provides access to Vietnamese stock market data
 type Client struct {
 	httpClient *http.Client
 	baseURL    string
+	retry      RetryPolicy
+	limiter    *RateLimiter
+
+	breakerThreshold int
+	breakerCooldown  time.Duration
+	breakersMu       sync.Mutex
+	breakers         map[string]*CircuitBreaker
+}
+
+// ClientOptions configures the resilience of a Client
+type ClientOptions struct {
+	// Timeout bounds each attempt, not the whole call with its retries
+	Timeout time.Duration
+	Retry   RetryPolicy
+	// BreakerThreshold consecutive failures open a host's circuit breaker for
+	// BreakerCooldown; a zero threshold disables the breaker
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	// RateLimit caps outbound requests per second, with bursts of RateBurst;
+	// zero disables limiting
+	RateLimit float64
+	RateBurst int
+}
+
+// DefaultClientOptions are used by NewClient and NewClientWithConfig
+var DefaultClientOptions = ClientOptions{
+	Timeout:          30 * time.Second,
+	Retry:            DefaultRetryPolicy,
+	BreakerThreshold: 5,
+	BreakerCooldown:  30 * time.Second,
+	RateLimit:        10,
+	RateBurst:        5,
 }
 
 // NewClient creates a new vnstock client
 func NewClient() *Client {
-	return &Client{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		baseURL: "https://api.vietstock.vn/finance",
-	}
+	return NewClientWithOptions("https://api.vietstock.vn/finance", DefaultClientOptions)
 }
 
 // NewClientWithConfig creates a client with custom configuration
 func NewClientWithConfig(baseURL string, timeout time.Duration) *Client {
-	return &Client{
+	opts := DefaultClientOptions
+	opts.Timeout = timeout
+	return NewClientWithOptions(baseURL, opts)
+}
+
+// NewClientWithOptions creates a client with custom retry, breaker and rate limit settings
+func NewClientWithOptions(baseURL string, opts ClientOptions) *Client {
+	if opts.Retry.MaxAttempts < 1 {
+		opts.Retry.MaxAttempts = 1
+	}
+
+	c := &Client{
 		httpClient: &http.Client{
-			Timeout: timeout,
+			Timeout: opts.Timeout,
 		},
-		baseURL: baseURL,
+		baseURL:          baseURL,
+		retry:            opts.Retry,
+		breakerThreshold: opts.BreakerThreshold,
+		breakerCooldown:  opts.BreakerCooldown,
+		breakers:         make(map[string]*CircuitBreaker),
 	}
+	if opts.RateLimit > 0 {
+		c.limiter = NewRateLimiter(opts.RateLimit, opts.RateBurst)
+	}
+	return c
 }
 
-// GetHistoricalData fetches historical OHLCV data for a symbol
-func (c *Client) GetHistoricalData(ctx context.Context, symbol string, days int) ([]OHLCV, error) {
-	endDate := time.Now()
-	startDate := endDate.AddDate(0, 0, -days)
+// GetHistoricalData fetches historical OHLCV data for a symbol covering the
+// last days trading days
+func (c *Client) GetHistoricalData(ctx context.Context, symbol string, resolution Resolution, days int) ([]OHLCV, error) {
+	endDate := time.Now().In(MarketLocation)
+	startDate := calendar.AddTradingDays(endDate, -days)
 
 	// Format dates for API
 	start := startDate.Format("2006-01-02")
 	end := endDate.Format("2006-01-02")
 
-	url := fmt.Sprintf("%s/histdata/%s?from=%s&to=%s", c.baseURL, symbol, start, end)
+	url := fmt.Sprintf("%s/histdata/%s?from=%s&to=%s&resolution=%s", c.baseURL, symbol, start, end, resolution)
+
+	var data []OHLCV
+	if err := c.getJSON(ctx, url, &data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// GetLatestQuote fetches the latest quote for a symbol
+func (c *Client) GetLatestQuote(ctx context.Context, symbol string) (*Quote, error) {
+	url := fmt.Sprintf("%s/quote/%s", c.baseURL, symbol)
+
+	var quote Quote
+	if err := c.getJSON(ctx, url, &quote); err != nil {
+		return nil, err
+	}
+	if quote.Symbol == "" {
+		quote.Symbol = symbol
+	}
+
+	return &quote, nil
+}
+
+// ListSymbols fetches the symbols listed on an exchange
+func (c *Client) ListSymbols(ctx context.Context, exchange string) ([]SymbolInfo, error) {
+	url := fmt.Sprintf("%s/symbols", c.baseURL)
+	if exchange != "" {
+		url += "?exchange=" + exchange
+	}
+
+	var symbols []SymbolInfo
+	if err := c.getJSON(ctx, url, &symbols); err != nil {
+		return nil, err
+	}
+
+	return symbols, nil
+}
+
+// BreakerStates returns the circuit breaker state of every host contacted so far
+func (c *Client) BreakerStates() map[string]BreakerState {
+	c.breakersMu.Lock()
+	defer c.breakersMu.Unlock()
 
+	states := make(map[string]BreakerState, len(c.breakers))
+	for host, b := range c.breakers {
+		states[host] = b.State()
+	}
+	return states
+}
+
+// breakerFor returns the circuit breaker of the host serving rawURL
+func (c *Client) breakerFor(rawURL string) *CircuitBreaker {
+	host := rawURL
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	c.breakersMu.Lock()
+	defer c.breakersMu.Unlock()
+
+	b, ok := c.breakers[host]
+	if !ok {
+		b = NewCircuitBreaker(c.breakerThreshold, c.breakerCooldown)
+		c.breakers[host] = b
+	}
+	return b
+}
+
+// getJSON performs a GET request and decodes the JSON response into out.
+// Timeouts, network errors, 429 and 5xx responses count against the host's
+// circuit breaker and are retried with jittered exponential
+// backoff, or after the delay requested by Retry-After.
+func (c *Client) getJSON(ctx context.Context, url string, out interface{}) error {
+	breaker := c.breakerFor(url)
+
+	for attempt := 0; ; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		if c.breakerThreshold > 0 {
+			if err := breaker.Allow(); err != nil {
+				return err
+			}
+		}
+
+		err := c.fetchJSON(ctx, url, out)
+		if err != nil && ctx.Err() != nil {
+			// The caller gave up; the host may be fine
+			breaker.Abandon()
+			return err
+		}
+		if err == nil || !retryable(err) {
+			// The host answered; a 4xx is our problem, not the host's
+			breaker.Success()
+			return err
+		}
+		breaker.Failure()
+
+		if attempt+1 >= c.retry.MaxAttempts {
+			if attempt > 0 {
+				return fmt.Errorf("giving up after %d attempts: %w", attempt+1, err)
+			}
+			return err
+		}
+
+		delay := c.retry.backoff(attempt)
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+			delay = statusErr.RetryAfter
+			if c.retry.MaxDelay > 0 && delay > c.retry.MaxDelay {
+				delay = c.retry.MaxDelay
+			}
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// fetchJSON makes a single GET attempt
+func (c *Client) fetchJSON(ctx context.Context, url string, out interface{}) error {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("User-Agent", "VNStock-Hybrid/1.0")
@@ -65,51 +244,30 @@ func (c *Client) GetHistoricalData(ctx context.Context, symbol string, days int)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch data: %w", err)
+		return fmt.Errorf("failed to fetch data: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+		return &StatusError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
 	}
 
-	var data []OHLCV
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return data, nil
+	return nil
 }
 
 // GetMockData returns mock historical data for testing
+//
+// Deprecated: the series is the same for every symbol. Use the synthetic
+// provider, which generates a distinct seeded series per symbol.
 func (c *Client) GetMockData(symbol string, days int) []OHLCV {
-	data := make([]OHLCV, days)
-	basePrice := 50000.0 // Base price in VND
-
-	for i := 0; i < days; i++ {
-		date := time.Now().AddDate(0, 0, -days+i+1)
-
-		// Generate somewhat realistic price movement
-		change := (float64(i%10) - 5) * 100
-		open := basePrice + change
-		close := open + (float64(i%5)-2)*50
-		high := max(open, close) + float64(i%3)*30
-		low := min(open, close) - float64(i%3)*30
-		volume := int64(1000000 + (i%10)*100000)
-
-		data[i] = OHLCV{
-			Date:   date,
-			Open:   open,
-			High:   high,
-			Low:    low,
-			Close:  close,
-			Volume: volume,
-		}
-
-		basePrice = close // Use close as next day's base
-	}
-
-	return data
+	return generateMockData(Resolution1D, days)
 }
 
 func max(a, b float64) float64 {
@@ -0,0 +1,253 @@
+package vnstock
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testOptions retries quickly and never rate limits
+var testOptions = ClientOptions{
+	Timeout:          time.Second,
+	Retry:            RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond},
+	BreakerThreshold: 10,
+	BreakerCooldown:  time.Minute,
+}
+
+func TestClientRetriesServerErrors(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"symbol":"FPT","price":120000}`))
+	}))
+	defer srv.Close()
+
+	client := NewClientWithOptions(srv.URL, testOptions)
+	quote, err := client.GetLatestQuote(context.Background(), "FPT")
+	if err != nil {
+		t.Fatalf("GetLatestQuote failed: %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); quote.Price != 120000 || n != 3 {
+		t.Errorf("got price %.0f after %d calls, want 120000 after 3", quote.Price, n)
+	}
+}
+
+func TestClientDoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	client := NewClientWithOptions(srv.URL, testOptions)
+	_, err := client.GetLatestQuote(context.Background(), "XXX")
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 StatusError, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("404 was attempted %d times, want 1", n)
+	}
+}
+
+func TestClientHonorsRetryAfter(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"symbol":"FPT"}`))
+	}))
+	defer srv.Close()
+
+	opts := testOptions
+	opts.Retry.MaxDelay = 2 * time.Second
+	client := NewClientWithOptions(srv.URL, opts)
+
+	start := time.Now()
+	if _, err := client.GetLatestQuote(context.Background(), "FPT"); err != nil {
+		t.Fatalf("GetLatestQuote failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want at least the 1s requested by Retry-After", elapsed)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+
+	if got := parseRetryAfter("7", now); got != 7*time.Second {
+		t.Errorf("seconds form: got %s", got)
+	}
+	if got := parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now); got != 90*time.Second {
+		t.Errorf("date form: got %s", got)
+	}
+	if got := parseRetryAfter("soon", now); got != 0 {
+		t.Errorf("invalid value: got %s", got)
+	}
+}
+
+func TestClientCircuitBreaker(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	opts := testOptions
+	opts.Retry.MaxAttempts = 1
+	opts.BreakerThreshold = 2
+	client := NewClientWithOptions(srv.URL, opts)
+	ctx := context.Background()
+
+	client.GetLatestQuote(ctx, "FPT")
+	client.GetLatestQuote(ctx, "FPT")
+	_, err := client.GetLatestQuote(ctx, "FPT")
+
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen after 2 failures, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("server called %d times, want 2", n)
+	}
+	for host, state := range client.BreakerStates() {
+		if state != BreakerOpen {
+			t.Errorf("breaker for %s is %s, want open", host, state)
+		}
+	}
+}
+
+// slowServer answers after delay, or when the client hangs up
+func slowServer(calls *int32, delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		select {
+		case <-time.After(delay):
+			w.Write([]byte(`{"symbol":"FPT","price":120000}`))
+		case <-r.Context().Done():
+		}
+	}))
+}
+
+func TestClientRetriesTimeoutsAndOpensBreaker(t *testing.T) {
+	var calls int32
+	srv := slowServer(&calls, time.Second)
+	defer srv.Close()
+
+	opts := testOptions
+	opts.Timeout = 20 * time.Millisecond
+	opts.BreakerThreshold = 3
+	client := NewClientWithOptions(srv.URL, opts)
+	ctx := context.Background()
+
+	if _, err := client.GetLatestQuote(ctx, "FPT"); err == nil {
+		t.Fatal("expected a timeout error")
+	}
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Errorf("server called %d times, want 3 attempts", n)
+	}
+	if _, err := client.GetLatestQuote(ctx, "FPT"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen after 3 timeouts, got %v", err)
+	}
+}
+
+func TestClientCancellationLeavesBreaker(t *testing.T) {
+	var calls int32
+	srv := slowServer(&calls, time.Second)
+	defer srv.Close()
+
+	opts := testOptions
+	opts.BreakerThreshold = 1
+	client := NewClientWithOptions(srv.URL, opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetLatestQuote(ctx, "FPT"); err == nil {
+		t.Fatal("expected a cancellation error")
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("server called %d times after cancellation, want 1", n)
+	}
+	for host, state := range client.BreakerStates() {
+		if state != BreakerClosed {
+			t.Errorf("breaker for %s is %s after cancellation, want closed", host, state)
+		}
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	now := time.Now()
+	b := NewCircuitBreaker(1, time.Minute)
+	b.now = func() time.Time { return now }
+
+	b.Failure()
+	if b.Allow() == nil {
+		t.Fatal("open breaker allowed a call")
+	}
+
+	now = now.Add(time.Minute)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("breaker did not let a probe through after the cooldown: %v", err)
+	}
+	if b.Allow() == nil {
+		t.Error("half-open breaker allowed a second concurrent probe")
+	}
+
+	b.Success()
+	if b.State() != BreakerClosed {
+		t.Errorf("state after successful probe = %s, want closed", b.State())
+	}
+}
+
+func TestCircuitBreakerAbandonedProbe(t *testing.T) {
+	now := time.Now()
+	b := NewCircuitBreaker(1, time.Minute)
+	b.now = func() time.Time { return now }
+
+	b.Failure()
+	now = now.Add(time.Minute)
+	b.Allow()
+	b.Abandon()
+
+	if b.State() != BreakerHalfOpen {
+		t.Errorf("state after abandoned probe = %s, want half_open", b.State())
+	}
+	if err := b.Allow(); err != nil {
+		t.Errorf("breaker did not let a new probe through after an abandoned one: %v", err)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(50, 1)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The first request uses the burst, the next three wait 20ms each
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("4 requests at 50/s took %s, want at least 50ms", elapsed)
+	}
+}
+
+func TestMockProviderNegativeDays(t *testing.T) {
+	data, err := NewMockProvider().GetHistoricalData(context.Background(), "FPT", Resolution1D, -5)
+	if err != nil || len(data) != 0 {
+		t.Errorf("got %d bars, %v for -5 days, want none", len(data), err)
+	}
+}
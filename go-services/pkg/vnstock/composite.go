@@ -0,0 +1,296 @@
+package vnstock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NamedProvider is a market data source. Its name is recorded as the Source
+// of the bars it supplies through a CompositeProvider.
+type NamedProvider struct {
+	Name     string
+	Provider MarketDataProvider
+}
+
+// Composite provider modes
+const (
+	// CompositeFailover uses the first source that answers
+	CompositeFailover = "failover"
+	// CompositeReconcile also queries the other sources and compares their bars
+	CompositeReconcile = "reconcile"
+)
+
+// CompositeOptions configures a CompositeProvider
+type CompositeOptions struct {
+	Mode string
+	// CloseTolerance and VolumeTolerance are the largest differences, in
+	// percent of the primary value, not reported as a mismatch
+	CloseTolerance  float64
+	VolumeTolerance float64
+	// OnMismatch, if set, receives the mismatches found for each request
+	OnMismatch func([]Mismatch)
+}
+
+// Mismatch is a bar on which two sources disagree beyond tolerance
+type Mismatch struct {
+	Symbol         string     `json:"symbol"`
+	Resolution     Resolution `json:"resolution"`
+	Date           time.Time  `json:"date"`
+	Field          string     `json:"field"`
+	Source         string     `json:"source"`
+	Reference      string     `json:"reference"`
+	SourceValue    float64    `json:"source_value"`
+	ReferenceValue float64    `json:"reference_value"`
+	DiffPercent    float64    `json:"diff_percent"`
+}
+
+// CompositeProvider queries a primary source and falls back to secondaries
+// in order. Bars it returns carry the name of the source they came from.
+type CompositeProvider struct {
+	sources []NamedProvider
+	opts    CompositeOptions
+}
+
+// NewCompositeProvider creates a provider over sources, the first being the primary
+func NewCompositeProvider(sources []NamedProvider, opts CompositeOptions) (*CompositeProvider, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("composite provider requires at least one source")
+	}
+	if opts.Mode == "" {
+		opts.Mode = CompositeFailover
+	}
+	if opts.Mode != CompositeFailover && opts.Mode != CompositeReconcile {
+		return nil, fmt.Errorf("unknown composite mode %q", opts.Mode)
+	}
+	return &CompositeProvider{sources: sources, opts: opts}, nil
+}
+
+// sourceResult is the answer of one source to a history request
+type sourceResult struct {
+	bars []OHLCV
+	err  error
+}
+
+// GetHistoricalData returns the bars of the first source that has data. In
+// reconcile mode every source is queried and the others are compared with it.
+func (p *CompositeProvider) GetHistoricalData(ctx context.Context, symbol string, resolution Resolution, days int) ([]OHLCV, error) {
+	if p.opts.Mode != CompositeReconcile {
+		var errs []error
+		for _, src := range p.sources {
+			bars, err := fetchHistory(ctx, src, symbol, resolution, days)
+			if err == nil {
+				return bars, nil
+			}
+			errs = append(errs, err)
+		}
+		return nil, errors.Join(errs...)
+	}
+
+	results := make([]sourceResult, len(p.sources))
+	var wg sync.WaitGroup
+	for i, src := range p.sources {
+		wg.Add(1)
+		go func(i int, src NamedProvider) {
+			defer wg.Done()
+			bars, err := fetchHistory(ctx, src, symbol, resolution, days)
+			results[i] = sourceResult{bars: bars, err: err}
+		}(i, src)
+	}
+	wg.Wait()
+
+	chosen := -1
+	var errs []error
+	for i, r := range results {
+		if r.err != nil {
+			errs = append(errs, r.err)
+			continue
+		}
+		if chosen < 0 {
+			chosen = i
+		}
+	}
+	if chosen < 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	var mismatches []Mismatch
+	for i, r := range results {
+		if i == chosen || r.err != nil {
+			continue
+		}
+		mismatches = append(mismatches, p.compare(symbol, resolution, p.sources[chosen].Name, results[chosen].bars, p.sources[i].Name, r.bars)...)
+	}
+	if len(mismatches) > 0 {
+		log.Printf("Warning: %d mismatched %s %s bars across market data sources", len(mismatches), symbol, resolution)
+		if p.opts.OnMismatch != nil {
+			p.opts.OnMismatch(mismatches)
+		}
+	}
+
+	return results[chosen].bars, nil
+}
+
+// fetchHistory queries one source and tags its bars with the source name
+func fetchHistory(ctx context.Context, src NamedProvider, symbol string, resolution Resolution, days int) ([]OHLCV, error) {
+	bars, err := src.Provider.GetHistoricalData(ctx, symbol, resolution, days)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", src.Name, err)
+	}
+	if len(bars) == 0 {
+		return nil, fmt.Errorf("%s: no data for symbol %s", src.Name, symbol)
+	}
+
+	for i := range bars {
+		bars[i].Source = src.Name
+	}
+	return bars, nil
+}
+
+// compare reports the bars of other whose close or volume differ from the
+// reference bars by more than the tolerance. Bars present in only one source
+// are not compared.
+func (p *CompositeProvider) compare(symbol string, resolution Resolution, refName string, ref []OHLCV, otherName string, other []OHLCV) []Mismatch {
+	byDate := make(map[int64]OHLCV, len(other))
+	for _, b := range other {
+		byDate[b.Date.Unix()] = b
+	}
+
+	var mismatches []Mismatch
+	for _, r := range ref {
+		o, ok := byDate[r.Date.Unix()]
+		if !ok {
+			continue
+		}
+
+		fields := []struct {
+			name      string
+			ref, val  float64
+			tolerance float64
+		}{
+			{FieldClose, r.Close, o.Close, p.opts.CloseTolerance},
+			{FieldVolume, float64(r.Volume), float64(o.Volume), p.opts.VolumeTolerance},
+		}
+		for _, f := range fields {
+			diff := diffPercent(f.ref, f.val)
+			if diff <= f.tolerance {
+				continue
+			}
+			mismatches = append(mismatches, Mismatch{
+				Symbol:         symbol,
+				Resolution:     resolution,
+				Date:           r.Date,
+				Field:          f.name,
+				Source:         otherName,
+				Reference:      refName,
+				SourceValue:    f.val,
+				ReferenceValue: f.ref,
+				DiffPercent:    diff,
+			})
+		}
+	}
+	return mismatches
+}
+
+// diffPercent returns the absolute difference between value and ref in percent of ref
+func diffPercent(ref, value float64) float64 {
+	if ref == 0 {
+		if value == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return math.Abs(value-ref) / math.Abs(ref) * 100
+}
+
+// GetLatestQuote returns the quote of the first source that answers
+func (p *CompositeProvider) GetLatestQuote(ctx context.Context, symbol string) (*Quote, error) {
+	var errs []error
+	for _, src := range p.sources {
+		quote, err := src.Provider.GetLatestQuote(ctx, symbol)
+		if err == nil {
+			return quote, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", src.Name, err))
+	}
+	return nil, errors.Join(errs...)
+}
+
+// ListSymbols returns the symbol list of the first source that answers
+func (p *CompositeProvider) ListSymbols(ctx context.Context, exchange string) ([]SymbolInfo, error) {
+	var errs []error
+	for _, src := range p.sources {
+		symbols, err := src.Provider.ListSymbols(ctx, exchange)
+		if err == nil {
+			return symbols, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", src.Name, err))
+	}
+	return nil, errors.Join(errs...)
+}
+
+// BreakerStates reports the circuit breakers of all sources, keyed by source and host
+func (p *CompositeProvider) BreakerStates() map[string]BreakerState {
+	states := make(map[string]BreakerState)
+	for _, src := range p.sources {
+		if reporter, ok := src.Provider.(BreakerReporter); ok {
+			for host, state := range reporter.BreakerStates() {
+				states[src.Name+"/"+host] = state
+			}
+		}
+	}
+	return states
+}
+
+// ParseSources parses a composite source list of the form
+// "vietstock=http:https://api.vietstock.vn/finance,local=file:./data/market".
+// Each entry names a source and gives its provider type and, for http and
+// file sources, the base URL or data directory. Registered generated
+// providers take an optional seed, as in "synthetic=synthetic:42".
+func ParseSources(s string) ([]ProviderConfig, error) {
+	var sources []ProviderConfig
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, spec, ok := strings.Cut(entry, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid source %q, expected name=type[:target]", entry)
+		}
+		typ, target, _ := strings.Cut(spec, ":")
+
+		cfg := ProviderConfig{Name: name, Type: typ}
+		switch typ {
+		case ProviderHTTP:
+			cfg.BaseURL = target
+		case ProviderFile:
+			cfg.DataDir = target
+		case ProviderMock:
+		default:
+			if _, ok := providerFactories[typ]; !ok {
+				return nil, fmt.Errorf("unsupported source type %q in %q", typ, entry)
+			}
+			if target != "" {
+				seed, err := strconv.ParseInt(target, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid seed %q in %q", target, entry)
+				}
+				cfg.Seed = seed
+			}
+		}
+		sources = append(sources, cfg)
+	}
+
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no market data sources configured")
+	}
+	return sources, nil
+}
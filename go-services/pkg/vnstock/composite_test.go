@@ -0,0 +1,136 @@
+package vnstock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// staticProvider serves fixed bars, or fails with err
+type staticProvider struct {
+	bars []OHLCV
+	err  error
+}
+
+func (p *staticProvider) GetHistoricalData(ctx context.Context, symbol string, resolution Resolution, days int) ([]OHLCV, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	bars := make([]OHLCV, len(p.bars))
+	copy(bars, p.bars)
+	return bars, nil
+}
+
+func (p *staticProvider) GetLatestQuote(ctx context.Context, symbol string) (*Quote, error) {
+	return nil, p.err
+}
+
+func (p *staticProvider) ListSymbols(ctx context.Context, exchange string) ([]SymbolInfo, error) {
+	return nil, p.err
+}
+
+func (p *staticProvider) GetFlows(ctx context.Context, symbol string, days int) ([]Flow, error) {
+	return nil, p.err
+}
+
+func compositeBars(closes ...float64) []OHLCV {
+	start := time.Date(2025, 3, 3, 0, 0, 0, 0, MarketLocation)
+	bars := make([]OHLCV, len(closes))
+	for i, c := range closes {
+		bars[i] = OHLCV{Date: start.AddDate(0, 0, i), Open: c, High: c, Low: c, Close: c, Volume: 1000}
+	}
+	return bars
+}
+
+func TestCompositeFailover(t *testing.T) {
+	composite, err := NewCompositeProvider([]NamedProvider{
+		{Name: "primary", Provider: &staticProvider{err: errors.New("down")}},
+		{Name: "backup", Provider: &staticProvider{bars: compositeBars(100, 101)}},
+	}, CompositeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bars, err := composite.GetHistoricalData(context.Background(), "FPT", Resolution1D, 2)
+	if err != nil {
+		t.Fatalf("failover failed: %v", err)
+	}
+	if len(bars) != 2 || bars[0].Source != "backup" {
+		t.Errorf("got %d bars from %q, want 2 from backup", len(bars), bars[0].Source)
+	}
+}
+
+func TestCompositeFlowsErrors(t *testing.T) {
+	failing, err := NewCompositeProvider([]NamedProvider{
+		{Name: "primary", Provider: &staticProvider{err: errors.New("down")}},
+	}, CompositeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := failing.GetFlows(context.Background(), "FPT", 5); err == nil || errors.Is(err, ErrNoFlows) {
+		t.Errorf("got %v from a failing source, want its error without ErrNoFlows", err)
+	}
+
+	empty, err := NewCompositeProvider([]NamedProvider{
+		{Name: "primary", Provider: &staticProvider{err: ErrNoFlows}},
+		{Name: "backup", Provider: &staticProvider{}},
+	}, CompositeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := empty.GetFlows(context.Background(), "FPT", 5); !errors.Is(err, ErrNoFlows) {
+		t.Errorf("got %v from sources without flows, want ErrNoFlows", err)
+	}
+}
+
+func TestCompositeReconcile(t *testing.T) {
+	secondary := compositeBars(100, 101, 110)
+	secondary[1].Volume = 1020 // within the 5% volume tolerance
+	secondary[2].Volume = 2000
+
+	var reported []Mismatch
+	composite, err := NewCompositeProvider([]NamedProvider{
+		{Name: "primary", Provider: &staticProvider{bars: compositeBars(100, 101, 102)}},
+		{Name: "secondary", Provider: &staticProvider{bars: secondary}},
+	}, CompositeOptions{
+		Mode:            CompositeReconcile,
+		CloseTolerance:  0.5,
+		VolumeTolerance: 5,
+		OnMismatch:      func(m []Mismatch) { reported = m },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bars, err := composite.GetHistoricalData(context.Background(), "FPT", Resolution1D, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bars[2].Close != 102 || bars[2].Source != "primary" {
+		t.Errorf("reconcile returned close %.0f from %q, want the primary's 102", bars[2].Close, bars[2].Source)
+	}
+
+	if len(reported) != 2 {
+		t.Fatalf("got %d mismatches, want close and volume on the last bar: %+v", len(reported), reported)
+	}
+	for _, m := range reported {
+		if !m.Date.Equal(bars[2].Date) || m.Source != "secondary" || m.Reference != "primary" {
+			t.Errorf("unexpected mismatch %+v", m)
+		}
+	}
+}
+
+func TestParseSources(t *testing.T) {
+	sources, err := ParseSources("vietstock=http:https://api.vietstock.vn/finance, local=file:./data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sources) != 2 || sources[0].BaseURL != "https://api.vietstock.vn/finance" || sources[1].DataDir != "./data" {
+		t.Errorf("unexpected sources %+v", sources)
+	}
+
+	if _, err := ParseSources("broken"); err == nil {
+		t.Error("expected an error for an entry without a name")
+	}
+}
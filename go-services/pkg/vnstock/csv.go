@@ -0,0 +1,113 @@
+package vnstock
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ReadCSV reads bars from a CSV file with a header row.
+// Columns are located through mapping, falling back to common column names.
+func ReadCSV(r io.Reader, mapping ColumnMapping) ([]BarRecord, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	index, err := resolveColumns(header, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []BarRecord
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		record, err := parseCSVRow(row, index)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func parseCSVRow(row []string, index map[string]int) (BarRecord, error) {
+	var record BarRecord
+	var err error
+
+	if i, ok := index[FieldSymbol]; ok {
+		record.Symbol = row[i]
+	}
+
+	if record.Date, err = parseDate(row[index[FieldDate]]); err != nil {
+		return record, err
+	}
+
+	prices := map[string]*float64{
+		FieldOpen:  &record.Open,
+		FieldHigh:  &record.High,
+		FieldLow:   &record.Low,
+		FieldClose: &record.Close,
+	}
+	for field, dst := range prices {
+		if *dst, err = parseNumber(row[index[field]]); err != nil {
+			return record, fmt.Errorf("invalid %s: %w", field, err)
+		}
+	}
+
+	if i, ok := index[FieldVolume]; ok {
+		volume, err := parseNumber(row[i])
+		if err != nil {
+			return record, fmt.Errorf("invalid volume: %w", err)
+		}
+		record.Volume = int64(volume)
+	}
+
+	if i, ok := index[FieldValue]; ok {
+		if record.Value, err = parseNumber(row[i]); err != nil {
+			return record, fmt.Errorf("invalid value: %w", err)
+		}
+	}
+
+	return record, nil
+}
+
+// WriteCSV writes bars as CSV with a symbol,date,open,high,low,close,volume,value header
+func WriteCSV(w io.Writer, records []BarRecord) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write([]string{FieldSymbol, FieldDate, FieldOpen, FieldHigh, FieldLow, FieldClose, FieldVolume, FieldValue}); err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		row := []string{
+			r.Symbol,
+			formatDate(r.Date),
+			strconv.FormatFloat(r.Open, 'f', -1, 64),
+			strconv.FormatFloat(r.High, 'f', -1, 64),
+			strconv.FormatFloat(r.Low, 'f', -1, 64),
+			strconv.FormatFloat(r.Close, 'f', -1, 64),
+			strconv.FormatInt(r.Volume, 10),
+			strconv.FormatFloat(r.Value, 'f', -1, 64),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,180 @@
+package vnstock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"vnstock-hybrid/pkg/calendar"
+)
+
+// FileProvider serves market data from files on disk.
+//
+// Each symbol is stored as <dir>/<SYMBOL>.json containing an array of OHLCV
+// bars, or as <dir>/<SYMBOL>.csv in the format read by ReadCSV. Daily bars use
+// the bare symbol as file name, other resolutions are stored as
+// <SYMBOL>_<resolution>; weekly and monthly bars fall back to resampling the
+// daily file. An optional
+// <dir>/symbols.json holds the SymbolInfo list; without it the symbol list is
+// derived from the file names.
+type FileProvider struct {
+	dir string
+}
+
+// NewFileProvider creates a provider reading from dir
+func NewFileProvider(dir string) *FileProvider {
+	return &FileProvider{dir: dir}
+}
+
+// GetHistoricalData returns the bars of the last days trading days in the file
+func (p *FileProvider) GetHistoricalData(ctx context.Context, symbol string, resolution Resolution, days int) ([]OHLCV, error) {
+	data, err := p.loadResolution(symbol, resolution)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return data, nil
+	}
+
+	// Files are static, so the window is anchored on the last stored bar
+	// rather than on the current time to keep results reproducible.
+	first := calendar.AddTradingDays(data[len(data)-1].Date, 1-days)
+	start := sort.Search(len(data), func(i int) bool {
+		return !data[i].Date.Before(first)
+	})
+
+	return data[start:], nil
+}
+
+// GetLatestQuote returns a quote built from the last bar in the file
+func (p *FileProvider) GetLatestQuote(ctx context.Context, symbol string) (*Quote, error) {
+	data, err := p.load(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no data for symbol %s", symbol)
+	}
+
+	latest := data[len(data)-1]
+	quote := &Quote{
+		Symbol: symbol,
+		Time:   latest.Date,
+		Price:  latest.Close,
+		Open:   latest.Open,
+		High:   latest.High,
+		Low:    latest.Low,
+		Volume: latest.Volume,
+		Value:  latest.Value,
+	}
+	if len(data) > 1 {
+		quote.ReferencePrice = data[len(data)-2].Close
+	}
+
+	return quote, nil
+}
+
+// ListSymbols returns the symbols available in the data directory
+func (p *FileProvider) ListSymbols(ctx context.Context, exchange string) ([]SymbolInfo, error) {
+	raw, err := os.ReadFile(filepath.Join(p.dir, "symbols.json"))
+	if err == nil {
+		var symbols []SymbolInfo
+		if err := json.Unmarshal(raw, &symbols); err != nil {
+			return nil, fmt.Errorf("failed to decode symbols.json: %w", err)
+		}
+		return filterSymbols(symbols, exchange), nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read symbols.json: %w", err)
+	}
+
+	// Exchange is unknown without symbols.json, so no filtering is possible
+	entries, err := os.ReadDir(p.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var symbols []SymbolInfo
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		name := strings.TrimSuffix(e.Name(), ext)
+		if e.IsDir() || (ext != ".json" && ext != ".csv") || strings.Contains(name, "_") || name == "symbols" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		symbols = append(symbols, SymbolInfo{Symbol: name})
+	}
+
+	return symbols, nil
+}
+
+// loadResolution reads the bars of a symbol at the given resolution
+func (p *FileProvider) loadResolution(symbol string, resolution Resolution) ([]OHLCV, error) {
+	if resolution == Resolution1D {
+		return p.load(symbol)
+	}
+
+	data, err := p.load(symbol + "_" + string(resolution))
+	if err == nil || !resolution.CoarserThan(Resolution1D) {
+		return data, err
+	}
+
+	daily, err := p.load(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return Resample(daily, resolution)
+}
+
+// load reads and sorts all bars stored under a file name
+func (p *FileProvider) load(name string) ([]OHLCV, error) {
+	var data []OHLCV
+
+	raw, err := os.ReadFile(filepath.Join(p.dir, name+".json"))
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return nil, fmt.Errorf("failed to decode data for %s: %w", name, err)
+		}
+	case os.IsNotExist(err):
+		if data, err = p.loadCSV(name); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("failed to read data for %s: %w", name, err)
+	}
+
+	sort.Slice(data, func(i, j int) bool {
+		return data[i].Date.Before(data[j].Date)
+	})
+
+	return data, nil
+}
+
+// loadCSV reads the bars stored in <dir>/<name>.csv
+func (p *FileProvider) loadCSV(name string) ([]OHLCV, error) {
+	f, err := os.Open(filepath.Join(p.dir, name+".csv"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no data for %s", name)
+		}
+		return nil, fmt.Errorf("failed to read data for %s: %w", name, err)
+	}
+	defer f.Close()
+
+	records, err := ReadCSV(f, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode data for %s: %w", name, err)
+	}
+
+	data := make([]OHLCV, len(records))
+	for i, r := range records {
+		data[i] = r.OHLCV
+	}
+	return data, nil
+}
@@ -0,0 +1,131 @@
+package vnstock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"vnstock-hybrid/pkg/calendar"
+)
+
+// Flow is one session's trading in a symbol by foreign investors and by the
+// proprietary desks of securities companies. Values are in VND.
+type Flow struct {
+	Date time.Time `json:"date"`
+
+	ForeignBuyVolume  int64   `json:"foreign_buy_volume"`
+	ForeignSellVolume int64   `json:"foreign_sell_volume"`
+	ForeignBuyValue   float64 `json:"foreign_buy_value"`
+	ForeignSellValue  float64 `json:"foreign_sell_value"`
+	// ForeignRoom is the number of shares foreigners may still buy under the
+	// foreign ownership limit after the session
+	ForeignRoom int64 `json:"foreign_room"`
+
+	ProprietaryBuyVolume  int64   `json:"proprietary_buy_volume"`
+	ProprietarySellVolume int64   `json:"proprietary_sell_volume"`
+	ProprietaryBuyValue   float64 `json:"proprietary_buy_value"`
+	ProprietarySellValue  float64 `json:"proprietary_sell_value"`
+}
+
+// ForeignNetVolume returns the shares foreigners bought minus those they sold
+func (f Flow) ForeignNetVolume() int64 {
+	return f.ForeignBuyVolume - f.ForeignSellVolume
+}
+
+// ForeignNetValue returns the value foreigners bought minus the value they sold
+func (f Flow) ForeignNetValue() float64 {
+	return f.ForeignBuyValue - f.ForeignSellValue
+}
+
+// ProprietaryNetValue returns the value proprietary desks bought minus the value they sold
+func (f Flow) ProprietaryNetValue() float64 {
+	return f.ProprietaryBuyValue - f.ProprietarySellValue
+}
+
+// FlowProvider is implemented by providers that publish investor flows
+type FlowProvider interface {
+	// GetFlows returns the daily flows of a stock over the last days trading days, oldest first
+	GetFlows(ctx context.Context, symbol string, days int) ([]Flow, error)
+}
+
+// ErrNoFlows is returned for symbols without investor flows, such as indices,
+// and by providers that cannot supply them
+var ErrNoFlows = errors.New("investor flows not available")
+
+// GetFlows fetches the daily foreign and proprietary flows of a stock
+func (c *Client) GetFlows(ctx context.Context, symbol string, days int) ([]Flow, error) {
+	if IsIndex(symbol) {
+		return nil, ErrNoFlows
+	}
+
+	endDate := time.Now().In(MarketLocation)
+	startDate := calendar.AddTradingDays(endDate, -days)
+
+	url := fmt.Sprintf("%s/flows/%s?from=%s&to=%s", c.baseURL, symbol, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+
+	var flows []Flow
+	if err := c.getJSON(ctx, url, &flows); err != nil {
+		return nil, err
+	}
+
+	return flows, nil
+}
+
+// GetFlows returns the flows of the first source that has them. It returns
+// ErrNoFlows only if no source failed; otherwise the sources' errors.
+func (p *CompositeProvider) GetFlows(ctx context.Context, symbol string, days int) ([]Flow, error) {
+	var errs []error
+	for _, src := range p.sources {
+		fp, ok := src.Provider.(FlowProvider)
+		if !ok {
+			continue
+		}
+		flows, err := fp.GetFlows(ctx, symbol, days)
+		if err == nil && len(flows) > 0 {
+			return flows, nil
+		}
+		if err != nil && !errors.Is(err, ErrNoFlows) {
+			errs = append(errs, fmt.Errorf("%s: %w", src.Name, err))
+		}
+	}
+	if len(errs) == 0 {
+		return nil, ErrNoFlows
+	}
+	return nil, errors.Join(errs...)
+}
+
+// GetFlows returns generated flows: foreigners net buying on up days and
+// net selling on down days, against a fixed room
+func (p *MockProvider) GetFlows(ctx context.Context, symbol string, days int) ([]Flow, error) {
+	if IsIndex(symbol) {
+		return nil, ErrNoFlows
+	}
+
+	bars := generateMockData(Resolution1D, days)
+	flows := make([]Flow, len(bars))
+	room := int64(50000000)
+	for i, b := range bars {
+		buy, sell := b.Volume/5, b.Volume/5
+		if b.Close > b.Open {
+			buy += b.Volume / 20
+		} else if b.Close < b.Open {
+			sell += b.Volume / 20
+		}
+		room -= buy - sell
+
+		flows[i] = Flow{
+			Date:                  b.Date,
+			ForeignBuyVolume:      buy,
+			ForeignSellVolume:     sell,
+			ForeignBuyValue:       float64(buy) * b.Close,
+			ForeignSellValue:      float64(sell) * b.Close,
+			ForeignRoom:           room,
+			ProprietaryBuyVolume:  b.Volume / 20,
+			ProprietarySellVolume: b.Volume / 20,
+			ProprietaryBuyValue:   float64(b.Volume/20) * b.Close,
+			ProprietarySellValue:  float64(b.Volume/20) * b.Close,
+		}
+	}
+	return flows, nil
+}
@@ -0,0 +1,232 @@
+package vnstock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Period is the length of a financial reporting period
+type Period string
+
+const (
+	PeriodQuarter Period = "quarter"
+	PeriodYear    Period = "year"
+)
+
+// IncomeStatement holds the results of a reporting period, in VND
+type IncomeStatement struct {
+	Revenue         float64 `json:"revenue"`
+	GrossProfit     float64 `json:"gross_profit"`
+	OperatingProfit float64 `json:"operating_profit"`
+	// NetIncome is the profit after tax attributable to the parent company's shareholders
+	NetIncome float64 `json:"net_income"`
+}
+
+// BalanceSheet holds the financial position at the end of a reporting period, in VND
+type BalanceSheet struct {
+	TotalAssets      float64 `json:"total_assets"`
+	TotalLiabilities float64 `json:"total_liabilities"`
+	// TotalDebt is short and long term borrowings, a part of TotalLiabilities
+	TotalDebt float64 `json:"total_debt"`
+	Equity    float64 `json:"equity"`
+}
+
+// CashFlowStatement holds the cash flows of a reporting period, in VND
+type CashFlowStatement struct {
+	OperatingCashFlow float64 `json:"operating_cash_flow"`
+	InvestingCashFlow float64 `json:"investing_cash_flow"`
+	FinancingCashFlow float64 `json:"financing_cash_flow"`
+	// CapitalExpenditure is the cash spent on fixed assets, as a positive amount
+	CapitalExpenditure float64 `json:"capital_expenditure"`
+}
+
+// FinancialReport is a company's financial statements for one fiscal quarter
+// or year
+type FinancialReport struct {
+	Period Period `json:"period"`
+	Year   int    `json:"year"`
+	// Quarter is 1-4 for quarterly reports and 0 for annual ones
+	Quarter int `json:"quarter"`
+
+	Income   IncomeStatement   `json:"income"`
+	Balance  BalanceSheet      `json:"balance"`
+	CashFlow CashFlowStatement `json:"cash_flow"`
+	// SharesOutstanding is the number of shares at the end of the period
+	SharesOutstanding int64 `json:"shares_outstanding"`
+}
+
+// Label returns the fiscal period of the report, such as "Q2/2025" or "2024"
+func (r FinancialReport) Label() string {
+	if r.Period == PeriodQuarter {
+		return fmt.Sprintf("Q%d/%d", r.Quarter, r.Year)
+	}
+	return fmt.Sprintf("%d", r.Year)
+}
+
+// FundamentalsProvider is implemented by providers that publish financial statements
+type FundamentalsProvider interface {
+	// GetFinancials returns the reports of a stock for the given period, oldest first
+	GetFinancials(ctx context.Context, symbol string, period Period) ([]FinancialReport, error)
+}
+
+// ErrNoFinancials is returned for symbols without financial statements, such
+// as indices, and by providers that cannot supply them
+var ErrNoFinancials = errors.New("financial statements not available")
+
+// ParsePeriod validates a reporting period
+func ParsePeriod(s string) (Period, error) {
+	switch p := Period(s); p {
+	case PeriodQuarter, PeriodYear:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid period %q, expected quarter or year", s)
+}
+
+// Valuation holds per-share figures and ratios of a stock at a price. Ratios
+// that cannot be computed, such as P/E on a loss, are zero.
+type Valuation struct {
+	Price float64 `json:"price"`
+	// Basis is the fiscal period of the latest report used, see FinancialReport.Label
+	Basis             string  `json:"basis"`
+	SharesOutstanding int64   `json:"shares_outstanding"`
+	MarketCap         float64 `json:"market_cap"`
+	// EPS is the trailing twelve months earnings per share
+	EPS               float64 `json:"eps"`
+	BookValuePerShare float64 `json:"book_value_per_share"`
+	PE                float64 `json:"pe"`
+	PB                float64 `json:"pb"`
+	// ROE is trailing twelve months net income over average equity, in percent
+	ROE          float64 `json:"roe"`
+	DebtToEquity float64 `json:"debt_to_equity"`
+}
+
+// ComputeValuation values a stock at price from its reports. Trailing twelve
+// months figures use the last four quarters when available and fall back to
+// the latest annual report.
+func ComputeValuation(reports []FinancialReport, price float64) (*Valuation, error) {
+	var quarters, years []FinancialReport
+	for _, r := range reports {
+		if r.Period == PeriodQuarter {
+			quarters = append(quarters, r)
+		} else {
+			years = append(years, r)
+		}
+	}
+	sortReports(quarters)
+	sortReports(years)
+
+	// The trailing year and the balance sheets at its start and end
+	var latest FinancialReport
+	var netIncome, openingEquity float64
+	switch {
+	case len(quarters) >= 4 && consecutive(quarters[len(quarters)-4:]):
+		ttm := quarters[len(quarters)-4:]
+		latest = ttm[3]
+		for _, q := range ttm {
+			netIncome += q.Income.NetIncome
+		}
+		openingEquity = ttm[0].Balance.Equity
+		if len(quarters) >= 5 {
+			openingEquity = quarters[len(quarters)-5].Balance.Equity
+		}
+	case len(years) > 0:
+		latest = years[len(years)-1]
+		netIncome = latest.Income.NetIncome
+		openingEquity = latest.Balance.Equity
+		if len(years) >= 2 {
+			openingEquity = years[len(years)-2].Balance.Equity
+		}
+	default:
+		return nil, ErrNoFinancials
+	}
+	if latest.SharesOutstanding <= 0 {
+		return nil, fmt.Errorf("report %s has no shares outstanding", latest.Label())
+	}
+
+	shares := float64(latest.SharesOutstanding)
+	equity := latest.Balance.Equity
+	v := &Valuation{
+		Price:             price,
+		Basis:             latest.Label(),
+		SharesOutstanding: latest.SharesOutstanding,
+		MarketCap:         price * shares,
+		EPS:               netIncome / shares,
+		BookValuePerShare: equity / shares,
+	}
+	if v.EPS > 0 {
+		v.PE = price / v.EPS
+	}
+	if v.BookValuePerShare > 0 {
+		v.PB = price / v.BookValuePerShare
+	}
+	if avgEquity := (openingEquity + equity) / 2; avgEquity > 0 {
+		v.ROE = netIncome / avgEquity * 100
+	}
+	if equity > 0 {
+		v.DebtToEquity = latest.Balance.TotalDebt / equity
+	}
+	return v, nil
+}
+
+// sortReports orders reports of one period oldest first
+func sortReports(reports []FinancialReport) {
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Year != reports[j].Year {
+			return reports[i].Year < reports[j].Year
+		}
+		return reports[i].Quarter < reports[j].Quarter
+	})
+}
+
+// consecutive reports whether sorted quarterly reports follow each other without gaps
+func consecutive(quarters []FinancialReport) bool {
+	for i := 1; i < len(quarters); i++ {
+		prev, cur := quarters[i-1], quarters[i]
+		if cur.Year*4+cur.Quarter != prev.Year*4+prev.Quarter+1 {
+			return false
+		}
+	}
+	return true
+}
+
+// GetFinancials fetches the financial statements of a stock
+func (c *Client) GetFinancials(ctx context.Context, symbol string, period Period) ([]FinancialReport, error) {
+	if IsIndex(symbol) {
+		return nil, ErrNoFinancials
+	}
+
+	url := fmt.Sprintf("%s/financials/%s?period=%s", c.baseURL, symbol, period)
+
+	var reports []FinancialReport
+	if err := c.getJSON(ctx, url, &reports); err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// GetFinancials returns the reports of the first source that has them. It
+// returns ErrNoFinancials only if no source failed; otherwise the sources'
+// errors.
+func (p *CompositeProvider) GetFinancials(ctx context.Context, symbol string, period Period) ([]FinancialReport, error) {
+	var errs []error
+	for _, src := range p.sources {
+		fp, ok := src.Provider.(FundamentalsProvider)
+		if !ok {
+			continue
+		}
+		reports, err := fp.GetFinancials(ctx, symbol, period)
+		if err == nil && len(reports) > 0 {
+			return reports, nil
+		}
+		if err != nil && !errors.Is(err, ErrNoFinancials) {
+			errs = append(errs, fmt.Errorf("%s: %w", src.Name, err))
+		}
+	}
+	if len(errs) == 0 {
+		return nil, ErrNoFinancials
+	}
+	return nil, errors.Join(errs...)
+}
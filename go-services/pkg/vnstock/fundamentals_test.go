@@ -0,0 +1,73 @@
+package vnstock
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func quarterReport(year, quarter int, netIncome, equity float64) FinancialReport {
+	return FinancialReport{
+		Period:            PeriodQuarter,
+		Year:              year,
+		Quarter:           quarter,
+		Income:            IncomeStatement{NetIncome: netIncome},
+		Balance:           BalanceSheet{Equity: equity, TotalDebt: equity / 2},
+		SharesOutstanding: 1000000,
+	}
+}
+
+func TestComputeValuation(t *testing.T) {
+	// Out of order on purpose
+	reports := []FinancialReport{
+		quarterReport(2025, 1, 1e9, 42e9),
+		quarterReport(2024, 2, 1e9, 38e9),
+		quarterReport(2024, 4, 2e9, 41e9),
+		quarterReport(2024, 1, 1e9, 37e9),
+		quarterReport(2024, 3, 1e9, 39e9),
+		{Period: PeriodYear, Year: 2024, Income: IncomeStatement{NetIncome: 5e9}, Balance: BalanceSheet{Equity: 41e9}, SharesOutstanding: 1000000},
+	}
+
+	v, err := ComputeValuation(reports, 60000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// TTM: Q2/2024-Q1/2025, equity averaged with Q1/2024
+	checks := []struct {
+		name      string
+		got, want float64
+	}{
+		{"EPS", v.EPS, 5000},
+		{"BVPS", v.BookValuePerShare, 42000},
+		{"PE", v.PE, 12},
+		{"PB", v.PB, 60000.0 / 42000},
+		{"ROE", v.ROE, 5e9 / 39.5e9 * 100},
+		{"DebtToEquity", v.DebtToEquity, 0.5},
+		{"MarketCap", v.MarketCap, 60e9},
+	}
+	for _, c := range checks {
+		if math.Abs(c.got-c.want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+	if v.Basis != "Q1/2025" {
+		t.Errorf("basis = %s, want Q1/2025", v.Basis)
+	}
+
+	// A missing quarter falls back to the annual report
+	v, err = ComputeValuation(append(reports[1:4:4], reports[5]), 60000)
+	if err != nil || v.Basis != "2024" || v.EPS != 5000 {
+		t.Errorf("got %+v, %v, want 2024 annual EPS 5000", v, err)
+	}
+
+	// No P/E on a loss
+	loss := []FinancialReport{{Period: PeriodYear, Year: 2024, Income: IncomeStatement{NetIncome: -1e9}, Balance: BalanceSheet{Equity: 10e9}, SharesOutstanding: 1000000}}
+	if v, err := ComputeValuation(loss, 10000); err != nil || v.PE != 0 || v.EPS != -1000 {
+		t.Errorf("got %+v, %v, want EPS -1000 and no P/E", v, err)
+	}
+
+	if _, err := ComputeValuation(nil, 10000); !errors.Is(err, ErrNoFinancials) {
+		t.Errorf("got %v, want ErrNoFinancials", err)
+	}
+}
@@ -0,0 +1,59 @@
+package vnstock
+
+import "vnstock-hybrid/pkg/calendar"
+
+// Market index symbols
+const (
+	IndexVNIndex    = "VNINDEX"
+	IndexVN30       = "VN30"
+	IndexHNXIndex   = "HNXINDEX"
+	IndexHNX30      = "HNX30"
+	IndexUPCOMIndex = "UPCOMINDEX"
+)
+
+// indexExchanges maps each supported index to the exchange it tracks
+var indexExchanges = map[string]calendar.Exchange{
+	IndexVNIndex:    calendar.HOSE,
+	IndexVN30:       calendar.HOSE,
+	IndexHNXIndex:   calendar.HNX,
+	IndexHNX30:      calendar.HNX,
+	IndexUPCOMIndex: calendar.UPCOM,
+}
+
+// indexNames are the display names of the supported indices
+var indexNames = map[string]string{
+	IndexVNIndex:    "VN-Index",
+	IndexVN30:       "VN30-Index",
+	IndexHNXIndex:   "HNX-Index",
+	IndexHNX30:      "HNX30-Index",
+	IndexUPCOMIndex: "UPCoM-Index",
+}
+
+// IndexSymbols lists the supported market indices
+var IndexSymbols = []string{IndexVNIndex, IndexVN30, IndexHNXIndex, IndexHNX30, IndexUPCOMIndex}
+
+// IsIndex reports whether symbol is a market index rather than a security
+func IsIndex(symbol string) bool {
+	_, ok := indexExchanges[symbol]
+	return ok
+}
+
+// IndexExchange returns the exchange tracked by an index
+func IndexExchange(symbol string) (calendar.Exchange, bool) {
+	exchange, ok := indexExchanges[symbol]
+	return exchange, ok
+}
+
+// IndexInstruments returns the instrument master entries of the supported indices
+func IndexInstruments() []SymbolInfo {
+	infos := make([]SymbolInfo, len(IndexSymbols))
+	for i, symbol := range IndexSymbols {
+		infos[i] = SymbolInfo{
+			Symbol:   symbol,
+			Name:     indexNames[symbol],
+			Exchange: string(indexExchanges[symbol]),
+			Type:     InstrumentIndex,
+		}
+	}
+	return infos
+}
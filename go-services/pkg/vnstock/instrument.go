@@ -0,0 +1,89 @@
+package vnstock
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// InstrumentType is the kind of security a symbol refers to
+type InstrumentType string
+
+const (
+	InstrumentStock InstrumentType = "stock"
+	InstrumentETF   InstrumentType = "etf"
+	// InstrumentCW is a covered warrant
+	InstrumentCW     InstrumentType = "cw"
+	InstrumentFuture InstrumentType = "future"
+	InstrumentIndex  InstrumentType = "index"
+	InstrumentBond   InstrumentType = "bond"
+)
+
+// InstrumentTypes lists all instrument types
+var InstrumentTypes = []InstrumentType{InstrumentStock, InstrumentETF, InstrumentCW, InstrumentFuture, InstrumentIndex, InstrumentBond}
+
+// ParseInstrumentType validates an instrument type
+func ParseInstrumentType(s string) (InstrumentType, error) {
+	for _, t := range InstrumentTypes {
+		if InstrumentType(s) == t {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("invalid instrument type %q, expected stock, etf, cw, future, index or bond", s)
+}
+
+// HasPriceBand reports whether the instrument trades within the daily price
+// limits of its exchange. The band of a covered warrant follows its underlying
+// through the conversion ratio, which is not known here.
+func (t InstrumentType) HasPriceBand() bool {
+	return t == InstrumentStock || t == InstrumentETF
+}
+
+// HasTickSize reports whether the instrument is quoted in VND ticks; indices,
+// futures and bonds are quoted in points
+func (t InstrumentType) HasTickSize() bool {
+	return t == InstrumentStock || t == InstrumentETF || t == InstrumentCW
+}
+
+// Symbol formats of the instruments traded in Vietnam
+var (
+	stockPattern = regexp.MustCompile(`^[A-Z0-9]{3}$`)
+	// ETFs: E1VFVN30, FUEVFVND, FUESSV30
+	etfPattern = regexp.MustCompile(`^(E1[A-Z0-9]{6}|FU[A-Z0-9]{6})$`)
+	// Covered warrants: C, the underlying, the year and a serial, as in CVNM2401
+	cwPattern = regexp.MustCompile(`^C[A-Z0-9]{3}[0-9]{4}$`)
+	// Futures: VN30F2507 for July 2025, or a rolling alias such as VN30F1M;
+	// government bond futures GB05F2509 and GB10F2512
+	futurePattern = regexp.MustCompile(`^(VN30|VN100|GB05|GB10)F([0-9]{4}|[12][MQ])$`)
+	// Listed bonds: the issuer, the year and a serial, as in VIC123006
+	bondPattern = regexp.MustCompile(`^[A-Z]{3}[0-9]{5,6}$`)
+)
+
+// InferInstrumentType guesses the instrument type of a symbol from its format,
+// for symbols missing from the instrument master
+func InferInstrumentType(symbol string) (InstrumentType, bool) {
+	switch {
+	case IsIndex(symbol):
+		return InstrumentIndex, true
+	case stockPattern.MatchString(symbol):
+		return InstrumentStock, true
+	case etfPattern.MatchString(symbol):
+		return InstrumentETF, true
+	case futurePattern.MatchString(symbol):
+		return InstrumentFuture, true
+	case cwPattern.MatchString(symbol):
+		return InstrumentCW, true
+	case bondPattern.MatchString(symbol):
+		return InstrumentBond, true
+	}
+	return "", false
+}
+
+// Listed reports whether an instrument is listed on day: on or after its
+// listing date and before its delisting date, when known
+func (s SymbolInfo) Listed(day time.Time) bool {
+	if s.ListedDate != nil && day.Before(*s.ListedDate) {
+		return false
+	}
+	return s.DelistedDate == nil || day.Before(*s.DelistedDate)
+}
@@ -0,0 +1,50 @@
+package vnstock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInferInstrumentType(t *testing.T) {
+	tests := []struct {
+		symbol string
+		want   InstrumentType
+	}{
+		{"VNM", InstrumentStock},
+		{"VNINDEX", InstrumentIndex},
+		{"E1VFVN30", InstrumentETF},
+		{"FUEVFVND", InstrumentETF},
+		{"CVNM2401", InstrumentCW},
+		{"VN30F1M", InstrumentFuture},
+		{"VN30F2507", InstrumentFuture},
+		{"GB10F2512", InstrumentFuture},
+		{"VIC123006", InstrumentBond},
+	}
+	for _, tt := range tests {
+		if got, ok := InferInstrumentType(tt.symbol); !ok || got != tt.want {
+			t.Errorf("InferInstrumentType(%s) = %s, %v, want %s", tt.symbol, got, ok, tt.want)
+		}
+	}
+
+	for _, symbol := range []string{"", "vnm", "VNMX", "VN30F3M", "C2401"} {
+		if got, ok := InferInstrumentType(symbol); ok {
+			t.Errorf("InferInstrumentType(%q) = %s, want unknown", symbol, got)
+		}
+	}
+}
+
+func TestSymbolInfoListed(t *testing.T) {
+	listed := time.Date(2024, 3, 1, 0, 0, 0, 0, MarketLocation)
+	delisted := time.Date(2024, 9, 3, 0, 0, 0, 0, MarketLocation)
+	cw := SymbolInfo{Symbol: "CVNM2401", ListedDate: &listed, DelistedDate: &delisted}
+
+	for day, want := range map[string]bool{"2024-02-29": false, "2024-03-01": true, "2024-09-02": true, "2024-09-03": false} {
+		d, _ := time.ParseInLocation("2006-01-02", day, MarketLocation)
+		if got := cw.Listed(d); got != want {
+			t.Errorf("Listed(%s) = %v, want %v", day, got, want)
+		}
+	}
+	if !(SymbolInfo{Symbol: "VNM"}).Listed(time.Now()) {
+		t.Error("instrument without dates is not listed")
+	}
+}
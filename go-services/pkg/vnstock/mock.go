@@ -0,0 +1,214 @@
+package vnstock
+
+import (
+	"context"
+	"time"
+
+	"vnstock-hybrid/pkg/calendar"
+)
+
+// mockSymbols is the symbol list served by MockProvider
+var mockSymbols = []SymbolInfo{
+	{Symbol: "VNM", Name: "Công ty CP Sữa Việt Nam", Exchange: "HSX", Industry: "Thực phẩm", Type: InstrumentStock},
+	{Symbol: "FPT", Name: "Công ty CP FPT", Exchange: "HSX", Industry: "Công nghệ", Type: InstrumentStock},
+	{Symbol: "VIC", Name: "Tập đoàn Vingroup", Exchange: "HSX", Industry: "Bất động sản", Type: InstrumentStock},
+	{Symbol: "HPG", Name: "Tập đoàn Hòa Phát", Exchange: "HSX", Industry: "Thép", Type: InstrumentStock},
+	{Symbol: "VHM", Name: "Vinhomes", Exchange: "HSX", Industry: "Bất động sản", Type: InstrumentStock},
+	{Symbol: "VCB", Name: "Vietcombank", Exchange: "HSX", Industry: "Ngân hàng", Type: InstrumentStock},
+	{Symbol: "BID", Name: "BIDV", Exchange: "HSX", Industry: "Ngân hàng", Type: InstrumentStock},
+	{Symbol: "TCB", Name: "Techcombank", Exchange: "HSX", Industry: "Ngân hàng", Type: InstrumentStock},
+	{Symbol: "MBB", Name: "MB Bank", Exchange: "HSX", Industry: "Ngân hàng", Type: InstrumentStock},
+	{Symbol: "VPB", Name: "VPBank", Exchange: "HSX", Industry: "Ngân hàng", Type: InstrumentStock},
+	{Symbol: "MWG", Name: "Thế Giới Di Động", Exchange: "HSX", Industry: "Bán lẻ", Type: InstrumentStock},
+	{Symbol: "SSI", Name: "SSI Securities", Exchange: "HSX", Industry: "Chứng khoán", Type: InstrumentStock},
+	{Symbol: "VND", Name: "VNDirect Securities", Exchange: "HSX", Industry: "Chứng khoán", Type: InstrumentStock},
+	{Symbol: "PNJ", Name: "Phú Nhuận Jewelry", Exchange: "HSX", Industry: "Bán lẻ", Type: InstrumentStock},
+	{Symbol: "E1VFVN30", Name: "Quỹ ETF DCVFMVN30", Exchange: "HSX", Type: InstrumentETF, Underlying: IndexVN30, ListedDate: mockDate(2014, 10, 6)},
+	{Symbol: "FUEVFVND", Name: "Quỹ ETF DCVFMVN Diamond", Exchange: "HSX", Type: InstrumentETF, ListedDate: mockDate(2020, 5, 4)},
+	{Symbol: "CVNM2401", Name: "Chứng quyền VNM/SSI/Call/EU/Cash/6M/01", Exchange: "HSX", Type: InstrumentCW, Underlying: "VNM", ListedDate: mockDate(2024, 3, 1), DelistedDate: mockDate(2024, 9, 3)},
+	{Symbol: "CFPT2502", Name: "Chứng quyền FPT/SSI/Call/EU/Cash/12M/02", Exchange: "HSX", Type: InstrumentCW, Underlying: "FPT", ListedDate: mockDate(2025, 2, 10)},
+	{Symbol: "VN30F1M", Name: "Hợp đồng tương lai VN30 tháng gần nhất", Exchange: "HNX", Type: InstrumentFuture, Underlying: IndexVN30},
+}
+
+func mockDate(year int, month time.Month, day int) *time.Time {
+	t := time.Date(year, month, day, 0, 0, 0, 0, MarketLocation)
+	return &t
+}
+
+// MockProvider serves generated data for development and tests
+type MockProvider struct{}
+
+// NewMockProvider creates a new mock market data provider
+func NewMockProvider() *MockProvider {
+	return &MockProvider{}
+}
+
+// GetHistoricalData returns generated bars for the last days trading days
+func (p *MockProvider) GetHistoricalData(ctx context.Context, symbol string, resolution Resolution, days int) ([]OHLCV, error) {
+	data := generateMockData(resolution, days)
+	if IsIndex(symbol) {
+		data = mockIndexSeries(data)
+	}
+	return data, nil
+}
+
+// GetLatestQuote returns a quote built from the last generated bar
+func (p *MockProvider) GetLatestQuote(ctx context.Context, symbol string) (*Quote, error) {
+	data := generateMockData(Resolution1D, 2)
+	if IsIndex(symbol) {
+		data = mockIndexSeries(data)
+	}
+	latest := data[1]
+
+	return &Quote{
+		Symbol:         symbol,
+		Time:           latest.Date,
+		Price:          latest.Close,
+		Open:           latest.Open,
+		High:           latest.High,
+		Low:            latest.Low,
+		Volume:         latest.Volume,
+		Value:          latest.Value,
+		ReferencePrice: data[0].Close,
+	}, nil
+}
+
+// ListSymbols returns a fixed list of sample symbols
+func (p *MockProvider) ListSymbols(ctx context.Context, exchange string) ([]SymbolInfo, error) {
+	return filterSymbols(mockSymbols, exchange), nil
+}
+
+// generateMockData generates a simple price series of the given resolution ending today
+func generateMockData(resolution Resolution, days int) []OHLCV {
+	// A negative count yields no bars, as an empty date range does upstream
+	if days < 0 {
+		days = 0
+	}
+	if resolution.IsIntraday() {
+		data := generateMockIntraday(days)
+		if resolution == Resolution1m {
+			return data
+		}
+		data, _ = Resample(data, resolution)
+		return data
+	}
+
+	data := generateMockDaily(days)
+	if resolution.CoarserThan(Resolution1D) {
+		data, _ = Resample(data, resolution)
+	}
+	return data
+}
+
+// generateMockDaily generates one bar per trading day for the last days trading days
+func generateMockDaily(days int) []OHLCV {
+	data := make([]OHLCV, days)
+	basePrice := 50000.0 // Base price in VND
+	dates := mockTradingDays(days)
+
+	for i := 0; i < days; i++ {
+		date := dates[i]
+
+		// Generate somewhat realistic price movement
+		change := (float64(i%10) - 5) * 100
+		open := basePrice + change
+		close := open + (float64(i%5)-2)*50
+		high := max(open, close) + float64(i%3)*30
+		low := min(open, close) - float64(i%3)*30
+		volume := int64(1000000 + (i%10)*100000)
+
+		data[i] = OHLCV{
+			Date:   date,
+			Open:   open,
+			High:   high,
+			Low:    low,
+			Close:  close,
+			Volume: volume,
+		}
+
+		basePrice = close // Use close as next day's base
+	}
+
+	return data
+}
+
+// generateMockIntraday generates 1-minute bars over the trading sessions of
+// the last days trading days, with a single closing auction print at 14:45
+func generateMockIntraday(days int) []OHLCV {
+	var data []OHLCV
+	basePrice := 50000.0
+	i := 0
+
+	for _, day := range mockTradingDays(days) {
+		for _, s := range calendar.BarSessions() {
+			start, end := s.Start, s.End
+			if s.Name == "ATC" {
+				start, end = 14*60+45, 14*60+46
+			}
+
+			for minute := start; minute < end; minute++ {
+				open := basePrice + (float64(i%10)-5)*10
+				close := open + (float64(i%5)-2)*10
+				data = append(data, OHLCV{
+					Date:   day.Add(time.Duration(minute) * time.Minute),
+					Open:   open,
+					High:   max(open, close) + float64(i%3)*10,
+					Low:    min(open, close) - float64(i%3)*10,
+					Close:  close,
+					Volume: int64(1000 + (i%10)*100),
+				})
+				basePrice = close
+				i++
+			}
+		}
+	}
+
+	return data
+}
+
+// mockIndexSeries turns generated stock bars into index-like bars: prices in
+// points around 1250 and market-wide volume and traded value
+func mockIndexSeries(data []OHLCV) []OHLCV {
+	const pointsPerVND = 1250.0 / 50000.0
+	const marketMultiple = 400
+
+	index := make([]OHLCV, len(data))
+	for i, b := range data {
+		index[i] = OHLCV{
+			Date:   b.Date,
+			Open:   b.Open * pointsPerVND,
+			High:   b.High * pointsPerVND,
+			Low:    b.Low * pointsPerVND,
+			Close:  b.Close * pointsPerVND,
+			Volume: b.Volume * marketMultiple,
+			Value:  float64(b.Volume) * b.Close * marketMultiple,
+		}
+	}
+	return index
+}
+
+// mockTradingDays returns the last days trading days up to today, oldest first
+func mockTradingDays(days int) []time.Time {
+	dates := make([]time.Time, days)
+	day := calendar.AddTradingDays(time.Now(), 0)
+	for i := days - 1; i >= 0; i-- {
+		dates[i] = day
+		day = calendar.PreviousTradingDay(day)
+	}
+	return dates
+}
+
+// filterSymbols returns the symbols listed on exchange, or all symbols if exchange is empty
+func filterSymbols(symbols []SymbolInfo, exchange string) []SymbolInfo {
+	if exchange == "" {
+		return symbols
+	}
+
+	var result []SymbolInfo
+	for _, s := range symbols {
+		if s.Exchange == exchange {
+			result = append(result, s)
+		}
+	}
+	return result
+}
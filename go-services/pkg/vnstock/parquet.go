@@ -0,0 +1,197 @@
+package vnstock
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+// parquetBar is the schema used when exporting bars to Parquet
+type parquetBar struct {
+	Symbol string    `parquet:"symbol"`
+	Date   time.Time `parquet:"date,timestamp(millisecond)"`
+	Open   float64   `parquet:"open"`
+	High   float64   `parquet:"high"`
+	Low    float64   `parquet:"low"`
+	Close  float64   `parquet:"close"`
+	Volume int64     `parquet:"volume"`
+	Value  float64   `parquet:"value"`
+}
+
+// WriteParquet writes bars to a Parquet file using the same columns as WriteCSV
+func WriteParquet(w io.Writer, records []BarRecord) error {
+	rows := make([]parquetBar, len(records))
+	for i, r := range records {
+		rows[i] = parquetBar{
+			Symbol: r.Symbol,
+			Date:   r.Date.UTC(),
+			Open:   r.Open,
+			High:   r.High,
+			Low:    r.Low,
+			Close:  r.Close,
+			Volume: r.Volume,
+			Value:  r.Value,
+		}
+	}
+
+	writer := parquet.NewGenericWriter[parquetBar](w)
+	if _, err := writer.Write(rows); err != nil {
+		return fmt.Errorf("failed to write parquet rows: %w", err)
+	}
+	return writer.Close()
+}
+
+// ReadParquet reads bars from a flat Parquet file.
+// Columns are located through mapping, falling back to common column names.
+func ReadParquet(r io.ReaderAt, size int64, mapping ColumnMapping) ([]BarRecord, error) {
+	file, err := parquet.OpenFile(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open parquet file: %w", err)
+	}
+
+	schema := file.Schema()
+	paths := schema.Columns()
+	columns := make([]string, len(paths))
+	for i, path := range paths {
+		columns[i] = path[len(path)-1]
+	}
+
+	index, err := resolveColumns(columns, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	logicalTypes := make(map[int]*format.LogicalType)
+	for field, i := range index {
+		leaf, ok := schema.Lookup(paths[i]...)
+		if !ok {
+			return nil, fmt.Errorf("column %s not found in schema", field)
+		}
+		logicalTypes[i] = leaf.Node.Type().LogicalType()
+	}
+
+	reader := parquet.NewReader(file)
+	defer reader.Close()
+
+	var records []BarRecord
+	rows := make([]parquet.Row, 128)
+	for rowNum := 1; ; {
+		n, err := reader.ReadRows(rows)
+		for _, row := range rows[:n] {
+			record, perr := parseParquetRow(row, index, logicalTypes)
+			if perr != nil {
+				return nil, fmt.Errorf("row %d: %w", rowNum, perr)
+			}
+			records = append(records, record)
+			rowNum++
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read parquet rows: %w", err)
+		}
+	}
+
+	return records, nil
+}
+
+func parseParquetRow(row parquet.Row, index map[string]int, logicalTypes map[int]*format.LogicalType) (BarRecord, error) {
+	values := make(map[int]parquet.Value, len(row))
+	for _, v := range row {
+		values[v.Column()] = v
+	}
+
+	var record BarRecord
+	var err error
+
+	if i, ok := index[FieldSymbol]; ok {
+		record.Symbol = string(values[i].ByteArray())
+	}
+
+	i := index[FieldDate]
+	if record.Date, err = parquetTime(values[i], logicalTypes[i]); err != nil {
+		return record, err
+	}
+
+	prices := map[string]*float64{
+		FieldOpen:  &record.Open,
+		FieldHigh:  &record.High,
+		FieldLow:   &record.Low,
+		FieldClose: &record.Close,
+	}
+	for field, dst := range prices {
+		if *dst, err = parquetNumber(values[index[field]]); err != nil {
+			return record, fmt.Errorf("invalid %s: %w", field, err)
+		}
+	}
+
+	if i, ok := index[FieldVolume]; ok {
+		volume, err := parquetNumber(values[i])
+		if err != nil {
+			return record, fmt.Errorf("invalid volume: %w", err)
+		}
+		record.Volume = int64(volume)
+	}
+
+	if i, ok := index[FieldValue]; ok {
+		if record.Value, err = parquetNumber(values[i]); err != nil {
+			return record, fmt.Errorf("invalid value: %w", err)
+		}
+	}
+
+	return record, nil
+}
+
+// parquetNumber converts any numeric or string Parquet value to float64
+func parquetNumber(v parquet.Value) (float64, error) {
+	if v.IsNull() {
+		return 0, nil
+	}
+
+	switch v.Kind() {
+	case parquet.Int32:
+		return float64(v.Int32()), nil
+	case parquet.Int64:
+		return float64(v.Int64()), nil
+	case parquet.Float:
+		return float64(v.Float()), nil
+	case parquet.Double:
+		return v.Double(), nil
+	case parquet.ByteArray:
+		return parseNumber(string(v.ByteArray()))
+	default:
+		return 0, fmt.Errorf("unsupported value kind %s", v.Kind())
+	}
+}
+
+// parquetTime converts DATE, TIMESTAMP and string Parquet values to time.Time
+func parquetTime(v parquet.Value, logicalType *format.LogicalType) (time.Time, error) {
+	if v.IsNull() {
+		return time.Time{}, fmt.Errorf("missing date")
+	}
+
+	switch v.Kind() {
+	case parquet.ByteArray:
+		return parseDate(string(v.ByteArray()))
+	case parquet.Int32:
+		// DATE is stored as days since the Unix epoch
+		return time.Unix(int64(v.Int32())*24*60*60, 0).UTC(), nil
+	case parquet.Int64:
+		if logicalType != nil && logicalType.Timestamp != nil {
+			unit := logicalType.Timestamp.Unit
+			switch {
+			case unit.Micros != nil:
+				return time.UnixMicro(v.Int64()).UTC(), nil
+			case unit.Nanos != nil:
+				return time.Unix(0, v.Int64()).UTC(), nil
+			}
+		}
+		return time.UnixMilli(v.Int64()).UTC(), nil
+	default:
+		return time.Time{}, fmt.Errorf("unsupported date kind %s", v.Kind())
+	}
+}
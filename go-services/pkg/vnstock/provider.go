@@ -0,0 +1,143 @@
+package vnstock
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Quote represents the latest trading snapshot of a symbol
+type Quote struct {
+	Symbol         string    `json:"symbol"`
+	Time           time.Time `json:"time"`
+	Price          float64   `json:"price"`
+	Open           float64   `json:"open"`
+	High           float64   `json:"high"`
+	Low            float64   `json:"low"`
+	Volume         int64     `json:"volume"`
+	Value          float64   `json:"value,omitempty"`
+	ReferencePrice float64   `json:"reference_price"`
+}
+
+// SymbolInfo describes a listed symbol
+type SymbolInfo struct {
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Exchange string `json:"exchange"`
+	Industry string `json:"industry"`
+	// Type is the kind of instrument, empty when the provider does not report it
+	Type InstrumentType `json:"type,omitempty"`
+	// Underlying is the stock or index a covered warrant or future derives from
+	Underlying string `json:"underlying,omitempty"`
+	// ListedDate and DelistedDate bound the trading life of the instrument.
+	// Covered warrants and futures are delisted after their last trading day.
+	ListedDate   *time.Time `json:"listed_date,omitempty"`
+	DelistedDate *time.Time `json:"delisted_date,omitempty"`
+}
+
+// MarketDataProvider is a source of Vietnamese market data
+type MarketDataProvider interface {
+	// GetHistoricalData returns bars of the given resolution covering the last
+	// days trading days, oldest first. Symbol may be a stock or a market index
+	// (see IndexSymbols).
+	GetHistoricalData(ctx context.Context, symbol string, resolution Resolution, days int) ([]OHLCV, error)
+	// GetLatestQuote returns the most recent quote for a symbol
+	GetLatestQuote(ctx context.Context, symbol string) (*Quote, error)
+	// ListSymbols returns the symbols listed on an exchange, or on all exchanges if exchange is empty
+	ListSymbols(ctx context.Context, exchange string) ([]SymbolInfo, error)
+}
+
+// BreakerReporter is implemented by providers that guard their upstream
+// hosts with circuit breakers
+type BreakerReporter interface {
+	BreakerStates() map[string]BreakerState
+}
+
+// Supported provider types
+const (
+	ProviderHTTP      = "http"
+	ProviderMock      = "mock"
+	ProviderFile      = "file"
+	ProviderComposite = "composite"
+)
+
+// ProviderConfig selects and configures a MarketDataProvider
+type ProviderConfig struct {
+	// Name identifies a source of a composite provider
+	Name    string
+	Type    string
+	BaseURL string
+	Timeout time.Duration
+	DataDir string
+	// Client configures retries, circuit breaking and rate limiting of the
+	// http provider; the zero value uses DefaultClientOptions
+	Client ClientOptions
+
+	// Sources are the providers of a composite provider, primary first. They
+	// inherit Timeout and Client from the composite configuration.
+	Sources   []ProviderConfig
+	Composite CompositeOptions
+
+	// Seed makes generated providers reproducible
+	Seed int64
+}
+
+// ProviderFactory creates a provider of a type registered outside this package
+type ProviderFactory func(cfg ProviderConfig) (MarketDataProvider, error)
+
+// providerFactories holds the provider types added with RegisterProvider
+var providerFactories = make(map[string]ProviderFactory)
+
+// RegisterProvider makes a provider type available to NewProvider and
+// ParseSources. It is meant to be called from an init function.
+func RegisterProvider(typ string, factory ProviderFactory) {
+	providerFactories[typ] = factory
+}
+
+// NewProvider creates the MarketDataProvider described by cfg
+func NewProvider(cfg ProviderConfig) (MarketDataProvider, error) {
+	switch cfg.Type {
+	case ProviderHTTP, "":
+		opts := cfg.Client
+		if opts == (ClientOptions{}) {
+			opts = DefaultClientOptions
+		}
+		if cfg.Timeout > 0 {
+			opts.Timeout = cfg.Timeout
+		}
+		if opts.Timeout <= 0 {
+			opts.Timeout = 30 * time.Second
+		}
+		baseURL := cfg.BaseURL
+		if baseURL == "" {
+			baseURL = "https://api.vietstock.vn/finance"
+		}
+		return NewClientWithOptions(baseURL, opts), nil
+	case ProviderMock:
+		return NewMockProvider(), nil
+	case ProviderComposite:
+		sources := make([]NamedProvider, len(cfg.Sources))
+		for i, src := range cfg.Sources {
+			if src.Type == ProviderComposite {
+				return nil, fmt.Errorf("composite source %q cannot itself be composite", src.Name)
+			}
+			src.Timeout, src.Client = cfg.Timeout, cfg.Client
+			provider, err := NewProvider(src)
+			if err != nil {
+				return nil, fmt.Errorf("source %q: %w", src.Name, err)
+			}
+			sources[i] = NamedProvider{Name: src.Name, Provider: provider}
+		}
+		return NewCompositeProvider(sources, cfg.Composite)
+	case ProviderFile:
+		if cfg.DataDir == "" {
+			return nil, fmt.Errorf("file provider requires a data directory")
+		}
+		return NewFileProvider(cfg.DataDir), nil
+	default:
+		if factory, ok := providerFactories[cfg.Type]; ok {
+			return factory(cfg)
+		}
+		return nil, fmt.Errorf("unknown market data provider %q", cfg.Type)
+	}
+}
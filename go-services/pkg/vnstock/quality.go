@@ -0,0 +1,214 @@
+package vnstock
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"vnstock-hybrid/pkg/calendar"
+)
+
+// IssueType classifies a data quality problem
+type IssueType string
+
+// Data quality issues
+const (
+	IssueMissingDays  IssueType = "missing_days"
+	IssueZeroVolume   IssueType = "zero_volume"
+	IssueInvalidRange IssueType = "invalid_range"
+	IssueDuplicate    IssueType = "duplicate_date"
+	IssuePriceJump    IssueType = "price_jump"
+)
+
+// Quality modes
+const (
+	// QualityFlag reports issues and leaves the bars untouched
+	QualityFlag = "flag"
+	// QualityRepair drops broken bars and forward-fills missing trading days
+	QualityRepair = "repair"
+	// QualityReject fails on any issue
+	QualityReject = "reject"
+)
+
+// maxJumpRatio is the close-to-close ratio treated as a bad print rather than a move.
+// VN price bands make a genuine daily move of this size impossible.
+const maxJumpRatio = 10.0
+
+// QualityIssue is a problem found in a bar series
+type QualityIssue struct {
+	Type    IssueType `json:"type"`
+	Date    time.Time `json:"date"`
+	Message string    `json:"message"`
+}
+
+// DataQuality summarizes the validation of a bar series
+type DataQuality struct {
+	Mode string `json:"mode"`
+	// Flagged reports issues left in the returned bars: any issue, unless
+	// repair fixed them all
+	Flagged bool `json:"flagged"`
+	// Issues are the issues found in the bars as given
+	Issues  []QualityIssue `json:"issues,omitempty"`
+	Dropped int            `json:"dropped"`
+	Filled  int            `json:"filled"`
+}
+
+// ValidQualityMode reports whether mode is a supported quality mode
+func ValidQualityMode(mode string) bool {
+	return mode == QualityFlag || mode == QualityRepair || mode == QualityReject
+}
+
+// ValidateBars checks a series sorted oldest first for duplicate dates,
+// inconsistent or non-positive prices, zero volume, 10x price jumps and, for
+// daily and intraday bars, missing trading days
+func ValidateBars(bars []OHLCV, resolution Resolution) []QualityIssue {
+	var issues []QualityIssue
+	add := func(t IssueType, date time.Time, format string, args ...interface{}) {
+		issues = append(issues, QualityIssue{Type: t, Date: date, Message: fmt.Sprintf(format, args...)})
+	}
+
+	var prevClose float64
+	for i, b := range bars {
+		if i > 0 {
+			if b.Date.Equal(bars[i-1].Date) {
+				add(IssueDuplicate, b.Date, "duplicate bar")
+				continue
+			}
+			if !resolution.CoarserThan(Resolution1D) {
+				if gap := calendar.TradingDaysBetween(bars[i-1].Date, b.Date) - 1; gap > 0 {
+					add(IssueMissingDays, b.Date, "%d trading days missing before this bar", gap)
+				}
+			}
+		}
+
+		if err := b.Validate(); err != nil {
+			add(IssueInvalidRange, b.Date, "%v", err)
+			continue
+		}
+		if b.Volume == 0 && b.Value == 0 {
+			add(IssueZeroVolume, b.Date, "no volume traded")
+		}
+		if prevClose > 0 && isJump(prevClose, b.Close) {
+			add(IssuePriceJump, b.Date, "close %.2f is %.1fx the previous close %.2f", b.Close, b.Close/prevClose, prevClose)
+		}
+		prevClose = b.Close
+	}
+
+	return issues
+}
+
+// CleanBars validates bars and applies mode. In repair mode duplicate dates
+// keep their last bar, bars with invalid prices and isolated 10x spikes are
+// dropped, and missing trading days of daily series are forward-filled with
+// the previous close and zero volume. Repaired bars are validated again, and
+// only the issues repair cannot fix, such as zero volume or a lasting jump,
+// leave them flagged. Reject mode returns an error if any issue is found.
+// Zero volume is only ever flagged.
+func CleanBars(bars []OHLCV, resolution Resolution, mode string) ([]OHLCV, *DataQuality, error) {
+	quality := &DataQuality{Mode: mode}
+	quality.Issues = ValidateBars(bars, resolution)
+	quality.Flagged = len(quality.Issues) > 0
+
+	switch mode {
+	case QualityReject:
+		if quality.Flagged {
+			return nil, quality, fmt.Errorf("data quality check failed: %d issues, first: %s on %s",
+				len(quality.Issues), quality.Issues[0].Message, quality.Issues[0].Date.Format("2006-01-02"))
+		}
+		return bars, quality, nil
+	case QualityRepair:
+		repaired, dropped := dropBrokenBars(bars)
+		repaired, filled := fillMissingDays(repaired, resolution)
+		quality.Dropped, quality.Filled = dropped, filled
+		quality.Flagged = len(remainingIssues(repaired, resolution)) > 0
+		return repaired, quality, nil
+	default:
+		return bars, quality, nil
+	}
+}
+
+// remainingIssues validates repaired bars, leaving out the zero volume of the
+// forward-filled ones
+func remainingIssues(bars []OHLCV, resolution Resolution) []QualityIssue {
+	filled := make(map[int64]bool)
+	for _, b := range bars {
+		if b.Source == filledSource {
+			filled[b.Date.Unix()] = true
+		}
+	}
+
+	var remaining []QualityIssue
+	for _, issue := range ValidateBars(bars, resolution) {
+		if issue.Type == IssueZeroVolume && filled[issue.Date.Unix()] {
+			continue
+		}
+		remaining = append(remaining, issue)
+	}
+	return remaining
+}
+
+// dropBrokenBars removes duplicates (keeping the last), bars with invalid
+// prices and single-bar 10x spikes that revert on the next bar
+func dropBrokenBars(bars []OHLCV) ([]OHLCV, int) {
+	var valid []OHLCV
+	for i, b := range bars {
+		if i+1 < len(bars) && bars[i+1].Date.Equal(b.Date) {
+			continue
+		}
+		if b.Validate() != nil {
+			continue
+		}
+		valid = append(valid, b)
+	}
+
+	result := make([]OHLCV, 0, len(valid))
+	for i, b := range valid {
+		if len(result) > 0 && i+1 < len(valid) {
+			prev, next := result[len(result)-1].Close, valid[i+1].Close
+			if isJump(prev, b.Close) && !isJump(prev, next) {
+				continue
+			}
+		}
+		result = append(result, b)
+	}
+
+	return result, len(bars) - len(result)
+}
+
+func isJump(from, to float64) bool {
+	ratio := to / from
+	return ratio >= maxJumpRatio || ratio <= 1/maxJumpRatio
+}
+
+// filledSource is the source of the bars inserted by fillMissingDays
+const filledSource = "ffill"
+
+// fillMissingDays inserts a flat bar at the previous close for every trading
+// day missing from a daily series
+func fillMissingDays(bars []OHLCV, resolution Resolution) ([]OHLCV, int) {
+	if resolution != Resolution1D || len(bars) < 2 {
+		return bars, 0
+	}
+
+	var filled []OHLCV
+	for i := 1; i < len(bars); i++ {
+		prev := bars[i-1]
+		for day := calendar.NextTradingDay(prev.Date); day.Before(BucketStart(bars[i].Date, Resolution1D)); day = calendar.NextTradingDay(day) {
+			filled = append(filled, OHLCV{
+				Date:   day,
+				Open:   prev.Close,
+				High:   prev.Close,
+				Low:    prev.Close,
+				Close:  prev.Close,
+				Source: filledSource,
+			})
+		}
+	}
+	if len(filled) == 0 {
+		return bars, 0
+	}
+
+	result := append(append([]OHLCV{}, bars...), filled...)
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Date.Before(result[j].Date) })
+	return result, len(filled)
+}
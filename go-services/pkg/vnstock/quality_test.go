@@ -0,0 +1,104 @@
+package vnstock
+
+import (
+	"testing"
+	"time"
+)
+
+// qualityBars returns daily bars on consecutive trading days from Monday 2025-03-03
+func qualityBars(closes ...float64) []OHLCV {
+	day := time.Date(2025, 3, 3, 0, 0, 0, 0, MarketLocation)
+	bars := make([]OHLCV, len(closes))
+	for i, c := range closes {
+		bars[i] = OHLCV{Date: day, Open: c, High: c + 100, Low: c - 100, Close: c, Volume: 1000}
+		day = day.AddDate(0, 0, 1)
+		for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			day = day.AddDate(0, 0, 1)
+		}
+	}
+	return bars
+}
+
+func issueTypes(issues []QualityIssue) map[IssueType]int {
+	types := make(map[IssueType]int)
+	for _, i := range issues {
+		types[i.Type]++
+	}
+	return types
+}
+
+func TestValidateBars(t *testing.T) {
+	bars := qualityBars(50000, 50500, 51000, 600000, 51500, 52000, 52500)
+	bars[1].Volume = 0
+	bars[2].High = bars[2].Low - 1
+	bars = append(bars[:5], append([]OHLCV{bars[4]}, bars[5:]...)...) // duplicate
+	bars = append(bars[:6], bars[7:]...)                              // drop a day
+
+	got := issueTypes(ValidateBars(bars, Resolution1D))
+	want := map[IssueType]int{
+		IssueZeroVolume:   1,
+		IssueInvalidRange: 1,
+		IssuePriceJump:    2, // into and out of the spike
+		IssueDuplicate:    1,
+		IssueMissingDays:  1,
+	}
+	for typ, n := range want {
+		if got[typ] != n {
+			t.Errorf("%s: got %d issues, want %d (all: %v)", typ, got[typ], n, got)
+		}
+	}
+}
+
+func TestCleanBarsRepair(t *testing.T) {
+	bars := qualityBars(50000, 50500, 600000, 51000, 51500, 52000)
+	bars = append(bars[:4], bars[5:]...) // drop a day
+
+	repaired, quality, err := CleanBars(bars, Resolution1D, QualityRepair)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The spike is dropped, then its day is forward-filled like the missing
+	// one, which leaves nothing to flag
+	if quality.Flagged || len(quality.Issues) != 3 || quality.Dropped != 1 || quality.Filled != 2 {
+		t.Errorf("got flagged=%v issues=%d dropped=%d filled=%d, want false/3/1/2",
+			quality.Flagged, len(quality.Issues), quality.Dropped, quality.Filled)
+	}
+	if len(repaired) != 6 {
+		t.Fatalf("got %d bars, want 6", len(repaired))
+	}
+	if filled := repaired[2]; filled.Close != 50500 || filled.Volume != 0 {
+		t.Errorf("forward-filled bar = %+v, want close 50500 with no volume", filled)
+	}
+	if issues := issueTypes(ValidateBars(repaired, Resolution1D)); len(issues) != 1 || issues[IssueZeroVolume] != 2 {
+		t.Errorf("repaired series still has issues: %+v", issues)
+	}
+}
+
+func TestCleanBarsRepairKeepsUnfixableFlagged(t *testing.T) {
+	// Zero volume is not repaired, nor is a jump that does not revert
+	zero := qualityBars(50000, 50500, 51000)
+	zero[1].Volume = 0
+	jump := qualityBars(50000, 50500, 600000, 601000)
+
+	for name, bars := range map[string][]OHLCV{"zero volume": zero, "lasting jump": jump} {
+		_, quality, err := CleanBars(bars, Resolution1D, QualityRepair)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !quality.Flagged {
+			t.Errorf("%s: repaired bars not flagged, issues %+v", name, quality.Issues)
+		}
+	}
+}
+
+func TestCleanBarsReject(t *testing.T) {
+	bars := qualityBars(50000, 50500)
+	bars[1].Low = bars[1].High + 1
+
+	if _, _, err := CleanBars(bars, Resolution1D, QualityReject); err == nil {
+		t.Error("reject mode accepted an invalid bar")
+	}
+	if _, _, err := CleanBars(qualityBars(50000, 50500), Resolution1D, QualityReject); err != nil {
+		t.Errorf("reject mode refused clean bars: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package vnstock
+
+import (
+	"fmt"
+	"time"
+
+	"vnstock-hybrid/pkg/calendar"
+)
+
+// Resample aggregates bars into the coarser resolution to.
+// Bars must be sorted oldest first and be finer than to.
+func Resample(bars []OHLCV, to Resolution) ([]OHLCV, error) {
+	if to.rank() < 0 {
+		return nil, fmt.Errorf("unsupported resolution %q", to)
+	}
+
+	var result []OHLCV
+	var current OHLCV
+	var currentStart time.Time
+
+	for i, b := range bars {
+		start := BucketStart(b.Date, to)
+
+		if i > 0 && start.Equal(currentStart) {
+			current.High = max(current.High, b.High)
+			current.Low = min(current.Low, b.Low)
+			current.Close = b.Close
+			current.Volume += b.Volume
+			current.Value += b.Value
+			continue
+		}
+
+		if i > 0 {
+			if start.Before(currentStart) {
+				return nil, fmt.Errorf("bars are not sorted: %s after %s", b.Date, currentStart)
+			}
+			result = append(result, current)
+		}
+
+		currentStart = start
+		current = b
+		current.Date = start
+	}
+
+	if len(bars) > 0 {
+		result = append(result, current)
+	}
+
+	return result, nil
+}
+
+// BucketStart returns the start of the bar of resolution r containing t.
+// Intraday buckets are aligned to the start of each span of calendar.BarSessions
+// and truncated at its end, so they never cross the lunch break or the closing
+// auction; daily and coarser buckets start at midnight market time.
+func BucketStart(t time.Time, r Resolution) time.Time {
+	local := t.In(MarketLocation)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, MarketLocation)
+
+	switch r {
+	case Resolution1D:
+		return day
+	case Resolution1W:
+		offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+		return day.AddDate(0, 0, -offset)
+	case Resolution1M:
+		return time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, MarketLocation)
+	}
+
+	step := int(r.Duration() / time.Minute)
+	minute := local.Hour()*60 + local.Minute()
+	s := sessionAt(minute)
+
+	// Prints outside a span (pre-open, lunch break) belong to the nearest one
+	if minute < s.Start {
+		minute = s.Start
+	}
+	if minute >= s.End {
+		minute = s.End - 1
+	}
+
+	bucket := s.Start + (minute-s.Start)/step*step
+	return day.Add(time.Duration(bucket) * time.Minute)
+}
+
+// sessionAt returns the bar span containing minute, or the closest one before it
+func sessionAt(minute int) calendar.Session {
+	spans := calendar.BarSessions()
+	current := spans[0]
+	for _, s := range spans {
+		if minute >= s.Start {
+			current = s
+		}
+	}
+	return current
+}
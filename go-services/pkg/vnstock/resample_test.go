@@ -0,0 +1,75 @@
+package vnstock
+
+import (
+	"testing"
+	"time"
+)
+
+func minuteBar(hour, minute int, price float64, volume int64) OHLCV {
+	return OHLCV{
+		Date:   time.Date(2024, 3, 4, hour, minute, 0, 0, MarketLocation),
+		Open:   price,
+		High:   price + 10,
+		Low:    price - 10,
+		Close:  price + 5,
+		Volume: volume,
+	}
+}
+
+func TestResampleRespectsSessions(t *testing.T) {
+	bars := []OHLCV{
+		minuteBar(11, 0, 100, 10),
+		minuteBar(11, 29, 110, 20),
+		minuteBar(13, 0, 120, 30),
+		minuteBar(14, 29, 130, 40),
+		minuteBar(14, 45, 140, 50), // ATC
+	}
+
+	hourly, err := Resample(bars, Resolution1h)
+	if err != nil {
+		t.Fatalf("Resample failed: %v", err)
+	}
+
+	// 11:00 bar stops at the lunch break, 13:00 bar at the ATC auction
+	expected := []struct {
+		hour, minute int
+		volume       int64
+	}{
+		{11, 0, 30},
+		{13, 0, 30},
+		{14, 0, 40},
+		{14, 30, 50},
+	}
+	if len(hourly) != len(expected) {
+		t.Fatalf("got %d hourly bars, expected %d: %+v", len(hourly), len(expected), hourly)
+	}
+	for i, e := range expected {
+		start := time.Date(2024, 3, 4, e.hour, e.minute, 0, 0, MarketLocation)
+		if !hourly[i].Date.Equal(start) || hourly[i].Volume != e.volume {
+			t.Errorf("bar %d = %s vol %d, expected %s vol %d", i, hourly[i].Date, hourly[i].Volume, start, e.volume)
+		}
+	}
+
+	if hourly[0].Open != 100 || hourly[0].Close != 115 || hourly[0].High != 120 || hourly[0].Low != 90 {
+		t.Errorf("bad aggregation for first bar: %+v", hourly[0])
+	}
+
+	daily, err := Resample(bars, Resolution1D)
+	if err != nil {
+		t.Fatalf("Resample failed: %v", err)
+	}
+	if len(daily) != 1 || daily[0].Volume != 150 || daily[0].Close != 145 {
+		t.Errorf("daily = %+v, expected one bar with volume 150 and close 145", daily)
+	}
+}
+
+func TestBucketStartWeekAndMonth(t *testing.T) {
+	wednesday := time.Date(2024, 3, 6, 0, 0, 0, 0, MarketLocation)
+
+	if got := BucketStart(wednesday, Resolution1W); !got.Equal(time.Date(2024, 3, 4, 0, 0, 0, 0, MarketLocation)) {
+		t.Errorf("week start = %s, expected Monday 2024-03-04", got)
+	}
+	if got := BucketStart(wednesday, Resolution1M); !got.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, MarketLocation)) {
+		t.Errorf("month start = %s, expected 2024-03-01", got)
+	}
+}
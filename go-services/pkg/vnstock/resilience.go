@@ -0,0 +1,252 @@
+package vnstock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry; it doubles on every retry
+	BaseDelay time.Duration
+	// MaxDelay caps any single wait, including one requested by Retry-After
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy retries three times over roughly four seconds
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+// backoff returns the wait before retry number attempt (0 for the first retry),
+// drawn uniformly up to the exponential bound ("full jitter") so that clients
+// failing together do not retry together
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	bound := p.BaseDelay << attempt
+	if bound <= 0 || bound > p.MaxDelay {
+		bound = p.MaxDelay
+	}
+	if bound <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(bound) + 1))
+}
+
+// StatusError is returned when the API answers with a non-200 status
+type StatusError struct {
+	StatusCode int
+	// RetryAfter is the wait requested by the server, if any
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("API returned status %d", e.StatusCode)
+}
+
+// retryable reports whether a request that failed with err may succeed if
+// repeated. The caller's own cancellation is checked separately: a timeout
+// of a single attempt also matches context.DeadlineExceeded, but is the host's
+// failure and worth retrying.
+func retryable(err error) bool {
+	if errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		code := statusErr.StatusCode
+		return code == http.StatusTooManyRequests || code == http.StatusRequestTimeout || code >= 500
+	}
+
+	// Timeouts, network errors and truncated bodies
+	return true
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// BreakerState is the state of a circuit breaker
+type BreakerState string
+
+// Circuit breaker states
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half_open"
+)
+
+// ErrCircuitOpen is returned without contacting the host while its breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// CircuitBreaker stops calls to a failing host. After Threshold consecutive
+// failures it opens and rejects calls for Cooldown, then lets a single probe
+// through: success closes it again, failure reopens it.
+type CircuitBreaker struct {
+	Threshold int
+	Cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+	now      func() time.Time
+}
+
+// NewCircuitBreaker creates a closed circuit breaker
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		Threshold: threshold,
+		Cooldown:  cooldown,
+		state:     BreakerClosed,
+		now:       time.Now,
+	}
+}
+
+// Allow returns ErrCircuitOpen if a call should not be attempted now
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.currentState() {
+	case BreakerOpen:
+		return ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+	}
+	return nil
+}
+
+// Success records a call that reached a healthy host
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = BreakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// Failure records a call that failed because of the host
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.probing || (b.Threshold > 0 && b.failures >= b.Threshold) {
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+	}
+	b.probing = false
+}
+
+// Abandon records a call given up by the caller, which says nothing about the
+// host; a half-open breaker lets the next probe through
+func (b *CircuitBreaker) Abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// State returns the current state of the breaker
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.currentState()
+}
+
+// currentState resolves an open breaker whose cooldown has elapsed to half-open
+func (b *CircuitBreaker) currentState() BreakerState {
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.Cooldown {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// RateLimiter is a token bucket limiting outbound requests
+type RateLimiter struct {
+	rate  float64 // tokens per second
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter allows perSecond requests on average with bursts of up to burst
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   perSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request may be sent or ctx is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil || l.rate <= 0 {
+		return nil
+	}
+
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+		l.last = now
+
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
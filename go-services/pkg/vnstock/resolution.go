@@ -0,0 +1,76 @@
+package vnstock
+
+import (
+	"fmt"
+	"time"
+
+	"vnstock-hybrid/pkg/calendar"
+)
+
+// Resolution is the time span covered by a single bar
+type Resolution string
+
+// Supported resolutions
+const (
+	Resolution1m  Resolution = "1m"
+	Resolution5m  Resolution = "5m"
+	Resolution15m Resolution = "15m"
+	Resolution1h  Resolution = "1h"
+	Resolution1D  Resolution = "1D"
+	Resolution1W  Resolution = "1W"
+	Resolution1M  Resolution = "1M"
+)
+
+// resolutions lists the supported resolutions from finest to coarsest
+var resolutions = []Resolution{
+	Resolution1m, Resolution5m, Resolution15m, Resolution1h,
+	Resolution1D, Resolution1W, Resolution1M,
+}
+
+// MarketLocation is the time zone of the Vietnamese exchanges
+var MarketLocation = calendar.Location
+
+// ParseResolution parses a resolution such as "5m" or "1D"
+func ParseResolution(s string) (Resolution, error) {
+	for _, r := range resolutions {
+		if string(r) == s {
+			return r, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported resolution %q, expected one of 1m, 5m, 15m, 1h, 1D, 1W, 1M", s)
+}
+
+// IsIntraday reports whether bars of this resolution are shorter than a trading day
+func (r Resolution) IsIntraday() bool {
+	return r.rank() < Resolution1D.rank()
+}
+
+// Duration returns the length of an intraday bar, or 0 for daily and coarser resolutions
+func (r Resolution) Duration() time.Duration {
+	switch r {
+	case Resolution1m:
+		return time.Minute
+	case Resolution5m:
+		return 5 * time.Minute
+	case Resolution15m:
+		return 15 * time.Minute
+	case Resolution1h:
+		return time.Hour
+	default:
+		return 0
+	}
+}
+
+// CoarserThan reports whether r aggregates bars of resolution other
+func (r Resolution) CoarserThan(other Resolution) bool {
+	return r.rank() > other.rank()
+}
+
+func (r Resolution) rank() int {
+	for i, res := range resolutions {
+		if res == r {
+			return i
+		}
+	}
+	return -1
+}
@@ -7,20 +7,133 @@ GRANT ALL PRIVILEGES ON DATABASE n8n TO n8n;
 -- Create tables for vnstock
 \c vnstock;
 
--- Stock symbols
-CREATE TABLE IF NOT EXISTS stocks (
+-- Instrument master: listed securities and market indices, kept current by
+-- the daily sync from the market data provider
+CREATE TABLE IF NOT EXISTS instruments (
     symbol VARCHAR(10) PRIMARY KEY,
     name VARCHAR(255) NOT NULL,
-    exchange VARCHAR(10) NOT NULL CHECK (exchange IN ('HSX', 'HNX', 'UPCOM')),
+    type VARCHAR(10) NOT NULL DEFAULT 'stock' CHECK (type IN
+        ('stock', 'etf', 'cw', 'future', 'index', 'bond')),
+    exchange VARCHAR(10) NOT NULL CHECK (exchange IN ('HSX', 'HOSE', 'HNX', 'UPCOM')),
     industry VARCHAR(100),
+    underlying VARCHAR(10),
+    listed_date TIMESTAMPTZ,
+    delisted_date TIMESTAMPTZ,
     is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Historical price bars
+CREATE TABLE IF NOT EXISTS ohlcv_bars (
+    id BIGSERIAL PRIMARY KEY,
+    symbol VARCHAR(10) NOT NULL,
+    resolution VARCHAR(5) NOT NULL,
+    date TIMESTAMPTZ NOT NULL,
+    open DECIMAL(12, 2),
+    high DECIMAL(12, 2),
+    low DECIMAL(12, 2),
+    close DECIMAL(12, 2),
+    volume BIGINT,
+    value DECIMAL(20, 0),
+    source VARCHAR(20),
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Bars on which market data sources disagree beyond tolerance
+CREATE TABLE IF NOT EXISTS bar_mismatches (
+    id BIGSERIAL PRIMARY KEY,
+    symbol VARCHAR(10) NOT NULL,
+    resolution VARCHAR(5) NOT NULL,
+    date TIMESTAMPTZ NOT NULL,
+    field VARCHAR(10) NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    reference VARCHAR(20) NOT NULL,
+    source_value DECIMAL(20, 2),
+    reference_value DECIMAL(20, 2),
+    diff_percent DECIMAL(10, 4),
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Corporate actions used to back-adjust price history
+CREATE TABLE IF NOT EXISTS corporate_actions (
+    id BIGSERIAL PRIMARY KEY,
+    symbol VARCHAR(10) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN
+        ('cash_dividend', 'stock_dividend', 'rights_issue', 'split')),
+    ex_date TIMESTAMPTZ NOT NULL,
+    ratio DECIMAL(12, 6),
+    cash_amount DECIMAL(12, 2),
+    subscription_price DECIMAL(12, 2),
+    description TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Daily foreign investor and proprietary desk trading per stock
+CREATE TABLE IF NOT EXISTS investor_flows (
+    id BIGSERIAL PRIMARY KEY,
+    symbol VARCHAR(10) NOT NULL,
+    date TIMESTAMPTZ NOT NULL,
+    foreign_buy_volume BIGINT,
+    foreign_sell_volume BIGINT,
+    foreign_buy_value DECIMAL(20, 0),
+    foreign_sell_value DECIMAL(20, 0),
+    foreign_room BIGINT,
+    proprietary_buy_volume BIGINT,
+    proprietary_sell_volume BIGINT,
+    proprietary_buy_value DECIMAL(20, 0),
+    proprietary_sell_value DECIMAL(20, 0),
     created_at TIMESTAMPTZ DEFAULT NOW()
 );
 
+-- Quarterly and annual financial statements, amounts in VND
+CREATE TABLE IF NOT EXISTS financial_statements (
+    id BIGSERIAL PRIMARY KEY,
+    symbol VARCHAR(10) NOT NULL,
+    period VARCHAR(10) NOT NULL CHECK (period IN ('quarter', 'year')),
+    year INTEGER NOT NULL,
+    quarter INTEGER NOT NULL DEFAULT 0,
+
+    -- Income statement
+    revenue DECIMAL(20, 0),
+    gross_profit DECIMAL(20, 0),
+    operating_profit DECIMAL(20, 0),
+    net_income DECIMAL(20, 0),
+
+    -- Balance sheet
+    total_assets DECIMAL(20, 0),
+    total_liabilities DECIMAL(20, 0),
+    total_debt DECIMAL(20, 0),
+    equity DECIMAL(20, 0),
+
+    -- Cash flow statement
+    operating_cash_flow DECIMAL(20, 0),
+    investing_cash_flow DECIMAL(20, 0),
+    financing_cash_flow DECIMAL(20, 0),
+    capital_expenditure DECIMAL(20, 0),
+
+    shares_outstanding BIGINT,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Saved formulas of the expression language
+CREATE TABLE IF NOT EXISTS formulas (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL UNIQUE,
+    expression TEXT NOT NULL,
+    description TEXT,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('number', 'bool')),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
 -- Technical analysis results
 CREATE TABLE IF NOT EXISTS technical_analysis (
     id BIGSERIAL PRIMARY KEY,
     symbol VARCHAR(10) NOT NULL,
+    resolution VARCHAR(5) NOT NULL DEFAULT '1D',
+    price_mode VARCHAR(10) NOT NULL DEFAULT 'adjusted',
     timestamp TIMESTAMPTZ NOT NULL,
 
     -- Price data
@@ -54,7 +167,7 @@ CREATE TABLE IF NOT EXISTS technical_analysis (
 
     created_at TIMESTAMPTZ DEFAULT NOW(),
 
-    UNIQUE(symbol, timestamp)
+    UNIQUE(symbol, resolution, price_mode, timestamp)
 );
 
 -- Sentiment analysis results
@@ -123,26 +236,37 @@ CREATE TABLE IF NOT EXISTS daily_reports (
 );
 
 -- Indexes
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ohlcv_symbol_res_date ON ohlcv_bars(symbol, resolution, date);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ca_symbol_type_exdate ON corporate_actions(symbol, type, ex_date);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_flow_symbol_date ON investor_flows(symbol, date);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_fin_symbol_period ON financial_statements(symbol, period, year, quarter);
+CREATE INDEX IF NOT EXISTS idx_instruments_type ON instruments(type);
+CREATE INDEX IF NOT EXISTS idx_mismatch_symbol_date ON bar_mismatches(symbol, resolution, date);
 CREATE INDEX IF NOT EXISTS idx_technical_symbol_time ON technical_analysis(symbol, timestamp DESC);
 CREATE INDEX IF NOT EXISTS idx_sentiment_symbol ON sentiment_analysis(symbol);
 CREATE INDEX IF NOT EXISTS idx_sentiment_analyzed ON sentiment_analysis(analyzed_at DESC);
 CREATE INDEX IF NOT EXISTS idx_forecast_symbol_time ON forecasts(symbol, timestamp DESC);
 
--- Insert some sample Vietnamese stocks
-INSERT INTO stocks (symbol, name, exchange, industry) VALUES
-('VNM', 'Công ty CP Sữa Việt Nam', 'HSX', 'Thực phẩm'),
-('FPT', 'Công ty CP FPT', 'HSX', 'Công nghệ'),
-('VIC', 'Tập đoàn Vingroup', 'HSX', 'Bất động sản'),
-('HPG', 'Tập đoàn Hòa Phát', 'HSX', 'Thép'),
-('VHM', 'Vinhomes', 'HSX', 'Bất động sản'),
-('VCB', 'Vietcombank', 'HSX', 'Ngân hàng'),
-('BID', 'BIDV', 'HSX', 'Ngân hàng'),
-('TCB', 'Techcombank', 'HSX', 'Ngân hàng'),
-('MBB', 'MB Bank', 'HSX', 'Ngân hàng'),
-('VPB', 'VPBank', 'HSX', 'Ngân hàng'),
-('MWG', 'Thế Giới Di Động', 'HSX', 'Bán lẻ'),
-('VNR', 'Tổng Công ty Cổ phần Tái bảo hiểm Quốc gia', 'HSX', 'Bảo hiểm'),
-('SSI', 'SSI Securities', 'HSX', 'Chứng khoán'),
-('VND', 'VNDirect Securities', 'HSX', 'Chứng khoán'),
-('PNJ', 'Phú Nhuận Jewelry', 'HSX', 'Bán lẻ')
+-- Seed the instrument master until the first sync
+INSERT INTO instruments (symbol, name, type, exchange, industry) VALUES
+('VNM', 'Công ty CP Sữa Việt Nam', 'stock', 'HSX', 'Thực phẩm'),
+('FPT', 'Công ty CP FPT', 'stock', 'HSX', 'Công nghệ'),
+('VIC', 'Tập đoàn Vingroup', 'stock', 'HSX', 'Bất động sản'),
+('HPG', 'Tập đoàn Hòa Phát', 'stock', 'HSX', 'Thép'),
+('VHM', 'Vinhomes', 'stock', 'HSX', 'Bất động sản'),
+('VCB', 'Vietcombank', 'stock', 'HSX', 'Ngân hàng'),
+('BID', 'BIDV', 'stock', 'HSX', 'Ngân hàng'),
+('TCB', 'Techcombank', 'stock', 'HSX', 'Ngân hàng'),
+('MBB', 'MB Bank', 'stock', 'HSX', 'Ngân hàng'),
+('VPB', 'VPBank', 'stock', 'HSX', 'Ngân hàng'),
+('MWG', 'Thế Giới Di Động', 'stock', 'HSX', 'Bán lẻ'),
+('VNR', 'Tổng Công ty Cổ phần Tái bảo hiểm Quốc gia', 'stock', 'HSX', 'Bảo hiểm'),
+('SSI', 'SSI Securities', 'stock', 'HSX', 'Chứng khoán'),
+('VND', 'VNDirect Securities', 'stock', 'HSX', 'Chứng khoán'),
+('PNJ', 'Phú Nhuận Jewelry', 'stock', 'HSX', 'Bán lẻ'),
+('VNINDEX', 'VN-Index', 'index', 'HOSE', NULL),
+('VN30', 'VN30-Index', 'index', 'HOSE', NULL),
+('HNXINDEX', 'HNX-Index', 'index', 'HNX', NULL),
+('HNX30', 'HNX30-Index', 'index', 'HNX', NULL),
+('UPCOMINDEX', 'UPCoM-Index', 'index', 'UPCOM', NULL)
 ON CONFLICT (symbol) DO NOTHING;